@@ -0,0 +1,331 @@
+// ©2022 Brandon Moller
+
+/*
+Package blockingqueue provides an implementation of [collections.BlockingQueue] for passing work between goroutines with backpressure.
+
+Elements are stored in a fixed-size ring buffer guarded by a mutex.
+A goroutine that has to wait does so on a channel that is closed when the state of the queue changes, so waiting can be combined with a context in a select.
+Every waiting goroutine is woken when its channel is closed and they then compete for the lock; those that lose go back to waiting.
+
+Iterators and sequences work on a snapshot of the elements taken when they start, in the order in which Pop would return them.
+Queues implement [collections.RemovingIterable]; each removal through a RemovingIterator shifts the elements behind it forward by one and wakes goroutines waiting for space.
+*/
+package blockingqueue
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"sync"
+	"sync/atomic"
+
+	"github.com/bmoller/collections"
+)
+
+type iterator[T comparable] struct {
+	elements      []T
+	index         int
+	modifications uint64
+	queue         *queue[T]
+	removable     bool
+	removed       int // the number of elements before index that have been removed from the queue
+}
+
+func (i *iterator[T]) Next() (element T, err error) {
+	if i.queue.modifications.Load() != i.modifications {
+		return element, collections.ErrConcurrentModification
+	} else if i.index == len(i.elements) {
+		return element, collections.ErrNoMoreItems
+	}
+	element = i.elements[i.index]
+	i.index++
+	i.removable = true
+
+	return element, nil
+}
+
+func (i *iterator[T]) Remove() error {
+	q := i.queue
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	if q.modifications.Load() != i.modifications {
+		return collections.ErrConcurrentModification
+	} else if !i.removable {
+		return collections.ErrNothingToRemove
+	}
+	for j := i.index - 1 - i.removed; j < q.size-1; j++ {
+		q.data[q.index(j)] = q.data[q.index(j+1)]
+	}
+	var zero T
+	q.data[q.index(q.size-1)] = zero
+	q.size--
+	signal(&q.notFull)
+	i.modifications, i.removable = q.modifications.Add(1), false
+	i.removed++
+
+	return nil
+}
+
+type queue[T comparable] struct {
+	closed        bool
+	data          []T
+	dropped       atomic.Int64
+	head          int
+	lock          sync.Mutex
+	modifications atomic.Uint64 // only changed with lock held, but read without it
+	notEmpty      chan struct{}
+	notFull       chan struct{}
+	size          int
+}
+
+/*
+New creates a BlockingQueue that holds at most capacity elements.
+The capacity is at least 1.
+*/
+func New[T comparable](capacity int) collections.BlockingQueue[T] {
+	if capacity < 1 {
+		capacity = 1
+	}
+
+	return &queue[T]{
+		data: make([]T, capacity),
+	}
+}
+
+// signal wakes every goroutine waiting on ch.
+func signal(ch *chan struct{}) {
+	if *ch != nil {
+		close(*ch)
+		*ch = nil
+	}
+}
+
+// wait returns the channel that the next signal on ch will close.
+func wait(ch *chan struct{}) <-chan struct{} {
+	if *ch == nil {
+		*ch = make(chan struct{})
+	}
+
+	return *ch
+}
+
+// index translates a position relative to the front of the queue into an index of the backing slice.
+func (q *queue[T]) index(i int) int {
+	return (q.head + i) % len(q.data)
+}
+
+// peek returns the front element; if the queue is empty and block is set, it also returns a channel to wait on.
+func (q *queue[T]) peek(block bool) (element T, ready <-chan struct{}, err error) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	if q.size == 0 {
+		if q.closed {
+			return element, nil, collections.ErrQueueClosed
+		}
+		if block {
+			ready = wait(&q.notEmpty)
+		}
+		return element, ready, collections.ErrEmptyQueue
+	}
+
+	return q.data[q.head], nil, nil
+}
+
+// pop removes the front element; if the queue is empty and block is set, it also returns a channel to wait on.
+func (q *queue[T]) pop(block bool) (element T, ready <-chan struct{}, err error) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	if q.size == 0 {
+		if q.closed {
+			return element, nil, collections.ErrQueueClosed
+		}
+		if block {
+			ready = wait(&q.notEmpty)
+		}
+		return element, ready, collections.ErrEmptyQueue
+	}
+
+	var zero T
+	element = q.data[q.head]
+	q.data[q.head] = zero
+	q.head = q.index(1)
+	q.modifications.Add(1)
+	q.size--
+	signal(&q.notFull)
+
+	return element, nil, nil
+}
+
+// push adds item at the end; if the queue is full and block is set, it also returns a channel to wait on.
+func (q *queue[T]) push(item T, block bool) (ready <-chan struct{}, err error) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	if q.closed {
+		return nil, collections.ErrQueueClosed
+	}
+	if q.size == len(q.data) {
+		if block {
+			ready = wait(&q.notFull)
+		}
+		return ready, collections.ErrQueueFull
+	}
+
+	q.data[q.index(q.size)] = item
+	q.modifications.Add(1)
+	q.size++
+	signal(&q.notEmpty)
+
+	return nil, nil
+}
+
+func (q *queue[T]) snapshot() []T {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	elements := make([]T, q.size)
+	for i := range elements {
+		elements[i] = q.data[q.index(i)]
+	}
+
+	return elements
+}
+
+func (q *queue[T]) All() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for i, element := range q.snapshot() {
+			if !yield(i, element) {
+				return
+			}
+		}
+	}
+}
+
+func (q *queue[T]) Cap() int {
+	return len(q.data)
+}
+
+func (q *queue[T]) Close() {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	q.closed = true
+	signal(&q.notEmpty)
+	signal(&q.notFull)
+}
+
+func (q *queue[T]) Dropped() int {
+	return int(q.dropped.Load())
+}
+
+func (q *queue[T]) Empty() bool {
+	return q.Size() == 0
+}
+
+func (q *queue[T]) Iterator() collections.Iterator[T] {
+	return q.RemovingIterator().Next
+}
+
+func (q *queue[T]) Peek() (T, error) {
+	element, _, err := q.peek(false)
+
+	return element, err
+}
+
+func (q *queue[T]) PeekCtx(ctx context.Context) (T, error) {
+	for {
+		element, ready, err := q.peek(true)
+		if !errors.Is(err, collections.ErrEmptyQueue) {
+			return element, err
+		}
+
+		select {
+		case <-ready:
+		case <-ctx.Done():
+			return element, ctx.Err()
+		}
+	}
+}
+
+func (q *queue[T]) Pop() (T, error) {
+	return q.TryPop()
+}
+
+func (q *queue[T]) PopCtx(ctx context.Context) (T, error) {
+	for {
+		element, ready, err := q.pop(true)
+		if !errors.Is(err, collections.ErrEmptyQueue) {
+			return element, err
+		}
+
+		select {
+		case <-ready:
+		case <-ctx.Done():
+			return element, ctx.Err()
+		}
+	}
+}
+
+func (q *queue[T]) Push(item T) {
+	if err := q.TryPush(item); err != nil {
+		q.dropped.Add(1)
+	}
+}
+
+func (q *queue[T]) PushCtx(ctx context.Context, item T) error {
+	for {
+		ready, err := q.push(item, true)
+		if !errors.Is(err, collections.ErrQueueFull) {
+			return err
+		}
+
+		select {
+		case <-ready:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+func (q *queue[T]) RemovingIterator() collections.RemovingIterator[T] {
+	// a sequence always finishes its snapshot, but an Iterator stops once anything is pushed or popped
+	modifications := q.modifications.Load()
+
+	return &iterator[T]{
+		elements:      q.snapshot(),
+		modifications: modifications,
+		queue:         q,
+	}
+}
+
+func (q *queue[T]) Size() int {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	return q.size
+}
+
+func (q *queue[T]) TryPop() (element T, err error) {
+	element, _, err = q.pop(false)
+
+	return element, err
+}
+
+func (q *queue[T]) TryPush(item T) error {
+	_, err := q.push(item, false)
+
+	return err
+}
+
+func (q *queue[T]) Values() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, element := range q.snapshot() {
+			if !yield(element) {
+				return
+			}
+		}
+	}
+}
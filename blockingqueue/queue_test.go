@@ -0,0 +1,394 @@
+// ©2022 Brandon Moller
+
+package blockingqueue_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bmoller/collections"
+	"github.com/bmoller/collections/blockingqueue"
+	"github.com/bmoller/collections/collectionstest"
+)
+
+func FuzzQueue(f *testing.F) {
+	collectionstest.FuzzQueue(f, func() collections.Queue[int] { return blockingqueue.New[int](1000) })
+}
+
+func TestQueue(t *testing.T) {
+	collectionstest.TestQueue(t, func() collections.Queue[int] { return blockingqueue.New[int](1000) })
+}
+
+func TestQueueAll(t *testing.T) {
+	queue := blockingqueue.New[int](1000)
+	for i := 0; i < 1000; i++ {
+		queue.Push(i)
+	}
+
+	expected := 0
+	for i, element := range queue.All() {
+		if i != expected {
+			t.Fatalf("expected index %d but got %d", expected, i)
+		} else if element != i {
+			t.Fatalf("expected element with value %d but got %d", i, element)
+		}
+		queue.Pop()
+		expected++
+	}
+	if expected != 1000 {
+		t.Fatalf("expected %d elements from All but got %d", 1000, expected)
+	}
+
+	queue.Push(0)
+	for range queue.All() {
+		break
+	}
+}
+
+func TestQueueCap(t *testing.T) {
+	if queue := blockingqueue.New[int](10); queue.Cap() != 10 {
+		t.Fatalf("expected capacity %d but got %d", 10, queue.Cap())
+	}
+	if queue := blockingqueue.New[int](0); queue.Cap() != 1 {
+		t.Fatalf("expected minimum capacity %d but got %d", 1, queue.Cap())
+	}
+}
+
+func TestQueueClose(t *testing.T) {
+	queue := blockingqueue.New[int](10)
+	for i := 0; i < 5; i++ {
+		queue.Push(i)
+	}
+	queue.Close()
+	queue.Close()
+
+	if err := queue.TryPush(5); err == nil || !errors.Is(err, collections.ErrQueueClosed) {
+		t.Fatalf("expected ErrQueueClosed from TryPush on closed queue but got: %v", err)
+	}
+	if err := queue.PushCtx(context.Background(), 5); err == nil || !errors.Is(err, collections.ErrQueueClosed) {
+		t.Fatalf("expected ErrQueueClosed from PushCtx on closed queue but got: %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		if element, err := queue.PopCtx(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if element != i {
+			t.Fatalf("expected element with value %d but got %d", i, element)
+		}
+	}
+	if _, err := queue.PopCtx(context.Background()); err == nil || !errors.Is(err, collections.ErrQueueClosed) {
+		t.Fatalf("expected ErrQueueClosed from PopCtx on closed and empty queue but got: %v", err)
+	}
+	if _, err := queue.TryPop(); err == nil || !errors.Is(err, collections.ErrQueueClosed) {
+		t.Fatalf("expected ErrQueueClosed from TryPop on closed and empty queue but got: %v", err)
+	}
+	if _, err := queue.Peek(); err == nil || !errors.Is(err, collections.ErrQueueClosed) {
+		t.Fatalf("expected ErrQueueClosed from Peek on closed and empty queue but got: %v", err)
+	}
+}
+
+func TestQueueCloseWakesWaiters(t *testing.T) {
+	empty := blockingqueue.New[int](1)
+	full := blockingqueue.New[int](1)
+	full.Push(0)
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 20)
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_, err := empty.PopCtx(context.Background())
+			errs <- err
+		}()
+		go func() {
+			defer wg.Done()
+			errs <- full.PushCtx(context.Background(), 1)
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	empty.Close()
+	full.Close()
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err == nil || !errors.Is(err, collections.ErrQueueClosed) {
+			t.Fatalf("expected ErrQueueClosed from waiting call but got: %v", err)
+		}
+	}
+}
+
+func TestQueueIterator(t *testing.T) {
+	queue := blockingqueue.New[int](1000)
+	if _, err := queue.Iterator()(); err == nil || !errors.Is(err, collections.ErrNoMoreItems) {
+		t.Fatalf("expected ErrNoMoreItems from Iterator on empty queue but got: %v", err)
+	}
+
+	for i := 0; i < 1000; i++ {
+		queue.Push(i)
+	}
+	itr := queue.Iterator()
+	for i := 0; i < 500; i++ {
+		if element, err := itr(); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if element != i {
+			t.Fatalf("expected element with value %d but got %d", i, element)
+		}
+	}
+	queue.TryPop()
+	if _, err := itr(); err == nil || !errors.Is(err, collections.ErrConcurrentModification) {
+		t.Fatalf("expected ErrConcurrentModification after TryPop but got: %v", err)
+	}
+
+	// a push that fails leaves the queue unchanged
+	itr = queue.Iterator()
+	itr()
+	queue.Close()
+	if err := queue.TryPush(0); err == nil || !errors.Is(err, collections.ErrQueueClosed) {
+		t.Fatalf("expected ErrQueueClosed from TryPush but got: %v", err)
+	}
+	if element, err := itr(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if element != 2 {
+		t.Fatalf("expected element with value %d but got %d", 2, element)
+	}
+}
+
+func TestQueuePeekCtx(t *testing.T) {
+	queue := blockingqueue.New[int](1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := queue.PeekCtx(ctx); err == nil || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected DeadlineExceeded from PeekCtx on empty queue but got: %v", err)
+	}
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		queue.Push(1)
+	}()
+	if element, err := queue.PeekCtx(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if element != 1 {
+		t.Fatalf("expected element with value %d but got %d", 1, element)
+	} else if queue.Size() != 1 {
+		t.Fatalf("expected PeekCtx to leave queue size %d but got %d", 1, queue.Size())
+	}
+
+	queue.Pop()
+	queue.Close()
+	if _, err := queue.PeekCtx(context.Background()); err == nil || !errors.Is(err, collections.ErrQueueClosed) {
+		t.Fatalf("expected ErrQueueClosed from PeekCtx on closed and empty queue but got: %v", err)
+	}
+}
+
+func TestQueuePopCtx(t *testing.T) {
+	queue := blockingqueue.New[int](1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := queue.PopCtx(ctx); err == nil || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected DeadlineExceeded from PopCtx on empty queue but got: %v", err)
+	}
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		queue.Push(1)
+	}()
+	if element, err := queue.PopCtx(context.Background()); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if element != 1 {
+		t.Fatalf("expected element with value %d but got %d", 1, element)
+	}
+
+	ctx, cancel = context.WithCancel(context.Background())
+	cancel()
+	if _, err := queue.PopCtx(ctx); err == nil || !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected Canceled from PopCtx on empty queue but got: %v", err)
+	}
+}
+
+func TestQueuePush(t *testing.T) {
+	queue := blockingqueue.New[int](1)
+	queue.Push(0)
+	queue.Push(1)
+	if element, _ := queue.Peek(); element != 0 {
+		t.Fatalf("expected element with value %d but got %d", 0, element)
+	} else if queue.Size() != 1 {
+		t.Fatalf("expected Push on a full queue to leave size %d but got %d", 1, queue.Size())
+	} else if queue.Dropped() != 1 {
+		t.Fatalf("expected %d dropped element but got %d", 1, queue.Dropped())
+	}
+
+	queue.Pop()
+	queue.Close()
+	queue.Push(2)
+	if !queue.Empty() {
+		t.Fatal("expected Push on a closed queue to add nothing")
+	} else if queue.Dropped() != 2 {
+		t.Fatalf("expected %d dropped elements but got %d", 2, queue.Dropped())
+	}
+}
+
+func TestQueuePushCtx(t *testing.T) {
+	queue := blockingqueue.New[int](1)
+	if err := queue.PushCtx(context.Background(), 0); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := queue.PushCtx(ctx, 1); err == nil || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected DeadlineExceeded from PushCtx on full queue but got: %v", err)
+	}
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		queue.Pop()
+	}()
+	if err := queue.PushCtx(context.Background(), 1); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if element, _ := queue.Peek(); element != 1 {
+		t.Fatalf("expected element with value %d but got %d", 1, element)
+	}
+}
+
+func TestQueueRemovingIterator(t *testing.T) {
+	queue := blockingqueue.New[int](2)
+	queue.Push(0)
+	queue.Push(1)
+
+	// removing an element through an iterator makes space for a waiting PushCtx
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		itr := queue.(collections.RemovingIterable[int]).RemovingIterator()
+		itr.Next()
+		itr.Remove()
+	}()
+	if err := queue.PushCtx(context.Background(), 2); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	for _, expected := range []int{1, 2} {
+		if element, err := queue.TryPop(); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if element != expected {
+			t.Fatalf("expected element with value %d but got %d", expected, element)
+		}
+	}
+}
+
+func TestQueueTryPop(t *testing.T) {
+	queue := blockingqueue.New[int](1)
+	if _, err := queue.TryPop(); err == nil || !errors.Is(err, collections.ErrEmptyQueue) {
+		t.Fatalf("expected ErrEmptyQueue from TryPop on empty queue but got: %v", err)
+	}
+	queue.Push(1)
+	if element, err := queue.TryPop(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if element != 1 {
+		t.Fatalf("expected element with value %d but got %d", 1, element)
+	}
+}
+
+func TestQueueTryPush(t *testing.T) {
+	queue := blockingqueue.New[int](10)
+	for i := 0; i < 10; i++ {
+		if err := queue.TryPush(i); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	if err := queue.TryPush(10); err == nil || !errors.Is(err, collections.ErrQueueFull) {
+		t.Fatalf("expected ErrQueueFull from TryPush on full queue but got: %v", err)
+	}
+	if queue.Size() != 10 {
+		t.Fatalf("expected queue size %d but got %d", 10, queue.Size())
+	}
+}
+
+func TestQueueValues(t *testing.T) {
+	queue := blockingqueue.New[int](1000)
+	for i := 0; i < 1000; i++ {
+		queue.Push(i)
+	}
+
+	expected := 0
+	for element := range queue.Values() {
+		if element != expected {
+			t.Fatalf("expected element with value %d but got %d", expected, element)
+		}
+		queue.Pop()
+		expected++
+	}
+	if expected != 1000 {
+		t.Fatalf("expected %d elements from Values but got %d", 1000, expected)
+	}
+
+	queue.Push(0)
+	for range queue.Values() {
+		break
+	}
+}
+
+func TestQueueConcurrent(t *testing.T) {
+	const (
+		producers   = 8
+		consumers   = 8
+		perProducer = 1000
+	)
+	queue := blockingqueue.New[int](10)
+
+	var (
+		producing sync.WaitGroup
+		consuming sync.WaitGroup
+		popped    [consumers][]int
+	)
+	for p := 0; p < producers; p++ {
+		producing.Add(1)
+		go func() {
+			defer producing.Done()
+			for i := 0; i < perProducer; i++ {
+				if err := queue.PushCtx(context.Background(), p*perProducer+i); err != nil {
+					t.Errorf("unexpected error: %s", err)
+				}
+			}
+		}()
+	}
+	for c := 0; c < consumers; c++ {
+		consuming.Add(1)
+		go func() {
+			defer consuming.Done()
+			for {
+				element, err := queue.PopCtx(context.Background())
+				if errors.Is(err, collections.ErrQueueClosed) {
+					return
+				}
+				popped[c] = append(popped[c], element)
+				if queue.Size() > queue.Cap() {
+					t.Errorf("queue size %d exceeds capacity %d", queue.Size(), queue.Cap())
+				}
+			}
+		}()
+	}
+	producing.Wait()
+	queue.Close()
+	consuming.Wait()
+
+	seen := make([]bool, producers*perProducer)
+	for _, elements := range popped {
+		for _, element := range elements {
+			if seen[element] {
+				t.Fatalf("element %d was returned more than once", element)
+			}
+			seen[element] = true
+		}
+	}
+	for element, ok := range seen {
+		if !ok {
+			t.Fatalf("element %d was lost", element)
+		}
+	}
+}
@@ -0,0 +1,229 @@
+// ©2022 Brandon Moller
+
+/*
+Package bridge connects collections to goroutine pipelines built from channels.
+
+FeedQueue and FeedStack start a goroutine that receives from a channel and pushes every element into a collection.
+DrainQueue and DrainStack do the reverse, starting a goroutine that delivers the elements of a collection on a channel as they become available.
+Each returns a Transfer that reports how many elements have been moved and why the goroutine stopped.
+A drain from a BlockingQueue waits for new elements with PopCtx; any other collection is checked while it is empty, at intervals that grow from 1ms to 100ms.
+
+The collection is used from the new goroutine while the caller continues to use it, so it must be safe for concurrent use, such as a collection from the concurrentqueue, concurrentstack or blockingqueue packages, or one wrapped by the synchronized package.
+*/
+package bridge
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"time"
+
+	"github.com/bmoller/collections"
+)
+
+// limits of the interval at which a drain checks an empty collection that cannot signal new elements
+const (
+	minPollInterval = time.Millisecond
+	maxPollInterval = 100 * time.Millisecond
+)
+
+// source is the part of a Queue or Stack needed to remove elements from it and to return one that could not be delivered.
+type source[T comparable] interface {
+	Pop() (T, error)
+	Push(T)
+}
+
+/*
+A Transfer tracks a goroutine started by one of the Feed or Drain functions.
+*/
+type Transfer struct {
+	count atomic.Int64
+	done  chan struct{}
+	err   error
+}
+
+func newTransfer() *Transfer {
+	return &Transfer{
+		done: make(chan struct{}),
+	}
+}
+
+/*
+Count returns the number of elements moved so far.
+*/
+func (t *Transfer) Count() int {
+	return int(t.count.Load())
+}
+
+/*
+Done returns a channel that is closed when the goroutine has stopped.
+*/
+func (t *Transfer) Done() <-chan struct{} {
+	return t.done
+}
+
+/*
+Err returns nil until the goroutine stops.
+It then returns the reason: nil if the input ran out, the context's error if it was cancelled, or the error that stopped the transfer.
+*/
+func (t *Transfer) Err() error {
+	select {
+	case <-t.done:
+		return t.err
+	default:
+		return nil
+	}
+}
+
+/*
+Wait blocks until the goroutine stops and returns the final count and the value of Err.
+*/
+func (t *Transfer) Wait() (int, error) {
+	<-t.done
+
+	return t.Count(), t.err
+}
+
+func drain[T comparable](ctx context.Context, from source[T]) (<-chan T, *Transfer) {
+	out := make(chan T)
+	transfer := newTransfer()
+	next := poll(from)
+	restore := func(element T) error {
+		from.Push(element)
+		return nil
+	}
+	if blocking, ok := from.(collections.BlockingQueue[T]); ok {
+		next = blocking.PopCtx
+		restore = blocking.TryPush
+	}
+
+	go func() {
+		defer close(transfer.done)
+		defer close(out)
+
+		for {
+			element, err := next(ctx)
+			if errors.Is(err, collections.ErrQueueClosed) {
+				return
+			} else if err != nil {
+				transfer.err = err
+				return
+			}
+
+			select {
+			case out <- element:
+				transfer.count.Add(1)
+			case <-ctx.Done():
+				transfer.err = ctx.Err()
+				if err := restore(element); err != nil {
+					transfer.err = errors.Join(transfer.err, err)
+				}
+				return
+			}
+		}
+	}()
+
+	return out, transfer
+}
+
+func feed[T comparable](ctx context.Context, in <-chan T, push func(T) error) *Transfer {
+	transfer := newTransfer()
+
+	go func() {
+		defer close(transfer.done)
+
+		for {
+			select {
+			case element, ok := <-in:
+				if !ok {
+					return
+				}
+				if err := push(element); err != nil {
+					transfer.err = err
+					return
+				}
+				transfer.count.Add(1)
+			case <-ctx.Done():
+				transfer.err = ctx.Err()
+				return
+			}
+		}
+	}()
+
+	return transfer
+}
+
+// poll returns a function that pops from from until it has an element, checking less often the longer it stays empty.
+func poll[T comparable](from source[T]) func(context.Context) (T, error) {
+	return func(ctx context.Context) (T, error) {
+		interval := minPollInterval
+		for {
+			element, err := from.Pop()
+			if err == nil || errors.Is(err, collections.ErrQueueClosed) {
+				return element, err
+			}
+
+			timer := time.NewTimer(interval)
+			select {
+			case <-timer.C:
+				interval = min(interval*2, maxPollInterval)
+			case <-ctx.Done():
+				timer.Stop()
+				return element, ctx.Err()
+			}
+		}
+	}
+}
+
+/*
+DrainQueue returns a channel on which the elements of q are delivered in the order Pop would return them.
+Each element is popped before it is offered on the channel, so other goroutines may push to and pop from q while it runs without any element being delivered twice.
+If ctx is cancelled while an element is waiting to be received, it is pushed back into q, which puts it at the end of a FIFO Queue.
+When q is a BlockingQueue it is returned with TryPush, and should that fail because q is full or closed, the element is lost and Err reports the TryPush error along with the context's.
+
+The channel is closed when ctx is cancelled, or when q is a BlockingQueue that has been closed and emptied.
+*/
+func DrainQueue[T comparable](ctx context.Context, q collections.Queue[T]) (<-chan T, *Transfer) {
+	return drain[T](ctx, q)
+}
+
+/*
+DrainStack returns a channel on which the elements of s are delivered in the order Pop would return them.
+Each element is popped before it is offered on the channel, so other goroutines may push to and pop from s while it runs without any element being delivered twice.
+If ctx is cancelled while an element is waiting to be received, it is pushed back onto s.
+
+The channel is closed when ctx is cancelled.
+*/
+func DrainStack[T comparable](ctx context.Context, s collections.Stack[T]) (<-chan T, *Transfer) {
+	return drain[T](ctx, s)
+}
+
+/*
+FeedQueue pushes every element received from in into q, until in is closed or ctx is cancelled.
+
+If q is a BlockingQueue then the goroutine waits for space with PushCtx, and stops with ErrQueueClosed if q is closed.
+Any other Queue is given each element with Push, so a BlockingQueue hidden behind a wrapper discards the elements that do not fit, as its Push does.
+An element that was received but could not be pushed because the goroutine stopped is not counted and is discarded.
+*/
+func FeedQueue[T comparable](ctx context.Context, in <-chan T, q collections.Queue[T]) *Transfer {
+	if blocking, ok := q.(collections.BlockingQueue[T]); ok {
+		return feed(ctx, in, func(item T) error {
+			return blocking.PushCtx(ctx, item)
+		})
+	}
+
+	return feed(ctx, in, func(item T) error {
+		q.Push(item)
+		return nil
+	})
+}
+
+/*
+FeedStack pushes every element received from in onto s, until in is closed or ctx is cancelled.
+*/
+func FeedStack[T comparable](ctx context.Context, in <-chan T, s collections.Stack[T]) *Transfer {
+	return feed(ctx, in, func(item T) error {
+		s.Push(item)
+		return nil
+	})
+}
@@ -0,0 +1,324 @@
+// ©2022 Brandon Moller
+
+package bridge_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/bmoller/collections"
+	"github.com/bmoller/collections/blockingqueue"
+	"github.com/bmoller/collections/bridge"
+	"github.com/bmoller/collections/concurrentqueue"
+	"github.com/bmoller/collections/concurrentstack"
+	"github.com/bmoller/collections/priorityqueue"
+	"github.com/bmoller/collections/synchronized"
+)
+
+func TestDrainQueue(t *testing.T) {
+	queue := concurrentqueue.New[int]()
+	for i := 0; i < 1000; i++ {
+		queue.Push(i)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	out, transfer := bridge.DrainQueue(ctx, queue)
+	if err := transfer.Err(); err != nil {
+		t.Fatalf("expected no error from running transfer but got: %s", err)
+	}
+	for i := 0; i < 1000; i++ {
+		if element := <-out; element != i {
+			t.Fatalf("expected element with value %d but got %d", i, element)
+		}
+	}
+
+	// elements pushed later are delivered once they arrive
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		queue.Push(1000)
+	}()
+	if element := <-out; element != 1000 {
+		t.Fatalf("expected element with value %d but got %d", 1000, element)
+	}
+
+	queue.Push(1001)
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	if _, ok := <-out; ok {
+		t.Fatal("expected channel to be closed after cancellation")
+	}
+	if count, err := transfer.Wait(); err == nil || !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected Canceled from cancelled transfer but got: %v", err)
+	} else if count != 1001 {
+		t.Fatalf("expected %d elements to be transferred but got %d", 1001, count)
+	}
+	if element, err := queue.Pop(); err != nil || element != 1001 {
+		t.Fatalf("expected undelivered element %d to remain in queue but got %d, %v", 1001, element, err)
+	}
+}
+
+func TestDrainQueueClosed(t *testing.T) {
+	queue := blockingqueue.New[int](10)
+	for i := 0; i < 10; i++ {
+		queue.Push(i)
+	}
+	queue.Close()
+
+	out, transfer := bridge.DrainQueue(context.Background(), queue)
+	expected := 0
+	for element := range out {
+		if element != expected {
+			t.Fatalf("expected element with value %d but got %d", expected, element)
+		}
+		expected++
+	}
+	if count, err := transfer.Wait(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if count != 10 {
+		t.Fatalf("expected %d elements to be transferred but got %d", 10, count)
+	}
+}
+
+func TestDrainQueueBlocking(t *testing.T) {
+	queue := blockingqueue.New[int](10)
+	ctx, cancel := context.WithCancel(context.Background())
+	out, transfer := bridge.DrainQueue(ctx, queue)
+
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		queue.Push(0)
+	}()
+	if element := <-out; element != 0 {
+		t.Fatalf("expected element with value %d but got %d", 0, element)
+	}
+
+	cancel()
+	if count, err := transfer.Wait(); err == nil || !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected Canceled from transfer cancelled while waiting but got: %v", err)
+	} else if count != 1 {
+		t.Fatalf("expected %d elements to be transferred but got %d", 1, count)
+	}
+}
+
+func TestDrainQueueConcurrentProducer(t *testing.T) {
+	queue := synchronized.Queue(priorityqueue.New(func(a, b int) bool { return a < b }))
+	ctx, cancel := context.WithCancel(context.Background())
+	out, transfer := bridge.DrainQueue(ctx, queue)
+
+	go func() {
+		for i := 0; i < 1000; i++ {
+			queue.Push(i)
+		}
+	}()
+	checkDelivered(t, out, 1000)
+
+	cancel()
+	if count, err := transfer.Wait(); err == nil || !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected Canceled from cancelled transfer but got: %v", err)
+	} else if count != 1000 {
+		t.Fatalf("expected %d elements to be transferred but got %d", 1000, count)
+	}
+	if queue.Size() != 0 {
+		t.Fatalf("expected queue to be empty after transfer but got size %d", queue.Size())
+	}
+}
+
+func TestDrainQueueRestoreBlocking(t *testing.T) {
+	queue := blockingqueue.New[int](10)
+	queue.Push(0)
+	ctx, cancel := context.WithCancel(context.Background())
+	_, transfer := bridge.DrainQueue(ctx, queue)
+
+	// the drain holds 0 until cancellation returns it to the queue
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	if count, err := transfer.Wait(); err == nil || !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected Canceled from cancelled transfer but got: %v", err)
+	} else if count != 0 {
+		t.Fatalf("expected %d elements to be transferred but got %d", 0, count)
+	}
+	if element, err := queue.TryPop(); err != nil || element != 0 {
+		t.Fatalf("expected undelivered element %d to be returned to queue but got %d, %v", 0, element, err)
+	}
+}
+
+func TestDrainQueueRestoreBlockingFull(t *testing.T) {
+	queue := blockingqueue.New[int](1)
+	queue.Push(0)
+	ctx, cancel := context.WithCancel(context.Background())
+	_, transfer := bridge.DrainQueue(ctx, queue)
+
+	// the drain holds 0 while the space it left is taken
+	time.Sleep(10 * time.Millisecond)
+	queue.Push(1)
+	cancel()
+	if _, err := transfer.Wait(); err == nil || !errors.Is(err, context.Canceled) || !errors.Is(err, collections.ErrQueueFull) {
+		t.Fatalf("expected Canceled and ErrQueueFull from cancelled transfer but got: %v", err)
+	}
+}
+
+func TestDrainStack(t *testing.T) {
+	stack := concurrentstack.New[int]()
+	for i := 0; i < 1000; i++ {
+		stack.Push(i)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	out, transfer := bridge.DrainStack(ctx, stack)
+	for i := 999; i > -1; i-- {
+		if element := <-out; element != i {
+			t.Fatalf("expected element with value %d but got %d", i, element)
+		}
+	}
+	<-transfer.Done()
+	if _, ok := <-out; ok {
+		t.Fatal("expected channel to be closed after deadline")
+	}
+	if err := transfer.Err(); err == nil || !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected DeadlineExceeded from transfer but got: %v", err)
+	}
+	if transfer.Count() != 1000 {
+		t.Fatalf("expected %d elements to be transferred but got %d", 1000, transfer.Count())
+	}
+}
+
+func TestDrainStackConcurrentProducer(t *testing.T) {
+	stack := concurrentstack.New[int]()
+	ctx, cancel := context.WithCancel(context.Background())
+	out, transfer := bridge.DrainStack(ctx, stack)
+
+	go func() {
+		for i := 0; i < 1000; i++ {
+			stack.Push(i)
+		}
+	}()
+	checkDelivered(t, out, 1000)
+
+	cancel()
+	if count, err := transfer.Wait(); err == nil || !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected Canceled from cancelled transfer but got: %v", err)
+	} else if count != 1000 {
+		t.Fatalf("expected %d elements to be transferred but got %d", 1000, count)
+	}
+	if stack.Size() != 0 {
+		t.Fatalf("expected stack to be empty after transfer but got size %d", stack.Size())
+	}
+}
+
+func TestDrainStackRestore(t *testing.T) {
+	stack := concurrentstack.New[int]()
+	stack.Push(0)
+	ctx, cancel := context.WithCancel(context.Background())
+	_, transfer := bridge.DrainStack(ctx, stack)
+
+	// the drain holds 0 until cancellation returns it to the stack
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	if _, err := transfer.Wait(); err == nil || !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected Canceled from cancelled transfer but got: %v", err)
+	}
+	if element, err := stack.Pop(); err != nil || element != 0 {
+		t.Fatalf("expected undelivered element %d to be returned to stack but got %d, %v", 0, element, err)
+	}
+}
+
+func TestFeedQueue(t *testing.T) {
+	queue := concurrentqueue.New[int]()
+	in := make(chan int)
+
+	transfer := bridge.FeedQueue(context.Background(), in, queue)
+	for i := 0; i < 1000; i++ {
+		in <- i
+	}
+	close(in)
+	if count, err := transfer.Wait(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if count != 1000 {
+		t.Fatalf("expected %d elements to be transferred but got %d", 1000, count)
+	}
+	for i := 0; i < 1000; i++ {
+		if element, err := queue.Pop(); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if element != i {
+			t.Fatalf("expected element with value %d but got %d", i, element)
+		}
+	}
+}
+
+func TestFeedQueueBlocking(t *testing.T) {
+	queue := blockingqueue.New[int](10)
+	in := make(chan int)
+
+	transfer := bridge.FeedQueue(context.Background(), in, queue)
+	go func() {
+		for i := 0; i < 1000; i++ {
+			in <- i
+		}
+		close(in)
+	}()
+	for i := 0; i < 1000; i++ {
+		if element, err := queue.PopCtx(context.Background()); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if element != i {
+			t.Fatalf("expected element with value %d but got %d", i, element)
+		}
+	}
+	if count, err := transfer.Wait(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if count != 1000 {
+		t.Fatalf("expected %d elements to be transferred but got %d", 1000, count)
+	}
+
+	queue.Close()
+	in = make(chan int, 1)
+	in <- 0
+	transfer = bridge.FeedQueue(context.Background(), in, queue)
+	if count, err := transfer.Wait(); err == nil || !errors.Is(err, collections.ErrQueueClosed) {
+		t.Fatalf("expected ErrQueueClosed from transfer into closed queue but got: %v", err)
+	} else if count != 0 {
+		t.Fatalf("expected %d elements to be transferred but got %d", 0, count)
+	}
+}
+
+func TestFeedStack(t *testing.T) {
+	stack := concurrentstack.New[int]()
+	in := make(chan int)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	transfer := bridge.FeedStack(ctx, in, stack)
+	for i := 0; i < 1000; i++ {
+		in <- i
+	}
+	cancel()
+	if count, err := transfer.Wait(); err == nil || !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected Canceled from cancelled transfer but got: %v", err)
+	} else if count != 1000 {
+		t.Fatalf("expected %d elements to be transferred but got %d", 1000, count)
+	}
+	for i := 999; i > -1; i-- {
+		if element, err := stack.Pop(); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if element != i {
+			t.Fatalf("expected element with value %d but got %d", i, element)
+		}
+	}
+}
+
+// checkDelivered receives n elements from out and fails the test unless they are the values 0 through n-1, each delivered once.
+func checkDelivered(t *testing.T, out <-chan int, n int) {
+	t.Helper()
+
+	seen := make([]bool, n)
+	for i := 0; i < n; i++ {
+		element := <-out
+		if element < 0 || element >= n {
+			t.Fatalf("expected element between 0 and %d but got %d", n-1, element)
+		} else if seen[element] {
+			t.Fatalf("expected element %d to be delivered once but it was repeated", element)
+		}
+		seen[element] = true
+	}
+}
@@ -5,12 +5,18 @@ Package collections provides basic data structures for a variety of purposes.
 
 Implementations are not guaranteed to be stable, but all functionality is exposed via interfaces for a stable API.
 Some interfaces have multiple implementations; consideration should be given to how the structure will be used when making a choice.
+
+Implementations that support JSON or binary encoding decode into a collection created by their package, replacing its contents.
+Because the concrete types are unexported, a variable or struct field of one of these interfaces must be set with a constructor such as New before decoding.
+[encoding/json] cannot choose an implementation for a nil interface and returns an [*encoding/json.UnmarshalTypeError] instead.
 */
 package collections
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"iter"
 )
 
 // Collection
@@ -23,46 +29,199 @@ type Collection[T comparable] interface {
 	Size() int   // Returns the number of values in the Collection
 }
 
+// Deque
+
+/*
+A Deque is a double-ended queue; elements can be added to, inspected at, and removed from either end.
+A Deque is also a Queue, where Push adds to the back and Peek and Pop operate on the front.
+AsStack returns a view of the same elements as a Stack, where the front of the Deque is the top of the Stack.
+
+Iterators returned by a Deque produce its elements from front to back.
+Backward produces the same index and element pairs as All, but in reverse order.
+*/
+type Deque[T comparable] interface {
+	Queue[T]
+
+	AsStack() Stack[T]
+	Backward() iter.Seq2[int, T]
+	PeekBack() (T, error)
+	PeekFront() (T, error)
+	PopBack() (T, error)
+	PopFront() (T, error)
+	PushBack(T)
+	PushFront(T)
+}
+
+type emptyDequeError struct{}
+
+func (emptyDequeError) Error() string {
+	return "deque is empty"
+}
+
+func (emptyDequeError) Is(target error) bool {
+	return target == ErrEmptyQueue || target == ErrEmptyStack
+}
+
+/*
+ErrEmptyDeque is returned when a Peek or Pop method is called on a Deque with no elements.
+As a Deque can stand in for a Queue or a Stack, ErrEmptyDeque also matches ErrEmptyQueue and ErrEmptyStack when checked with [errors.Is].
+*/
+var ErrEmptyDeque error = emptyDequeError{}
+
+// Encoding
+
+/*
+ErrInvalidEncoding is returned when decoding binary data that was not produced by the same kind of collection, or that is truncated or corrupt.
+If the data is rejected after its header was accepted, the collection holds the elements decoded before the error.
+*/
+var ErrInvalidEncoding = errors.New("data is not a valid encoding of this collection")
+
+/*
+ErrUnsupportedVersion is returned when decoding binary data written in a format version that this package does not understand.
+*/
+var ErrUnsupportedVersion = errors.New("unsupported encoding version")
+
 // Iterable
 
 /*
 An Iterable returns an Iterator to navigate its elements.
+Its elements are also available as range-over-func sequences via All and Values.
+All pairs each element with its position in the iteration order, starting from 0.
+Both sequences produce elements in the same order as the Iterator.
 */
 type Iterable[T comparable] interface {
+	All() iter.Seq2[int, T]
 	Iterator() Iterator[T]
+	Values() iter.Seq[T]
 }
 
 /*
 An Iterator can be used to loop through all of the elements of the returning Iterable.
+
+Iterators fail fast: once the Iterable is modified after the Iterator was created, every later call returns ErrConcurrentModification instead of an element.
+A modification is any call that adds, removes, replaces or reorders elements, including through another view of the same elements or a wrapper around it.
+Methods that only read do not invalidate an Iterator, and neither do calls that return an error or that add to or remove from a Set without changing its elements.
+The one exception is removing an element through a RemovingIterator, which leaves that RemovingIterator valid but still invalidates every other Iterator.
+
+Sequences returned by All and Values are not affected; each implementation documents what they produce if the Iterable is modified during a range loop.
 */
 type Iterator[T comparable] func() (T, error)
 
+/*
+A RemovingIterable returns a RemovingIterator, for callers that need to remove elements as they iterate over them.
+Every List, Queue, Stack and Set in this module is a RemovingIterable.
+*/
+type RemovingIterable[T comparable] interface {
+	RemovingIterator() RemovingIterator[T]
+}
+
+/*
+A RemovingIterator is an Iterator that can also remove the element it returned last.
+Next behaves as calling an Iterator does, including failing fast once the Iterable is modified.
+Remove takes the element most recently returned by Next out of the Iterable, and then lets Next carry on with the element that followed it.
+It returns ErrNothingToRemove if Next has not returned an element since the RemovingIterator was created or since the last Remove.
+If the Iterable was modified by any other means, Remove returns ErrConcurrentModification and removes nothing.
+*/
+type RemovingIterator[T comparable] interface {
+	Next() (T, error)
+	Remove() error
+}
+
+/*
+ErrConcurrentModification is returned by an Iterator whose Iterable was modified after the Iterator was created.
+The Iterator cannot continue, but a new one can be created to iterate over the modified Iterable.
+*/
+var ErrConcurrentModification = errors.New("collection was modified during iteration")
+
 /*
 ErrNoMoreItems indicates that an iterator has returned all of its items.
 Callers should be prepared for this error and treat it as an expected state.
 */
 var ErrNoMoreItems = errors.New("no more items to return")
 
+/*
+ErrNothingToRemove is returned by Remove on a RemovingIterator whose last element returned by Next has already been removed, or that has not returned one yet.
+*/
+var ErrNothingToRemove = errors.New("iterator has no element to remove")
+
+/*
+ToSeq adapts an Iterator into an [iter.Seq].
+The sequence ends when the Iterator returns ErrNoMoreItems; as the Iterator is consumed, the sequence can only be ranged over once.
+A sequence cannot report an error, so if the Iterator returns any other error, such as ErrConcurrentModification, the sequence panics with it.
+*/
+func ToSeq[T comparable](itr Iterator[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for {
+			element, err := itr()
+			if errors.Is(err, ErrNoMoreItems) {
+				return
+			} else if err != nil {
+				panic(err)
+			}
+			if !yield(element) {
+				return
+			}
+		}
+	}
+}
+
+/*
+FromSeq adapts an [iter.Seq] into an Iterator, which returns ErrNoMoreItems once the sequence is exhausted.
+The returned stop function releases the resources held by the Iterator.
+It must be called if the Iterator is abandoned before returning ErrNoMoreItems, and is safe to call more than once.
+*/
+func FromSeq[T comparable](seq iter.Seq[T]) (Iterator[T], func()) {
+	next, stop := iter.Pull(seq)
+
+	return func() (T, error) {
+		element, ok := next()
+		if !ok {
+			return element, ErrNoMoreItems
+		}
+		return element, nil
+	}, stop
+}
+
 // List
 
 /*
 A List is an ordered Collection of items.
 Values added to the list retain the order in which they are added, and can be accessed by index.
 New elements can be inserted at an arbitrary index or added to the end of the list.
+Insert accepts any index from 0 to the size of the list, where inserting at the size is the same as a call to Add.
 Elements can be removed individually by index, or all at once with a call to Clear.
+The element at an existing index can be replaced with a call to Set, which returns the value it replaced.
+
+Lists can be searched by value with IndexOf, LastIndexOf and Contains, or by an arbitrary condition with IndexFunc.
+Like [slices.Index], the index methods return -1 if no element matches.
+
+Sort and SortStable order the elements of a List in place according to a less function.
+SortStable also preserves the original order of elements that are equal according to less.
 
 A List can also return a subset of its values via a call to SubList.
 Similar to a slice, a SubList is created by referencing a range of indexes of the originating list.
 However, a SubList is not another view into the same values, but instead is a complete copy of the elements in the range specified.
+
+Iterators returned by a List produce its elements in index order, starting from index 0.
+Backward produces the same index and element pairs as All, but in reverse order.
 */
 type List[T comparable] interface {
 	Collection[T]
+	Iterable[T]
 
 	Add(T)
+	Backward() iter.Seq2[int, T]
 	Clear()
+	Contains(T) bool
 	Get(int) (T, error)
+	IndexFunc(func(T) bool) int
+	IndexOf(T) int
 	Insert(int, T) error
+	LastIndexOf(T) int
 	Remove(int) (T, error)
+	Set(int, T) (T, error)
+	Sort(func(a, b T) bool)
+	SortStable(func(a, b T) bool)
 	SubList(int, int) (List[T], error)
 }
 
@@ -101,11 +260,13 @@ func (e ErrInvalidRange) Error() string {
 A ListNode stores a single element of a LinkedList.
 In addition to the element's value the node also stores references to the next and previous nodes in the list, allowing for traversal in either direction.
 In general, a call to Next or Previous will return a pointer to a concrete implementation of the ListNode interface.
+The value stored by a node can be replaced in place with SetValue, without changing the node's position in the list.
 A LinkedList also stores references to several of its key nodes, such as the head and tail.
 */
 type ListNode[T comparable] interface {
 	Next() ListNode[T]
 	Previous() ListNode[T]
+	SetValue(T)
 	Value() T
 }
 
@@ -120,11 +281,14 @@ However, nodes do not necessarily know their positions in the larger list, thoug
 LinkedLists can add elements directly before or after an existing node.
 Existing nodes can also be directly removed, in which case the previous and next nodes (if any) are linked to each other.
 
+FindNode returns the first node holding a value, so that a search can be followed by node operations without a second traversal.
+
 As a general rule, LinkedLists are slower than Lists for any index-based operations as the nodes must be traversed to reach the required element.
 */
 type LinkedList[T comparable] interface {
 	List[T]
 
+	FindNode(T) (ListNode[T], error)
 	GetNode(int) (ListNode[T], error)
 	Head() ListNode[T]
 	InsertAfter(ListNode[T], T) (ListNode[T], error)
@@ -144,15 +308,50 @@ Most methods of nodes and lists reference interface types in their signatures, b
 */
 var ErrWrongNodeType = errors.New("node is from an incompatible list implementation")
 
+// Map
+
+/*
+A Map associates keys with values, holding at most one value for each key.
+Put adds a key and its value, replacing the value if the key is already present.
+Like the Remove method of a Set, Delete never returns an error; it only ensures that the key is not present in the Map.
+
+The keys, values, and key and value pairs of a Map are available as range-over-func sequences via Keys, Values and Entries.
+All three sequences produce elements in the same order, which is defined by each implementation.
+*/
+type Map[K comparable, V any] interface {
+	Collection[K]
+
+	Clear()
+	ContainsKey(K) bool
+	Delete(K)
+	Entries() iter.Seq2[K, V]
+	Get(K) (V, error)
+	Keys() iter.Seq[K]
+	Put(K, V)
+	Values() iter.Seq[V]
+}
+
+/*
+ErrEmptyMap is returned when a method that requires at least one entry is called on an empty Map.
+*/
+var ErrEmptyMap = errors.New("map is empty")
+
+/*
+ErrKeyNotFound is returned when Get is called with a key that is not present in the Map.
+*/
+var ErrKeyNotFound = errors.New("key is not present in the map")
+
 // Queue
 
 /*
 A Queue represents a First In, First Out data structure.
 New elements are added to the end of the queue and will be returned after all preceding items.
 Values are returned and removed from the Queue via Pop, or a value can be retrieved without removal via Peek.
+Iterators returned by a Queue produce its elements in FIFO order, the same order in which Pop would return them.
 */
 type Queue[T comparable] interface {
 	Collection[T]
+	Iterable[T]
 
 	Peek() (T, error)
 	Pop() (T, error)
@@ -165,6 +364,84 @@ This should be considered a part of normal operations and callers should expect
 */
 var ErrEmptyQueue = errors.New("queue is empty")
 
+// AddressableQueue
+
+/*
+A QueueHandle refers to a single element of an AddressableQueue.
+The handle remains valid until its element is removed from the queue, by either Pop or Remove.
+*/
+type QueueHandle[T comparable] interface {
+	Value() T
+}
+
+/*
+An AddressableQueue is a priority queue that returns a QueueHandle for each element pushed.
+Handles allow an element to be located later without searching, so that its value can be changed with Update or it can be removed before reaching the front of the queue.
+Peek and Pop behave as they do for a Queue, returning the element with the highest priority.
+Iterators returned by an AddressableQueue produce its elements in the order in which Pop would return them.
+*/
+type AddressableQueue[T comparable] interface {
+	Collection[T]
+	Iterable[T]
+
+	Contains(QueueHandle[T]) bool
+	Peek() (T, error)
+	Pop() (T, error)
+	Push(T) QueueHandle[T]
+	Remove(QueueHandle[T]) (T, error)
+	Update(QueueHandle[T], T) error
+}
+
+/*
+ErrHandleIsNotElement is returned if a method is called with a handle whose element is not in the AddressableQueue.
+This includes handles from other queues and handles whose element has already been removed.
+*/
+var ErrHandleIsNotElement = errors.New("handle is not an element of this queue")
+
+/*
+ErrWrongHandleType indicates that a referenced handle is from an incompatible concrete implementation of AddressableQueue.
+*/
+var ErrWrongHandleType = errors.New("handle is from an incompatible queue implementation")
+
+// BlockingQueue
+
+/*
+A BlockingQueue is a Queue with a fixed capacity that is safe for concurrent use.
+PushCtx waits while the queue is full and PopCtx waits while it is empty; both return early with the context's error if it is cancelled or its deadline passes.
+PeekCtx waits as PopCtx does, but leaves the element it returns in the queue.
+TryPush and TryPop never wait and instead return ErrQueueFull or ErrEmptyQueue.
+
+The Queue methods are available to callers that only know the queue as a Queue.
+Peek and Pop behave as TryPop does.
+Push never waits, so that callers holding a lock around it cannot block those that would make space.
+As it has no way to report an error, an element that TryPush would reject is discarded instead, and Dropped returns the number discarded so far.
+
+After Close, no new elements can be added and every waiting call returns ErrQueueClosed.
+Elements already in the queue can still be removed; once it is empty, calls that remove elements also return ErrQueueClosed.
+*/
+type BlockingQueue[T comparable] interface {
+	Queue[T]
+
+	Cap() int
+	Close()
+	Dropped() int
+	PeekCtx(context.Context) (T, error)
+	PopCtx(context.Context) (T, error)
+	PushCtx(context.Context, T) error
+	TryPop() (T, error)
+	TryPush(T) error
+}
+
+/*
+ErrQueueClosed is returned when adding an element to a BlockingQueue that has been closed, or when removing an element from one that is closed and empty.
+*/
+var ErrQueueClosed = errors.New("queue is closed")
+
+/*
+ErrQueueFull is returned when TryPush is called on a BlockingQueue that is at capacity.
+*/
+var ErrQueueFull = errors.New("queue is full")
+
 // Set
 
 /*
@@ -189,6 +466,59 @@ ErrEmptySet is returned when Peek or Pop are called on an empty Set.
 */
 var ErrEmptySet = errors.New("stack is empty")
 
+// SortedMap
+
+/*
+A SortedMap is a Map that keeps its entries in ascending order of their keys, as defined by a comparison supplied to the implementation.
+Like a SortedSet, a SortedMap can be queried for the entries with its smallest and largest keys and for the entries nearest to an arbitrary key.
+Floor and Ceiling return the entry with the nearest key less than or equal to, or greater than or equal to, the key given; Lower and Higher exclude the key itself.
+
+The sequences returned by a SortedMap produce its entries in ascending order of keys; Backward produces them in descending order.
+*/
+type SortedMap[K comparable, V any] interface {
+	Map[K, V]
+
+	Backward() iter.Seq2[K, V]
+	Ceiling(K) (K, V, error)
+	Floor(K) (K, V, error)
+	Higher(K) (K, V, error)
+	Lower(K) (K, V, error)
+	Max() (K, V, error)
+	Min() (K, V, error)
+}
+
+// SortedSet
+
+/*
+A SortedSet is a Set that keeps its elements in ascending order, as defined by a comparison supplied to the implementation.
+In addition to the Set methods, a SortedSet can be queried for its smallest and largest elements and for the nearest elements to an arbitrary value.
+Floor and Ceiling return the nearest element less than or equal to, or greater than or equal to, the value; Lower and Higher exclude the value itself.
+Pop removes and returns the smallest element.
+
+Range returns the elements from the first argument (inclusive) to the second (exclusive) as a new SortedSet.
+Similar to a SubList, the result is a complete copy of the elements, not another view into the same values.
+
+Iterators returned by a SortedSet produce its elements in ascending order.
+Backward produces the same index and element pairs as All, but in descending order.
+*/
+type SortedSet[T comparable] interface {
+	Set[T]
+
+	Backward() iter.Seq2[int, T]
+	Ceiling(T) (T, error)
+	Floor(T) (T, error)
+	Higher(T) (T, error)
+	Lower(T) (T, error)
+	Max() (T, error)
+	Min() (T, error)
+	Range(T, T) SortedSet[T]
+}
+
+/*
+ErrNoSuchElement is returned when no element satisfies a query such as Floor, Ceiling or FindNode.
+*/
+var ErrNoSuchElement = errors.New("no element satisfies the query")
+
 // Stack
 
 /*
@@ -196,9 +526,11 @@ A Stack is an ordered group of items from which the last added is the first retu
 The common analogy is a stack of clean plates at a buffet or cafeteria; when one is removed, another rises to take its place.
 A new element is added to the top of the Stack (first for retrieval) with a call to Push.
 Peek and Pop return the next value from the Stack, with Peek retaining the value on the Stack and Pop removing it.
+Iterators returned by a Stack produce its elements from top to bottom, the same order in which Pop would return them.
 */
 type Stack[T comparable] interface {
 	Collection[T]
+	Iterable[T]
 
 	Peek() (T, error)
 	Pop() (T, error)
@@ -209,3 +541,31 @@ type Stack[T comparable] interface {
 ErrEmptyStack is returned when Peek or Pop are called on an empty Stack.
 */
 var ErrEmptyStack = errors.New("stack is empty")
+
+// Validator
+
+/*
+A Validator can check that its internal structure is consistent, such as the links between its nodes and the count of its elements.
+Validate walks the entire structure and returns an ErrCorrupted describing the first problem found, or nil if there is none.
+It is intended for debugging and tests rather than normal use, as it takes time proportional to the size of the collection.
+A collection is only corrupted by a bug in its implementation or by concurrent use without synchronization.
+*/
+type Validator interface {
+	Validate() error
+}
+
+/*
+ErrCorrupted is returned by Validate when the internal structure of a collection is inconsistent.
+*/
+type ErrCorrupted struct {
+	Index   int    // Position of the element at which the problem was found, or -1 if it is not specific to one element
+	Problem string // Description of the inconsistency
+}
+
+func (e ErrCorrupted) Error() string {
+	if e.Index < 0 {
+		return fmt.Sprintf("collection is corrupted: %s", e.Problem)
+	}
+
+	return fmt.Sprintf("collection is corrupted at index %d: %s", e.Index, e.Problem)
+}
@@ -3,12 +3,31 @@
 package collections_test
 
 import (
+	"errors"
 	"fmt"
+	"slices"
 	"testing"
 
 	"github.com/bmoller/collections"
+	"github.com/bmoller/collections/linkedlist"
+	"github.com/bmoller/collections/slicelist"
 )
 
+func TestErrCorrupted(t *testing.T) {
+	err := collections.ErrCorrupted{
+		Index:   -1,
+		Problem: "tail is not the last node",
+	}
+	if err.Error() != "collection is corrupted: tail is not the last node" {
+		t.Fatalf("unexpected error string: %s", err)
+	}
+
+	err.Index = 5
+	if err.Error() != fmt.Sprintf("collection is corrupted at index %d: %s", 5, "tail is not the last node") {
+		t.Fatalf("unexpected error string: %s", err)
+	}
+}
+
 func TestErrIndexOutOfRange(t *testing.T) {
 	err := collections.ErrIndexOutOfRange{
 		Index: 100,
@@ -30,3 +49,126 @@ func TestErrInvalidRange(t *testing.T) {
 		t.Fatalf("unexpected error string: %s", err)
 	}
 }
+
+func TestFromSeq(t *testing.T) {
+	itr, stop := collections.FromSeq(slices.Values([]int{0, 1, 2}))
+	defer stop()
+
+	for i := 0; i < 3; i++ {
+		if element, err := itr(); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if element != i {
+			t.Fatalf("expected element with value %d but got %d", i, element)
+		}
+	}
+	if _, err := itr(); !errors.Is(err, collections.ErrNoMoreItems) {
+		t.Fatalf("expected ErrNoMoreItems but got: %v", err)
+	}
+
+	itr, stop = collections.FromSeq(slices.Values([]int{0, 1, 2}))
+	itr()
+	stop()
+	if _, err := itr(); !errors.Is(err, collections.ErrNoMoreItems) {
+		t.Fatalf("expected ErrNoMoreItems after stop but got: %v", err)
+	}
+}
+
+func TestToSeq(t *testing.T) {
+	values := []int{0, 1, 2}
+	i := 0
+	itr := func() (element int, err error) {
+		if i == len(values) {
+			return element, collections.ErrNoMoreItems
+		}
+		element = values[i]
+		i++
+		return element, nil
+	}
+
+	if result := slices.Collect(collections.ToSeq(itr)); !slices.Equal(result, values) {
+		t.Fatalf("expected %v but got %v", values, result)
+	}
+
+	i = 0
+	for element := range collections.ToSeq(itr) {
+		if element == 1 {
+			break
+		}
+	}
+	if i != 2 {
+		t.Fatalf("expected iterator to stop after %d elements but consumed %d", 2, i)
+	}
+
+	i = 0
+	failing := func() (element int, err error) {
+		if i == 1 {
+			return element, collections.ErrConcurrentModification
+		}
+		return itr()
+	}
+	defer func() {
+		if err, _ := recover().(error); err == nil || !errors.Is(err, collections.ErrConcurrentModification) {
+			t.Fatalf("expected panic with ErrConcurrentModification but got: %v", err)
+		}
+	}()
+	for range collections.ToSeq(failing) {
+	}
+}
+
+func TestErrEmptyDeque(t *testing.T) {
+	if !errors.Is(collections.ErrEmptyDeque, collections.ErrEmptyDeque) {
+		t.Fatal("expected ErrEmptyDeque to match itself")
+	}
+	if !errors.Is(collections.ErrEmptyDeque, collections.ErrEmptyQueue) {
+		t.Fatal("expected ErrEmptyDeque to match ErrEmptyQueue")
+	}
+	if !errors.Is(collections.ErrEmptyDeque, collections.ErrEmptyStack) {
+		t.Fatal("expected ErrEmptyDeque to match ErrEmptyStack")
+	}
+	if errors.Is(collections.ErrEmptyDeque, collections.ErrEmptyList) {
+		t.Fatal("expected ErrEmptyDeque not to match ErrEmptyList")
+	}
+	if collections.ErrEmptyDeque.Error() != "deque is empty" {
+		t.Fatalf("unexpected error string: %s", collections.ErrEmptyDeque)
+	}
+}
+
+func TestBinarySearch(t *testing.T) {
+	less := func(a, b int) bool { return a < b }
+
+	list := slicelist.New[int]()
+	if index, found := collections.BinarySearch(list, 0, less); index != 0 || found {
+		t.Fatalf("expected (%d, %t) from empty list but got (%d, %t)", 0, false, index, found)
+	}
+
+	for i := 0; i < 1000; i++ {
+		list.Add(i * 2)
+	}
+	for i := -1; i < 2000; i++ {
+		index, found := collections.BinarySearch(list, i, less)
+		if expected := (i + 1) / 2; index != expected {
+			t.Fatalf("expected index %d for %d but got %d", expected, i, index)
+		} else if found != (i >= 0 && i%2 == 0) {
+			t.Fatalf("unexpected found result %t for %d", found, i)
+		}
+	}
+}
+
+func TestIsSorted(t *testing.T) {
+	less := func(a, b int) bool { return a < b }
+
+	list := linkedlist.New[int]()
+	if !collections.IsSorted(list, less) {
+		t.Fatal("expected empty list to be sorted")
+	}
+	for i := 0; i < 100; i++ {
+		list.Add(i / 2)
+	}
+	if !collections.IsSorted(list, less) {
+		t.Fatal("expected ascending list to be sorted")
+	}
+	list.Add(0)
+	if collections.IsSorted(list, less) {
+		t.Fatal("expected list with descending tail not to be sorted")
+	}
+}
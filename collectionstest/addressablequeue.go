@@ -0,0 +1,134 @@
+// ©2022 Brandon Moller
+
+package collectionstest
+
+import (
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/bmoller/collections"
+)
+
+// opcodes for the operations of FuzzAddressableQueue
+const (
+	addressableContains = iota
+	addressablePeek
+	addressablePop
+	addressablePush
+	addressableRemove
+	addressableUpdate
+	addressableOperations // the number of opcodes
+)
+
+/*
+FuzzAddressableQueue applies the operations decoded from each input to one of the AddressableQueues returned by newQueue and to a reference model that maps each handle to its value.
+The AddressableQueues must return lower values first, as one ordered by [cmp.Less] does.
+Handles are chosen from every handle returned by Push, including those whose elements have left the queue, and from a handle of another AddressableQueue.
+It fails if their results or errors differ, or if their elements or handles differ after any operation.
+*/
+func FuzzAddressableQueue(f *testing.F, newQueue func() collections.AddressableQueue[int]) {
+	addSeeds(f)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		ops := newOperations(t, data)
+		queue := newQueue()
+		foreign := newQueue().Push(0)
+		var handles []collections.QueueHandle[int]
+		model := make(map[collections.QueueHandle[int]]int)
+		for op, ok := ops.next(addressableOperations); ok; op, ok = ops.next(addressableOperations) {
+			switch op {
+			case addressableContains:
+				h, i := handleOf(ops, handles, foreign)
+				ops.record("Contains(handles[%d])", i)
+				_, expected := model[h]
+				checkResult(t, "Contains", queue.Contains(h), expected)
+			case addressablePeek, addressablePop:
+				var (
+					element int
+					err     error
+				)
+				method := "Peek"
+				if op == addressablePop {
+					method = "Pop"
+					element, err = queue.Pop()
+				} else {
+					element, err = queue.Peek()
+				}
+				ops.record("%s()", method)
+				if len(model) == 0 {
+					checkError(t, method, err, collections.ErrEmptyQueue)
+					break
+				}
+				checkError(t, method, err, nil)
+				checkResult(t, method, element, slices.Min(slices.Collect(maps.Values(model))))
+				if op == addressablePeek {
+					break
+				}
+				// any of the elements with the lowest value may be the one popped, which is the only one whose handle is no longer contained
+				popped := 0
+				for h, value := range model {
+					if value == element && !queue.Contains(h) {
+						delete(model, h)
+						popped++
+					}
+				}
+				checkResult(t, "the number of handles invalidated by Pop", popped, 1)
+			case addressablePush:
+				value := ops.value()
+				ops.record("Push(%d) as handles[%d]", value, len(handles))
+				h := queue.Push(value)
+				handles = append(handles, h)
+				model[h] = value
+			case addressableRemove:
+				h, i := handleOf(ops, handles, foreign)
+				ops.record("Remove(handles[%d])", i)
+				element, err := queue.Remove(h)
+				value, ok := model[h]
+				if !ok {
+					checkError(t, "Remove", err, collections.ErrHandleIsNotElement)
+					break
+				}
+				checkError(t, "Remove", err, nil)
+				checkResult(t, "Remove", element, value)
+				delete(model, h)
+			case addressableUpdate:
+				h, i := handleOf(ops, handles, foreign)
+				value := ops.value()
+				ops.record("Update(handles[%d], %d)", i, value)
+				err := queue.Update(h, value)
+				if _, ok := model[h]; !ok {
+					checkError(t, "Update", err, collections.ErrHandleIsNotElement)
+					break
+				}
+				checkError(t, "Update", err, nil)
+				model[h] = value
+			}
+			checkAddressableQueue(t, queue, model)
+		}
+	})
+}
+
+// checkAddressableQueue fails the test unless queue holds exactly the handles of model, each with its value, and produces the values in ascending order.
+func checkAddressableQueue(t *testing.T, queue collections.AddressableQueue[int], model map[collections.QueueHandle[int]]int) {
+	t.Helper()
+
+	for h, value := range model {
+		if !queue.Contains(h) {
+			t.Fatalf("expected queue to contain handle with value %d", value)
+		} else if h.Value() != value {
+			t.Fatalf("expected handle with value %d but got %d", value, h.Value())
+		}
+	}
+	checkSize(t, queue, len(model))
+	checkIterable(t, queue, slices.Sorted(maps.Values(model)))
+}
+
+// handleOf returns one of handles chosen by the next byte of the input along with its index, or foreign with the index len(handles).
+func handleOf(ops *operations, handles []collections.QueueHandle[int], foreign collections.QueueHandle[int]) (collections.QueueHandle[int], int) {
+	i := int(ops.byte()) % (len(handles) + 1)
+	if i == len(handles) {
+		return foreign, i
+	}
+
+	return handles[i], i
+}
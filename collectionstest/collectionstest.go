@@ -0,0 +1,250 @@
+// ©2022 Brandon Moller
+
+/*
+Package collectionstest provides conformance tests for implementations of the collections interfaces.
+
+Each Test function takes a constructor for the implementation under test and runs a subtest for every method of the interface, including its documented edge cases and errors.
+The constructor is called at least once per subtest and must return a new, empty collection each time.
+The packages in this module use the same tests, so an implementation that passes them behaves as the built-in ones do.
+
+	func TestList(t *testing.T) {
+		collectionstest.TestList(t, mylist.New[int])
+	}
+
+The tests never hold more than 1000 elements in a collection at once, so an implementation with a fixed capacity can be tested with a capacity of 1000.
+They only use a collection from the goroutine running the test, and do not modify a collection while ranging over it.
+Whenever the tests check the elements of a collection that implements [collections.Validator], they also check that Validate returns nil.
+The Iterator tests also check that an Iterator fails with ErrConcurrentModification after each method that modifies its collection, and keeps going after those that do not.
+Every collection must also implement [collections.RemovingIterable], as those in this module do, and the RemovingIterator tests fail for one that does not.
+
+Each Fuzz function decodes its input into a sequence of operations, applies them to both the implementation under test and a simple reference model backed by a slice or map, and fails as soon as their results, errors or elements differ.
+The operations performed are logged when an input fails, and no more than 1000 are decoded from one input.
+
+	func FuzzList(f *testing.F) {
+		collectionstest.FuzzList(f, mylist.New[int])
+	}
+
+As with any fuzz test, go test saves failing inputs under testdata/fuzz and runs them as regression tests from then on.
+*/
+package collectionstest
+
+import (
+	"errors"
+	"iter"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/bmoller/collections"
+)
+
+// checkBackward fails the test unless seq produces exactly the elements of expected in reverse, paired with their indexes.
+func checkBackward(t *testing.T, seq iter.Seq2[int, int], expected []int) {
+	t.Helper()
+
+	i := len(expected) - 1
+	for index, element := range seq {
+		if i < 0 {
+			t.Fatalf("expected %d elements from Backward but got more", len(expected))
+		} else if index != i {
+			t.Fatalf("expected index %d from Backward but got %d", i, index)
+		} else if element != expected[i] {
+			t.Fatalf("expected element with value %d at index %d from Backward but got %d", expected[i], i, element)
+		}
+		i--
+	}
+	if i != -1 {
+		t.Fatalf("expected %d elements from Backward but got %d", len(expected), len(expected)-1-i)
+	}
+}
+
+// checkIndexError fails the test unless err is an ErrIndexOutOfRange reporting index and size.
+func checkIndexError(t *testing.T, err error, index, size int) {
+	t.Helper()
+
+	var indexErr collections.ErrIndexOutOfRange
+	if err == nil || !errors.As(err, &indexErr) {
+		t.Fatalf("expected ErrIndexOutOfRange for index %d but got: %v", index, err)
+	} else if indexErr.Index != index || indexErr.Size != size {
+		t.Fatalf("expected ErrIndexOutOfRange with index %d and size %d but got index %d and size %d", index, size, indexErr.Index, indexErr.Size)
+	}
+}
+
+// checkIterable fails the test unless every iterator of c produces the elements of expected in order.
+func checkIterable(t *testing.T, c collections.Iterable[int], expected []int) {
+	t.Helper()
+
+	checkSeq(t, "All", c.All(), expected)
+	if actual := slices.Collect(c.Values()); !slices.Equal(actual, expected) {
+		t.Fatalf("expected %v from Values but got %v", expected, actual)
+	}
+	checkIterator(t, c.Iterator(), expected)
+}
+
+// checkIterator fails the test unless itr produces exactly the elements of expected and is then exhausted.
+func checkIterator(t *testing.T, itr collections.Iterator[int], expected []int) {
+	t.Helper()
+
+	var actual []int
+	element, err := itr()
+	for ; err == nil && len(actual) <= len(expected); element, err = itr() {
+		actual = append(actual, element)
+	}
+	if len(actual) > len(expected) {
+		t.Fatalf("expected %d elements from Iterator but got more", len(expected))
+	} else if !errors.Is(err, collections.ErrNoMoreItems) {
+		t.Fatalf("exhausted iterator should return ErrNoMoreItems but got %v", err)
+	} else if !slices.Equal(actual, expected) {
+		t.Fatalf("expected %v from Iterator but got %v", expected, actual)
+	}
+}
+
+// checkInvalidated fails the test unless itr, created before method modified its collection, returns ErrConcurrentModification on every call.
+func checkInvalidated(t *testing.T, itr collections.Iterator[int], method string) {
+	t.Helper()
+
+	for range 2 {
+		if _, err := itr(); err == nil || !errors.Is(err, collections.ErrConcurrentModification) {
+			t.Fatalf("expected ErrConcurrentModification from Iterator after %s but got: %v", method, err)
+		}
+	}
+}
+
+// checkRangeError fails the test unless err is an ErrInvalidRange reporting start and end.
+func checkRangeError(t *testing.T, err error, start, end int) {
+	t.Helper()
+
+	var rangeErr collections.ErrInvalidRange
+	if err == nil || !errors.As(err, &rangeErr) {
+		t.Fatalf("expected ErrInvalidRange for range %d to %d but got: %v", start, end, err)
+	} else if rangeErr.Start != start || rangeErr.End != end {
+		t.Fatalf("expected ErrInvalidRange with start %d and end %d but got start %d and end %d", start, end, rangeErr.Start, rangeErr.End)
+	}
+}
+
+// checkRemovingIterator removes the odd elements of c, which must hold the elements of sequence(1000) in any order, through a RemovingIterator.
+// It fails the test unless every element is produced, Remove succeeds once after each Next, and other Iterators of c are invalidated.
+// It then checks that a RemovingIterator fails with ErrConcurrentModification once modify has changed c, and fails the test if c is not a [collections.RemovingIterable].
+func checkRemovingIterator(t *testing.T, c collections.Iterable[int], modify func()) {
+	t.Helper()
+
+	iterable, ok := c.(collections.RemovingIterable[int])
+	if !ok {
+		t.Fatal("collection does not implement RemovingIterable")
+	}
+	itr := iterable.RemovingIterator()
+	if err := itr.Remove(); err == nil || !errors.Is(err, collections.ErrNothingToRemove) {
+		t.Fatalf("expected ErrNothingToRemove from Remove before Next but got: %v", err)
+	}
+	other := c.Iterator()
+
+	count := 0
+	element, err := itr.Next()
+	for ; err == nil && count < 1000; element, err = itr.Next() {
+		count++
+		if element%2 == 0 {
+			continue
+		}
+		if err := itr.Remove(); err != nil {
+			t.Fatalf("unexpected error removing %d: %s", element, err)
+		} else if err := itr.Remove(); err == nil || !errors.Is(err, collections.ErrNothingToRemove) {
+			t.Fatalf("expected ErrNothingToRemove from a second Remove but got: %v", err)
+		}
+	}
+	if !errors.Is(err, collections.ErrNoMoreItems) {
+		t.Fatalf("exhausted iterator should return ErrNoMoreItems but got %v", err)
+	} else if count != 1000 {
+		t.Fatalf("expected %d elements from RemovingIterator but got %d", 1000, count)
+	}
+	checkInvalidated(t, other, "Remove on a RemovingIterator")
+
+	itr = iterable.RemovingIterator()
+	itr.Next()
+	modify()
+	if err := itr.Remove(); err == nil || !errors.Is(err, collections.ErrConcurrentModification) {
+		t.Fatalf("expected ErrConcurrentModification from Remove after a modification but got: %v", err)
+	} else if _, err := itr.Next(); err == nil || !errors.Is(err, collections.ErrConcurrentModification) {
+		t.Fatalf("expected ErrConcurrentModification from Next after a modification but got: %v", err)
+	}
+}
+
+// checkSeq fails the test unless seq produces exactly the elements of expected, paired with their indexes.
+func checkSeq(t *testing.T, name string, seq iter.Seq2[int, int], expected []int) {
+	t.Helper()
+
+	count := 0
+	for i, element := range seq {
+		if count >= len(expected) {
+			t.Fatalf("expected %d elements from %s but got more", len(expected), name)
+		} else if i != count {
+			t.Fatalf("expected index %d from %s but got %d", count, name, i)
+		} else if element != expected[i] {
+			t.Fatalf("expected element with value %d at index %d from %s but got %d", expected[i], i, name, element)
+		}
+		count++
+	}
+	if count != len(expected) {
+		t.Fatalf("expected %d elements from %s but got %d", len(expected), name, count)
+	}
+}
+
+// checkSize fails the test unless c is valid and Size and Empty agree with size.
+func checkSize(t *testing.T, c collections.Collection[int], size int) {
+	t.Helper()
+
+	checkValid(t, c)
+	if c.Size() != size {
+		t.Fatalf("expected size %d but got %d", size, c.Size())
+	} else if c.Empty() != (size == 0) {
+		t.Fatalf("expected Empty to be %t with size %d", size == 0, size)
+	}
+}
+
+// checkValid fails the test if c is a [collections.Validator] that reports a problem with its structure.
+func checkValid(t *testing.T, c any) {
+	t.Helper()
+
+	if validator, ok := c.(collections.Validator); ok {
+		if err := validator.Validate(); err != nil {
+			t.Fatalf("unexpected error from Validate: %s", err)
+		}
+	}
+}
+
+// evens returns the even elements from 0 up to but not including n.
+func evens(n int) []int {
+	elements := make([]int, 0, n/2)
+	for i := 0; i < n; i += 2 {
+		elements = append(elements, i)
+	}
+
+	return elements
+}
+
+// isNil reports whether node is nil, including a nil pointer held by the interface.
+func isNil(node collections.ListNode[int]) bool {
+	if node == nil {
+		return true
+	}
+	value := reflect.ValueOf(node)
+
+	return value.Kind() == reflect.Pointer && value.IsNil()
+}
+
+// reversed returns a reversed copy of elements.
+func reversed(elements []int) []int {
+	reversed := slices.Clone(elements)
+	slices.Reverse(reversed)
+
+	return reversed
+}
+
+// sequence returns the elements from 0 up to but not including n.
+func sequence(n int) []int {
+	elements := make([]int, n)
+	for i := range elements {
+		elements[i] = i
+	}
+
+	return elements
+}
@@ -0,0 +1,124 @@
+// ©2022 Brandon Moller
+
+package collectionstest
+
+import (
+	"testing"
+
+	"github.com/bmoller/collections"
+)
+
+// opcodes for the operations of FuzzDeque
+const (
+	dequePeek = iota
+	dequePeekBack
+	dequePeekFront
+	dequePop
+	dequePopBack
+	dequePopFront
+	dequePush
+	dequePushBack
+	dequePushFront
+	dequeStackPeek
+	dequeStackPop
+	dequeStackPush
+	dequeOperations // the number of opcodes
+)
+
+/*
+FuzzDeque applies the operations decoded from each input to one of the Deques returned by newDeque and to a reference Deque backed by a slice.
+The Deque is used through its own methods, as a Queue, and through the Stack returned by AsStack.
+It fails if their results or errors differ, or if their elements differ after any operation.
+*/
+func FuzzDeque(f *testing.F, newDeque func() collections.Deque[int]) {
+	addSeeds(f)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		ops := newOperations(t, data)
+		deque := newDeque()
+		stack := deque.AsStack()
+		var model []int
+		for op, ok := ops.next(dequeOperations); ok; op, ok = ops.next(dequeOperations) {
+			switch op {
+			case dequePush, dequePushBack, dequePushFront, dequeStackPush:
+				value := ops.value()
+				switch op {
+				case dequePush:
+					ops.record("Push(%d)", value)
+					deque.Push(value)
+				case dequePushBack:
+					ops.record("PushBack(%d)", value)
+					deque.PushBack(value)
+				case dequePushFront:
+					ops.record("PushFront(%d)", value)
+					deque.PushFront(value)
+				case dequeStackPush:
+					ops.record("AsStack().Push(%d)", value)
+					stack.Push(value)
+				}
+				if op == dequePushFront || op == dequeStackPush {
+					model = append([]int{value}, model...)
+				} else {
+					model = append(model, value)
+				}
+			default:
+				model = stepDequeRemoval(t, ops, op, deque, stack, model)
+			}
+			checkQueue(t, deque, model)
+			checkBackward(t, deque.Backward(), model)
+			checkStack(t, stack, model)
+		}
+	})
+}
+
+// stepDequeRemoval applies the Peek or Pop operation op to both deque and model, and returns the updated model.
+func stepDequeRemoval(t *testing.T, ops *operations, op int, deque collections.Deque[int], stack collections.Stack[int], model []int) []int {
+	t.Helper()
+
+	var (
+		back   bool
+		call   func() (int, error)
+		empty  error
+		method string
+		remove bool
+	)
+	switch op {
+	case dequePeek:
+		call, empty, method = deque.Peek, collections.ErrEmptyQueue, "Peek"
+	case dequePeekBack:
+		back, call, empty, method = true, deque.PeekBack, collections.ErrEmptyDeque, "PeekBack"
+	case dequePeekFront:
+		call, empty, method = deque.PeekFront, collections.ErrEmptyDeque, "PeekFront"
+	case dequePop:
+		call, empty, method, remove = deque.Pop, collections.ErrEmptyQueue, "Pop", true
+	case dequePopBack:
+		back, call, empty, method, remove = true, deque.PopBack, collections.ErrEmptyDeque, "PopBack", true
+	case dequePopFront:
+		call, empty, method, remove = deque.PopFront, collections.ErrEmptyDeque, "PopFront", true
+	case dequeStackPeek:
+		call, empty, method = stack.Peek, collections.ErrEmptyStack, "AsStack().Peek"
+	case dequeStackPop:
+		call, empty, method, remove = stack.Pop, collections.ErrEmptyStack, "AsStack().Pop", true
+	}
+
+	ops.record("%s()", method)
+	element, err := call()
+	if len(model) == 0 {
+		checkError(t, method, err, empty)
+		return model
+	}
+	checkError(t, method, err, nil)
+	if back {
+		checkResult(t, method, element, model[len(model)-1])
+	} else {
+		checkResult(t, method, element, model[0])
+	}
+
+	switch {
+	case remove && back:
+		model = model[:len(model)-1]
+	case remove:
+		model = model[1:]
+	}
+
+	return model
+}
@@ -0,0 +1,122 @@
+// ©2022 Brandon Moller
+
+package collectionstest
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"testing"
+
+	"github.com/bmoller/collections"
+)
+
+// maxOperations limits the operations decoded from one input, so that no collection grows beyond 1000 elements.
+const maxOperations = 1000
+
+// operations decodes a fuzz input into a sequence of operations, each an opcode followed by the bytes of its arguments.
+// Arguments read past the end of the input are 0.
+type operations struct {
+	count int
+	data  []byte
+	trace []string
+}
+
+// newOperations returns the operations encoded by data, and logs every operation performed if the test fails.
+func newOperations(t *testing.T, data []byte) *operations {
+	ops := &operations{
+		data: data,
+	}
+	t.Cleanup(func() {
+		if t.Failed() {
+			t.Logf("operations performed:\n%s", strings.Join(ops.trace, "\n"))
+		}
+	})
+
+	return ops
+}
+
+// byte returns the next byte of the input.
+func (o *operations) byte() byte {
+	if len(o.data) == 0 {
+		return 0
+	}
+	b := o.data[0]
+	o.data = o.data[1:]
+
+	return b
+}
+
+// index returns a position in a collection of size elements, from -1 up to size+1 so that both bounds are exceeded.
+func (o *operations) index(size int) int {
+	return int(o.byte())%(size+3) - 1
+}
+
+// key returns a value from the full range of a byte, for sets and maps where duplicates are less interesting.
+func (o *operations) key() int {
+	return int(o.byte())
+}
+
+// next returns the next opcode, from 0 up to but not including n, and false once the input or the operation limit is exhausted.
+func (o *operations) next(n int) (int, bool) {
+	if len(o.data) == 0 || o.count == maxOperations {
+		return 0, false
+	}
+	o.count++
+
+	return int(o.byte()) % n, true
+}
+
+// record adds an operation to the trace logged if the test fails.
+func (o *operations) record(format string, args ...any) {
+	o.trace = append(o.trace, fmt.Sprintf(format, args...))
+}
+
+// value returns an element value from a small range, so that duplicates are common.
+func (o *operations) value() int {
+	return int(o.byte() % 16)
+}
+
+// addSeeds adds inputs to the seed corpus of f: no operations, every opcode in turn, and a fixed pseudo-random sequence.
+func addSeeds(f *testing.F) {
+	ascending := make([]byte, 256)
+	for i := range ascending {
+		ascending[i] = byte(i)
+	}
+	random := make([]byte, 2048)
+	rand.NewChaCha8([32]byte{}).Read(random)
+
+	f.Add([]byte{})
+	f.Add(ascending)
+	f.Add(random)
+}
+
+// checkError fails the test unless err matches expected, which is nil if the call should have succeeded.
+func checkError(t *testing.T, method string, err, expected error) {
+	t.Helper()
+
+	switch expected := expected.(type) {
+	case nil:
+		if err != nil {
+			t.Fatalf("unexpected error from %s: %s", method, err)
+		}
+	case collections.ErrIndexOutOfRange:
+		checkIndexError(t, err, expected.Index, expected.Size)
+	case collections.ErrInvalidRange:
+		checkRangeError(t, err, expected.Start, expected.End)
+	default:
+		if err == nil || !errors.Is(err, expected) {
+			t.Fatalf("expected %v from %s but got: %v", expected, method, err)
+		}
+	}
+}
+
+// checkResult fails the test unless method returned expected.
+func checkResult[T comparable](t *testing.T, method string, actual, expected T) {
+	t.Helper()
+
+	if actual != expected {
+		t.Fatalf("expected %v from %s but got %v", expected, method, actual)
+	}
+}
@@ -0,0 +1,501 @@
+// ©2022 Brandon Moller
+
+package collectionstest
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/bmoller/collections"
+)
+
+// opcodes for the node operations of FuzzLinkedList, which follow those of FuzzList
+const (
+	linkedListFindNode = iota
+	linkedListGetNode
+	linkedListInsertAfter
+	linkedListInsertBefore
+	linkedListRemoveNode
+	linkedListSetValue
+	linkedListOperations // the number of opcodes handled by stepLinkedList
+)
+
+// maxHeldNodes limits the nodes that FuzzLinkedList keeps to pass back to a list.
+const maxHeldNodes = 8
+
+// foreignNode is a ListNode that no LinkedList implementation accepts.
+type foreignNode struct {
+	value int
+}
+
+func (n *foreignNode) Next() collections.ListNode[int] {
+	return nil
+}
+
+func (n *foreignNode) Previous() collections.ListNode[int] {
+	return nil
+}
+
+func (n *foreignNode) SetValue(value int) {
+	n.value = value
+}
+
+func (n *foreignNode) Value() int {
+	return n.value
+}
+
+// heldNode pairs a node returned by a LinkedList with the element of the model that it holds.
+type heldNode struct {
+	element *listElement
+	node    collections.ListNode[int]
+}
+
+/*
+FuzzLinkedList applies the operations decoded from each input to one of the LinkedLists returned by newList and to a reference List backed by a slice, as FuzzList does.
+The nodes returned by the list are kept and later passed back to it, so that nodes removed by Remove, RemoveNode or Clear must be rejected with ErrNodeIsNotElement.
+Set and SetValue are expected to change the value of a node in place, but as a LinkedList may move values between nodes while sorting, nodes are discarded by Sort and SortStable.
+*/
+func FuzzLinkedList(f *testing.F, newList func() collections.LinkedList[int]) {
+	addSeeds(f)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		ops := newOperations(t, data)
+		list := newList()
+		var (
+			held  []heldNode
+			model listModel
+		)
+		for op, ok := ops.next(listOperations + linkedListOperations); ok; op, ok = ops.next(listOperations + linkedListOperations) {
+			switch {
+			case op == listSort:
+				held = nil
+				fallthrough
+			case op < listOperations:
+				model = stepList(t, ops, op, list, model)
+			default:
+				model, held = stepLinkedList(t, ops, op-listOperations, list, model, held)
+			}
+			checkLinkedList(t, list, model.values())
+			for _, h := range held {
+				if model.index(h.element) != -1 && h.node.Value() != h.element.value {
+					t.Fatalf("expected node to hold value %d but got %d", h.element.value, h.node.Value())
+				}
+			}
+		}
+	})
+}
+
+/*
+TestLinkedList runs the conformance tests for [collections.LinkedList] against the LinkedLists returned by newList.
+The tests for [collections.List] are run first, as by TestList.
+*/
+func TestLinkedList(t *testing.T, newList func() collections.LinkedList[int]) {
+	TestList(t, func() collections.List[int] { return newList() })
+
+	t.Run("FindNode", func(t *testing.T) { testLinkedListFindNode(t, newList) })
+	t.Run("GetNode", func(t *testing.T) { testLinkedListGetNode(t, newList) })
+	t.Run("Head", func(t *testing.T) { testLinkedListHead(t, newList) })
+	t.Run("InsertAfter", func(t *testing.T) { testLinkedListInsertAfter(t, newList) })
+	t.Run("InsertBefore", func(t *testing.T) { testLinkedListInsertBefore(t, newList) })
+	t.Run("Nodes", func(t *testing.T) { testLinkedListNodes(t, newList) })
+	t.Run("RemoveNode", func(t *testing.T) { testLinkedListRemoveNode(t, newList) })
+	t.Run("Tail", func(t *testing.T) { testLinkedListTail(t, newList) })
+}
+
+// checkLinkedList fails the test unless list holds exactly the elements of expected, both by index and by following its nodes from either end.
+func checkLinkedList(t *testing.T, list collections.LinkedList[int], expected []int) {
+	t.Helper()
+
+	checkList(t, list, expected)
+	if len(expected) == 0 {
+		if !isNil(list.Head()) || !isNil(list.Tail()) {
+			t.Fatal("expected empty list to have no head or tail")
+		}
+		return
+	}
+
+	var forward, backward []int
+	for node := list.Head(); !isNil(node) && len(forward) <= len(expected); node = node.Next() {
+		forward = append(forward, node.Value())
+	}
+	for node := list.Tail(); !isNil(node) && len(backward) <= len(expected); node = node.Previous() {
+		backward = append(backward, node.Value())
+	}
+	if !slices.Equal(forward, expected) {
+		t.Fatalf("expected %v following Next from the head but got %v", expected, forward)
+	} else if !slices.Equal(backward, reversed(expected)) {
+		t.Fatalf("expected %v following Previous from the tail but got %v", reversed(expected), backward)
+	}
+}
+
+// fillLinkedList adds the elements of elements to a new LinkedList.
+func fillLinkedList(newList func() collections.LinkedList[int], elements []int) collections.LinkedList[int] {
+	list := newList()
+	for _, element := range elements {
+		list.Add(element)
+	}
+
+	return list
+}
+
+// hold adds a node to those kept by FuzzLinkedList, discarding the oldest if there are too many.
+func hold(held []heldNode, element *listElement, node collections.ListNode[int]) []heldNode {
+	held = append(held, heldNode{element, node})
+	if len(held) > maxHeldNodes {
+		held = held[1:]
+	}
+
+	return held
+}
+
+// stepLinkedList applies the node operation op to both list and model, and returns the updated model and held nodes.
+func stepLinkedList(t *testing.T, ops *operations, op int, list collections.LinkedList[int], model listModel, held []heldNode) (listModel, []heldNode) {
+	t.Helper()
+
+	switch op {
+	case linkedListFindNode:
+		value := ops.value()
+		ops.record("FindNode(%d)", value)
+		node, err := list.FindNode(value)
+		if index := slices.Index(model.values(), value); index == -1 {
+			checkError(t, "FindNode", err, collections.ErrNoSuchElement)
+		} else {
+			checkError(t, "FindNode", err, nil)
+			checkResult(t, "FindNode", node.Value(), value)
+			held = hold(held, model[index], node)
+		}
+		return model, held
+	case linkedListGetNode:
+		index := ops.index(len(model))
+		ops.record("GetNode(%d)", index)
+		node, err := list.GetNode(index)
+		checkError(t, "GetNode", err, model.indexError(index))
+		if err == nil {
+			checkResult(t, "GetNode", node.Value(), model[index].value)
+			held = hold(held, model[index], node)
+		}
+		return model, held
+	}
+
+	choice := ops.byte()
+	if len(held) == 0 {
+		ops.record("no nodes held")
+		return model, held
+	}
+	h := held[int(choice)%len(held)]
+	index := model.index(h.element)
+	var expected error
+	if index == -1 {
+		expected = collections.ErrNodeIsNotElement
+	}
+
+	switch op {
+	case linkedListInsertAfter, linkedListInsertBefore:
+		method, value := "InsertBefore", ops.value()
+		if op == linkedListInsertAfter {
+			method = "InsertAfter"
+		}
+		ops.record("%s(node at %d, %d)", method, index, value)
+		var (
+			err  error
+			node collections.ListNode[int]
+		)
+		if op == linkedListInsertAfter {
+			node, err = list.InsertAfter(h.node, value)
+			index++
+		} else {
+			node, err = list.InsertBefore(h.node, value)
+		}
+		checkError(t, method, err, expected)
+		if err == nil {
+			checkResult(t, method, node.Value(), value)
+			element := &listElement{value}
+			model = slices.Insert(model, index, element)
+			held = hold(held, element, node)
+		}
+	case linkedListRemoveNode:
+		ops.record("RemoveNode(node at %d)", index)
+		checkError(t, "RemoveNode", list.RemoveNode(h.node), expected)
+		if expected == nil {
+			model = slices.Delete(model, index, index+1)
+		}
+	case linkedListSetValue:
+		value := ops.value()
+		ops.record("SetValue(node at %d, %d)", index, value)
+		h.node.SetValue(value)
+		h.element.value = value
+	}
+
+	return model, held
+}
+
+func testLinkedListFindNode(t *testing.T, newList func() collections.LinkedList[int]) {
+	list := newList()
+	if _, err := list.FindNode(0); err == nil || !errors.Is(err, collections.ErrNoSuchElement) {
+		t.Fatalf("expected ErrNoSuchElement from FindNode on empty list but got: %v", err)
+	}
+
+	for i := 0; i < 1000; i++ {
+		list.Add(i % 100)
+	}
+	for i := 0; i < 100; i++ {
+		node, err := list.FindNode(i)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if node.Value() != i {
+			t.Fatalf("expected node with value %d but got %d", i, node.Value())
+		}
+		// the node must be the first match, at index i
+		if indexed, err := list.GetNode(i); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if indexed != node {
+			t.Fatalf("expected FindNode(%d) to return the node at index %d", i, i)
+		}
+	}
+	if _, err := list.FindNode(100); err == nil || !errors.Is(err, collections.ErrNoSuchElement) {
+		t.Fatalf("expected ErrNoSuchElement from FindNode for a missing value but got: %v", err)
+	}
+}
+
+func testLinkedListGetNode(t *testing.T, newList func() collections.LinkedList[int]) {
+	list := newList()
+	for _, index := range []int{-1, 0, 1} {
+		if _, err := list.GetNode(index); err == nil || !errors.Is(err, collections.ErrEmptyList) {
+			t.Fatalf("expected ErrEmptyList from GetNode(%d) on empty list but got: %v", index, err)
+		}
+	}
+
+	list = fillLinkedList(newList, sequence(1000))
+	for i := 0; i < 1000; i++ {
+		if node, err := list.GetNode(i); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if node.Value() != i {
+			t.Fatalf("expected node with value %d but got %d", i, node.Value())
+		}
+	}
+	for _, index := range []int{-1, 1000} {
+		_, err := list.GetNode(index)
+		checkIndexError(t, err, index, 1000)
+	}
+}
+
+func testLinkedListHead(t *testing.T, newList func() collections.LinkedList[int]) {
+	list := newList()
+	if !isNil(list.Head()) {
+		t.Fatal("expected head node of new list to be nil")
+	}
+
+	for i := 999; i >= 0; i-- {
+		if i == 999 {
+			list.Add(i)
+		} else if err := list.Insert(0, i); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		if head := list.Head(); head.Value() != i {
+			t.Fatalf("expected head node to have value %d but got %d", i, head.Value())
+		} else if !isNil(head.Previous()) {
+			t.Fatal("expected head node to have no previous node")
+		}
+	}
+	for i := 0; i < 1000; i++ {
+		if head := list.Head(); head.Value() != i {
+			t.Fatalf("expected head node to have value %d but got %d", i, head.Value())
+		}
+		list.Remove(0)
+	}
+	checkLinkedList(t, list, nil)
+}
+
+func testLinkedListInsertAfter(t *testing.T, newList func() collections.LinkedList[int]) {
+	list := fillLinkedList(newList, []int{0})
+	if _, err := list.InsertAfter(&foreignNode{}, 0); err == nil || !errors.Is(err, collections.ErrWrongNodeType) {
+		t.Fatalf("expected ErrWrongNodeType from InsertAfter with a foreign node but got: %v", err)
+	}
+	other := fillLinkedList(newList, []int{0})
+	if _, err := list.InsertAfter(other.Head(), 0); err == nil || !errors.Is(err, collections.ErrNodeIsNotElement) {
+		t.Fatalf("expected ErrNodeIsNotElement from InsertAfter with a node of another list but got: %v", err)
+	}
+	checkLinkedList(t, list, []int{0})
+
+	// after the tail, then after every node from the head
+	expected := []int{0}
+	node := list.Head()
+	for i := 1; i < 500; i++ {
+		var err error
+		if node, err = list.InsertAfter(node, i); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if node.Value() != i {
+			t.Fatalf("expected new node to have value %d but got %d", i, node.Value())
+		}
+		expected = append(expected, i)
+	}
+	checkLinkedList(t, list, expected)
+
+	for i, node := 0, list.Head(); i < 500; i++ {
+		inserted, err := list.InsertAfter(node, -i)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		node = inserted.Next()
+	}
+	expected = nil
+	for i := 0; i < 500; i++ {
+		expected = append(expected, i, -i)
+	}
+	checkLinkedList(t, list, expected)
+}
+
+func testLinkedListInsertBefore(t *testing.T, newList func() collections.LinkedList[int]) {
+	list := fillLinkedList(newList, []int{0})
+	if _, err := list.InsertBefore(&foreignNode{}, 0); err == nil || !errors.Is(err, collections.ErrWrongNodeType) {
+		t.Fatalf("expected ErrWrongNodeType from InsertBefore with a foreign node but got: %v", err)
+	}
+	other := fillLinkedList(newList, []int{0})
+	if _, err := list.InsertBefore(other.Head(), 0); err == nil || !errors.Is(err, collections.ErrNodeIsNotElement) {
+		t.Fatalf("expected ErrNodeIsNotElement from InsertBefore with a node of another list but got: %v", err)
+	}
+	checkLinkedList(t, list, []int{0})
+
+	// before the head, then before every node from the tail
+	expected := []int{0}
+	node := list.Head()
+	for i := 1; i < 500; i++ {
+		var err error
+		if node, err = list.InsertBefore(node, i); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if node.Value() != i {
+			t.Fatalf("expected new node to have value %d but got %d", i, node.Value())
+		}
+		expected = append([]int{i}, expected...)
+	}
+	checkLinkedList(t, list, expected)
+
+	for i, node := 0, list.Tail(); i < 500; i++ {
+		inserted, err := list.InsertBefore(node, -i)
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		node = inserted.Previous()
+	}
+	expected = nil
+	for i := 499; i >= 0; i-- {
+		expected = append(expected, -i, i)
+	}
+	checkLinkedList(t, list, expected)
+}
+
+func testLinkedListNodes(t *testing.T, newList func() collections.LinkedList[int]) {
+	list := fillLinkedList(newList, sequence(1000))
+	checkLinkedList(t, list, sequence(1000))
+
+	expected := make([]int, 1000)
+	for i, node := 0, list.Head(); !isNil(node); i, node = i+1, node.Next() {
+		node.SetValue(i * 2)
+		if node.Value() != i*2 {
+			t.Fatalf("expected node to have value %d after SetValue but got %d", i*2, node.Value())
+		}
+		expected[i] = i * 2
+	}
+	checkLinkedList(t, list, expected)
+
+	list.Sort(func(a, b int) bool { return a > b })
+	checkLinkedList(t, list, reversed(expected))
+
+	// looking up nodes and changing a node that has been removed leave an iterator valid
+	list = fillLinkedList(newList, sequence(10))
+	removed := list.Head()
+	list.RemoveNode(removed)
+	itr := list.Iterator()
+	itr()
+	list.FindNode(5)
+	list.GetNode(5)
+	list.Head().Next()
+	list.Tail().Previous()
+	removed.SetValue(10)
+	list.InsertAfter(removed, 10)
+	list.RemoveNode(removed)
+	checkIterator(t, itr, sequence(10)[2:])
+
+	modifications := []struct {
+		method string
+		modify func(collections.LinkedList[int])
+	}{
+		{"InsertAfter", func(list collections.LinkedList[int]) { list.InsertAfter(list.Head(), 10) }},
+		{"InsertBefore", func(list collections.LinkedList[int]) { list.InsertBefore(list.Tail(), 10) }},
+		{"RemoveNode", func(list collections.LinkedList[int]) { list.RemoveNode(list.Tail()) }},
+		{"SetValue", func(list collections.LinkedList[int]) { list.Head().SetValue(10) }},
+	}
+	for _, m := range modifications {
+		list = fillLinkedList(newList, sequence(10))
+		itr = list.Iterator()
+		itr()
+		m.modify(list)
+		checkInvalidated(t, itr, m.method)
+	}
+}
+
+func testLinkedListRemoveNode(t *testing.T, newList func() collections.LinkedList[int]) {
+	list := fillLinkedList(newList, sequence(100))
+	if err := list.RemoveNode(&foreignNode{}); err == nil || !errors.Is(err, collections.ErrWrongNodeType) {
+		t.Fatalf("expected ErrWrongNodeType from RemoveNode with a foreign node but got: %v", err)
+	}
+	other := fillLinkedList(newList, []int{0})
+	if err := list.RemoveNode(other.Head()); err == nil || !errors.Is(err, collections.ErrNodeIsNotElement) {
+		t.Fatalf("expected ErrNodeIsNotElement from RemoveNode with a node of another list but got: %v", err)
+	}
+	checkLinkedList(t, list, sequence(100))
+
+	// the middle, the head and the tail, then every remaining node
+	expected := sequence(100)
+	for _, index := range []int{50, 0, 97} {
+		node, _ := list.GetNode(index)
+		if err := list.RemoveNode(node); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		expected = slices.Delete(expected, index, index+1)
+		checkLinkedList(t, list, expected)
+
+		if err := list.RemoveNode(node); err == nil || !errors.Is(err, collections.ErrNodeIsNotElement) {
+			t.Fatalf("expected ErrNodeIsNotElement from removing a node twice but got: %v", err)
+		}
+		if _, err := list.InsertAfter(node, 0); err == nil || !errors.Is(err, collections.ErrNodeIsNotElement) {
+			t.Fatalf("expected ErrNodeIsNotElement from InsertAfter with a removed node but got: %v", err)
+		}
+		checkLinkedList(t, list, expected)
+	}
+	for i := 0; len(expected) > 0; i++ {
+		index := i * 7919 % len(expected)
+		node, _ := list.GetNode(index)
+		if err := list.RemoveNode(node); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		expected = slices.Delete(expected, index, index+1)
+		checkLinkedList(t, list, expected)
+	}
+
+	list.Add(0)
+	checkLinkedList(t, list, []int{0})
+}
+
+func testLinkedListTail(t *testing.T, newList func() collections.LinkedList[int]) {
+	list := newList()
+	if !isNil(list.Tail()) {
+		t.Fatal("expected tail node of new list to be nil")
+	}
+
+	for i := 0; i < 1000; i++ {
+		list.Add(i)
+		if tail := list.Tail(); tail.Value() != i {
+			t.Fatalf("expected tail node to have value %d but got %d", i, tail.Value())
+		} else if !isNil(tail.Next()) {
+			t.Fatal("expected tail node to have no next node")
+		}
+	}
+	for i := 999; i >= 0; i-- {
+		if tail := list.Tail(); tail.Value() != i {
+			t.Fatalf("expected tail node to have value %d but got %d", i, tail.Value())
+		}
+		list.Remove(i)
+	}
+	checkLinkedList(t, list, nil)
+}
@@ -0,0 +1,717 @@
+// ©2022 Brandon Moller
+
+package collectionstest
+
+import (
+	"cmp"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/bmoller/collections"
+)
+
+// opcodes for the List operations of FuzzList and FuzzLinkedList
+const (
+	listAdd = iota
+	listClear
+	listGet
+	listIndexFunc
+	listInsert
+	listRemove
+	listSearch
+	listSet
+	listSort
+	listSubList
+	listOperations // the number of opcodes handled by stepList
+)
+
+// listElement boxes an element of a listModel, so that the nodes of a LinkedList can be matched with the elements they hold.
+type listElement struct {
+	value int
+}
+
+// listModel is the reference List that FuzzList and FuzzLinkedList compare against.
+type listModel []*listElement
+
+// index returns the position of element in the model, or -1 if it is not a member.
+func (m listModel) index(element *listElement) int {
+	return slices.Index(m, element)
+}
+
+// indexError returns the error expected from an access to index, or nil if it is valid.
+func (m listModel) indexError(index int) error {
+	switch {
+	case len(m) == 0:
+		return collections.ErrEmptyList
+	case index < 0 || index >= len(m):
+		return collections.ErrIndexOutOfRange{
+			Index: index,
+			Size:  len(m),
+		}
+	}
+
+	return nil
+}
+
+// values returns the elements of the model.
+func (m listModel) values() []int {
+	values := make([]int, len(m))
+	for i, element := range m {
+		values[i] = element.value
+	}
+
+	return values
+}
+
+/*
+FuzzList applies the operations decoded from each input to one of the Lists returned by newList and to a reference List backed by a slice.
+It fails if their results or errors differ, or if their elements differ after any operation.
+*/
+func FuzzList(f *testing.F, newList func() collections.List[int]) {
+	addSeeds(f)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		ops := newOperations(t, data)
+		list := newList()
+		var model listModel
+		for op, ok := ops.next(listOperations); ok; op, ok = ops.next(listOperations) {
+			model = stepList(t, ops, op, list, model)
+			checkList(t, list, model.values())
+		}
+	})
+}
+
+/*
+TestList runs the conformance tests for [collections.List] against the Lists returned by newList.
+*/
+func TestList(t *testing.T, newList func() collections.List[int]) {
+	t.Run("Add", func(t *testing.T) { testListAdd(t, newList) })
+	t.Run("All", func(t *testing.T) { testListAll(t, newList) })
+	t.Run("Backward", func(t *testing.T) { testListBackward(t, newList) })
+	t.Run("Clear", func(t *testing.T) { testListClear(t, newList) })
+	t.Run("Contains", func(t *testing.T) { testListContains(t, newList) })
+	t.Run("Empty", func(t *testing.T) { testListEmpty(t, newList) })
+	t.Run("Get", func(t *testing.T) { testListGet(t, newList) })
+	t.Run("IndexFunc", func(t *testing.T) { testListIndexFunc(t, newList) })
+	t.Run("IndexOf", func(t *testing.T) { testListIndexOf(t, newList) })
+	t.Run("Insert", func(t *testing.T) { testListInsert(t, newList) })
+	t.Run("Iterator", func(t *testing.T) { testListIterator(t, newList) })
+	t.Run("LastIndexOf", func(t *testing.T) { testListLastIndexOf(t, newList) })
+	t.Run("Remove", func(t *testing.T) { testListRemove(t, newList) })
+	t.Run("RemovingIterator", func(t *testing.T) { testListRemovingIterator(t, newList) })
+	t.Run("Set", func(t *testing.T) { testListSet(t, newList) })
+	t.Run("Size", func(t *testing.T) { testListSize(t, newList) })
+	t.Run("Sort", func(t *testing.T) { testListSort(t, newList) })
+	t.Run("SortStable", func(t *testing.T) { testListSortStable(t, newList) })
+	t.Run("SubList", func(t *testing.T) { testListSubList(t, newList) })
+	t.Run("Values", func(t *testing.T) { testListValues(t, newList) })
+}
+
+// checkList fails the test unless list holds exactly the elements of expected, in order, in both directions.
+func checkList(t *testing.T, list collections.List[int], expected []int) {
+	t.Helper()
+
+	checkSize(t, list, len(expected))
+	checkIterable(t, list, expected)
+	checkBackward(t, list.Backward(), expected)
+}
+
+// fillList adds the elements of elements to a new List.
+func fillList(newList func() collections.List[int], elements []int) collections.List[int] {
+	list := newList()
+	for _, element := range elements {
+		list.Add(element)
+	}
+
+	return list
+}
+
+// stepList applies the List operation op to both list and model, and returns the updated model.
+func stepList(t *testing.T, ops *operations, op int, list collections.List[int], model listModel) listModel {
+	t.Helper()
+
+	switch op {
+	case listAdd:
+		value := ops.value()
+		ops.record("Add(%d)", value)
+		list.Add(value)
+		model = append(model, &listElement{value})
+	case listClear:
+		ops.record("Clear()")
+		list.Clear()
+		model = nil
+	case listGet:
+		index := ops.index(len(model))
+		ops.record("Get(%d)", index)
+		element, err := list.Get(index)
+		checkError(t, "Get", err, model.indexError(index))
+		if err == nil {
+			checkResult(t, "Get", element, model[index].value)
+		}
+	case listIndexFunc:
+		value := ops.value()
+		ops.record("IndexFunc(>= %d)", value)
+		atLeast := func(element int) bool { return element >= value }
+		expected := slices.IndexFunc(model.values(), atLeast)
+		checkResult(t, "IndexFunc", list.IndexFunc(atLeast), expected)
+	case listInsert:
+		index, value := ops.index(len(model)), ops.value()
+		ops.record("Insert(%d, %d)", index, value)
+		var expected error
+		if index < 0 || index > len(model) {
+			expected = collections.ErrIndexOutOfRange{
+				Index: index,
+				Size:  len(model),
+			}
+		}
+		checkError(t, "Insert", list.Insert(index, value), expected)
+		if expected == nil {
+			model = slices.Insert(model, index, &listElement{value})
+		}
+	case listRemove:
+		index := ops.index(len(model))
+		ops.record("Remove(%d)", index)
+		element, err := list.Remove(index)
+		checkError(t, "Remove", err, model.indexError(index))
+		if err == nil {
+			checkResult(t, "Remove", element, model[index].value)
+			model = slices.Delete(model, index, index+1)
+		}
+	case listSearch:
+		value := ops.value()
+		ops.record("Contains, IndexOf and LastIndexOf(%d)", value)
+		values := model.values()
+		checkResult(t, "Contains", list.Contains(value), slices.Contains(values, value))
+		checkResult(t, "IndexOf", list.IndexOf(value), slices.Index(values, value))
+		last := slices.Index(reversed(values), value)
+		if last != -1 {
+			last = len(values) - 1 - last
+		}
+		checkResult(t, "LastIndexOf", list.LastIndexOf(value), last)
+	case listSet:
+		index, value := ops.index(len(model)), ops.value()
+		ops.record("Set(%d, %d)", index, value)
+		element, err := list.Set(index, value)
+		checkError(t, "Set", err, model.indexError(index))
+		if err == nil {
+			checkResult(t, "Set", element, model[index].value)
+			model[index].value = value
+		}
+	case listSort:
+		flags := ops.byte()
+		compare := cmp.Compare[int]
+		if flags&1 != 0 {
+			compare = func(a, b int) int { return cmp.Compare(b, a) }
+		}
+		less := func(a, b int) bool { return compare(a, b) < 0 }
+		if flags&2 != 0 {
+			ops.record("SortStable(descending: %t)", flags&1 != 0)
+			list.SortStable(less)
+		} else {
+			ops.record("Sort(descending: %t)", flags&1 != 0)
+			list.Sort(less)
+		}
+		slices.SortStableFunc(model, func(a, b *listElement) int { return compare(a.value, b.value) })
+	case listSubList:
+		start, end := ops.index(len(model)), ops.index(len(model))
+		ops.record("SubList(%d, %d)", start, end)
+		var expected error
+		switch {
+		case len(model) == 0:
+			expected = collections.ErrEmptyList
+		case start < 0 || end < start:
+			expected = collections.ErrInvalidRange{
+				End:   end,
+				Start: start,
+			}
+		case start >= len(model):
+			expected = collections.ErrIndexOutOfRange{
+				Index: start,
+				Size:  len(model),
+			}
+		case end > len(model):
+			expected = collections.ErrIndexOutOfRange{
+				Index: end,
+				Size:  len(model),
+			}
+		}
+		subList, err := list.SubList(start, end)
+		checkError(t, "SubList", err, expected)
+		if err == nil {
+			values := model.values()[start:end]
+			checkList(t, subList, values)
+			subList.Add(-1)
+			checkList(t, subList, append(values, -1))
+		}
+	}
+
+	return model
+}
+
+func testListAdd(t *testing.T, newList func() collections.List[int]) {
+	list := newList()
+	for i := 0; i < 1000; i++ {
+		list.Add(i)
+		if list.Size() != i+1 {
+			t.Fatalf("expected list size %d but got %d", i+1, list.Size())
+		}
+	}
+	checkList(t, list, sequence(1000))
+}
+
+func testListAll(t *testing.T, newList func() collections.List[int]) {
+	list := newList()
+	checkSeq(t, "All", list.All(), nil)
+
+	list = fillList(newList, sequence(1000))
+	checkSeq(t, "All", list.All(), sequence(1000))
+	for i := range list.All() {
+		if i == 10 {
+			break
+		}
+	}
+}
+
+func testListBackward(t *testing.T, newList func() collections.List[int]) {
+	list := newList()
+	for range list.Backward() {
+		t.Fatal("expected no elements from Backward on empty list")
+	}
+
+	list = fillList(newList, sequence(1000))
+	checkList(t, list, sequence(1000))
+	for i := range list.Backward() {
+		if i == 990 {
+			break
+		}
+	}
+}
+
+func testListClear(t *testing.T, newList func() collections.List[int]) {
+	list := newList()
+	list.Clear()
+	checkList(t, list, nil)
+
+	for i := 1; i < 1001; i *= 10 {
+		list := fillList(newList, sequence(i))
+		list.Clear()
+		checkList(t, list, nil)
+		if _, err := list.Get(0); err == nil || !errors.Is(err, collections.ErrEmptyList) {
+			t.Fatalf("expected ErrEmptyList from Get after Clear but got: %v", err)
+		}
+
+		for j := 0; j < 10; j++ {
+			list.Add(j)
+		}
+		checkList(t, list, sequence(10))
+	}
+}
+
+func testListContains(t *testing.T, newList func() collections.List[int]) {
+	list := newList()
+	if list.Contains(0) {
+		t.Fatal("expected empty list not to contain any element")
+	}
+
+	for i := 0; i < 1000; i += 2 {
+		list.Add(i)
+	}
+	for i := -1; i < 1001; i++ {
+		if list.Contains(i) != (i >= 0 && i < 1000 && i%2 == 0) {
+			t.Fatalf("unexpected result from Contains for %d", i)
+		}
+	}
+}
+
+func testListEmpty(t *testing.T, newList func() collections.List[int]) {
+	list := newList()
+	if !list.Empty() {
+		t.Fatal("expected new list to be empty")
+	}
+	list.Add(0)
+	if list.Empty() {
+		t.Fatal("expected list to not be empty after adding element")
+	}
+	list.Remove(0)
+	if !list.Empty() {
+		t.Fatal("expected list to be empty after removing its only element")
+	}
+}
+
+func testListGet(t *testing.T, newList func() collections.List[int]) {
+	list := newList()
+	for _, index := range []int{-1, 0, 1} {
+		if _, err := list.Get(index); err == nil || !errors.Is(err, collections.ErrEmptyList) {
+			t.Fatalf("expected ErrEmptyList from Get(%d) on empty list but got: %v", index, err)
+		}
+	}
+
+	list = fillList(newList, sequence(1000))
+	for i := 0; i < 1000; i++ {
+		if element, err := list.Get(i); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if element != i {
+			t.Fatalf("expected element with value %d but got %d", i, element)
+		}
+	}
+	for _, index := range []int{-1, 1000, 1001} {
+		_, err := list.Get(index)
+		checkIndexError(t, err, index, 1000)
+	}
+}
+
+func testListIndexFunc(t *testing.T, newList func() collections.List[int]) {
+	list := newList()
+	if index := list.IndexFunc(func(int) bool { return true }); index != -1 {
+		t.Fatalf("expected index %d from empty list but got %d", -1, index)
+	}
+
+	for i := 0; i < 1000; i++ {
+		list.Add(i % 100)
+	}
+	if index := list.IndexFunc(func(value int) bool { return value > 49 }); index != 50 {
+		t.Fatalf("expected index %d but got %d", 50, index)
+	}
+	if index := list.IndexFunc(func(value int) bool { return value < 0 }); index != -1 {
+		t.Fatalf("expected index %d but got %d", -1, index)
+	}
+}
+
+func testListIndexOf(t *testing.T, newList func() collections.List[int]) {
+	list := newList()
+	if index := list.IndexOf(0); index != -1 {
+		t.Fatalf("expected index %d from empty list but got %d", -1, index)
+	}
+
+	for i := 0; i < 1000; i++ {
+		list.Add(i % 100)
+	}
+	for i := 0; i < 100; i++ {
+		if index := list.IndexOf(i); index != i {
+			t.Fatalf("expected index %d but got %d", i, index)
+		}
+	}
+	if index := list.IndexOf(100); index != -1 {
+		t.Fatalf("expected index %d but got %d", -1, index)
+	}
+}
+
+func testListInsert(t *testing.T, newList func() collections.List[int]) {
+	list := newList()
+	for _, index := range []int{-1, 1} {
+		err := list.Insert(index, 0)
+		checkIndexError(t, err, index, 0)
+	}
+	if err := list.Insert(0, 0); err != nil {
+		t.Fatalf("unexpected error from Insert on empty list: %s", err)
+	}
+	checkList(t, list, []int{0})
+
+	// every position is used, including the front and the back
+	expected := []int{0}
+	for i := 1; i < 998; i++ {
+		index := i * 7919 % (len(expected) + 1)
+		if err := list.Insert(index, i); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+		expected = slices.Insert(expected, index, i)
+	}
+	if err := list.Insert(0, 998); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := list.Insert(list.Size(), 999); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected = append(append([]int{998}, expected...), 999)
+	checkList(t, list, expected)
+
+	for _, index := range []int{-1, 1001} {
+		err := list.Insert(index, 0)
+		checkIndexError(t, err, index, 1000)
+	}
+	checkList(t, list, expected)
+}
+
+func testListIterator(t *testing.T, newList func() collections.List[int]) {
+	list := newList()
+	if _, err := list.Iterator()(); err == nil || !errors.Is(err, collections.ErrNoMoreItems) {
+		t.Fatalf("expected ErrNoMoreItems from Iterator on empty list but got: %v", err)
+	}
+
+	list = fillList(newList, sequence(1000))
+	itr := list.Iterator()
+	for i := 0; i < 1000; i++ {
+		if element, err := itr(); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if element != i {
+			t.Fatalf("expected element with value %d but got %d", i, element)
+		}
+	}
+	if _, err := itr(); err == nil || !errors.Is(err, collections.ErrNoMoreItems) {
+		t.Fatalf("exhausted iterator should return ErrNoMoreItems but got %v", err)
+	}
+	if list.Size() != 1000 {
+		t.Fatalf("expected iteration to leave list size %d but got %d", 1000, list.Size())
+	}
+
+	// reads and calls that fail leave an iterator valid
+	list = fillList(newList, sequence(1000))
+	itr = list.Iterator()
+	itr()
+	list.Contains(500)
+	list.Get(500)
+	list.IndexOf(500)
+	list.SubList(0, 500)
+	list.Insert(-1, 0)
+	list.Remove(1000)
+	list.Set(1000, 0)
+	checkIterator(t, itr, sequence(1000)[1:])
+
+	less := func(a, b int) bool { return a > b }
+	modifications := []struct {
+		method string
+		modify func(collections.List[int])
+	}{
+		{"Add", func(list collections.List[int]) { list.Add(0) }},
+		{"Clear", func(list collections.List[int]) { list.Clear() }},
+		{"Insert", func(list collections.List[int]) { list.Insert(500, 0) }},
+		{"Remove", func(list collections.List[int]) { list.Remove(500) }},
+		{"Set", func(list collections.List[int]) { list.Set(500, 0) }},
+		{"Sort", func(list collections.List[int]) { list.Sort(less) }},
+		{"SortStable", func(list collections.List[int]) { list.SortStable(less) }},
+	}
+	for _, m := range modifications {
+		list = fillList(newList, sequence(1000))
+		itr = list.Iterator()
+		itr()
+		m.modify(list)
+		checkInvalidated(t, itr, m.method)
+	}
+}
+
+func testListLastIndexOf(t *testing.T, newList func() collections.List[int]) {
+	list := newList()
+	if index := list.LastIndexOf(0); index != -1 {
+		t.Fatalf("expected index %d from empty list but got %d", -1, index)
+	}
+
+	for i := 0; i < 1000; i++ {
+		list.Add(i % 100)
+	}
+	for i := 0; i < 100; i++ {
+		if index := list.LastIndexOf(i); index != 900+i {
+			t.Fatalf("expected index %d but got %d", 900+i, index)
+		}
+	}
+	if index := list.LastIndexOf(100); index != -1 {
+		t.Fatalf("expected index %d but got %d", -1, index)
+	}
+}
+
+func testListRemove(t *testing.T, newList func() collections.List[int]) {
+	list := newList()
+	for _, index := range []int{-1, 0, 1} {
+		if _, err := list.Remove(index); err == nil || !errors.Is(err, collections.ErrEmptyList) {
+			t.Fatalf("expected ErrEmptyList from Remove(%d) on empty list but got: %v", index, err)
+		}
+	}
+
+	expected := sequence(1000)
+	list = fillList(newList, expected)
+	for _, index := range []int{-1, 1000} {
+		_, err := list.Remove(index)
+		checkIndexError(t, err, index, 1000)
+	}
+	checkList(t, list, expected)
+
+	// the back and the front first, then positions spread through the list
+	for _, index := range []int{999, 998, 0, 0} {
+		if element, err := list.Remove(index); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if element != expected[index] {
+			t.Fatalf("expected element with value %d but got %d", expected[index], element)
+		}
+		expected = slices.Delete(expected, index, index+1)
+		checkList(t, list, expected)
+	}
+	for i := 0; len(expected) > 0; i++ {
+		index := i * 7919 % len(expected)
+		if element, err := list.Remove(index); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if element != expected[index] {
+			t.Fatalf("expected element with value %d but got %d", expected[index], element)
+		}
+		expected = slices.Delete(expected, index, index+1)
+		checkList(t, list, expected)
+	}
+
+	if _, err := list.Remove(0); err == nil || !errors.Is(err, collections.ErrEmptyList) {
+		t.Fatalf("expected ErrEmptyList from Remove after removing every element but got: %v", err)
+	}
+	for i := 0; i < 10; i++ {
+		list.Add(i)
+	}
+	checkList(t, list, sequence(10))
+}
+
+func testListRemovingIterator(t *testing.T, newList func() collections.List[int]) {
+	list := fillList(newList, sequence(1000))
+	checkRemovingIterator(t, list, func() { list.Remove(0) })
+	checkList(t, list, evens(1000)[1:])
+}
+
+func testListSet(t *testing.T, newList func() collections.List[int]) {
+	list := newList()
+	for _, index := range []int{-1, 0, 1} {
+		if _, err := list.Set(index, 0); err == nil || !errors.Is(err, collections.ErrEmptyList) {
+			t.Fatalf("expected ErrEmptyList from Set(%d) on empty list but got: %v", index, err)
+		}
+	}
+
+	list = fillList(newList, sequence(1000))
+	expected := make([]int, 1000)
+	for i := 0; i < 1000; i++ {
+		if old, err := list.Set(i, i*2); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if old != i {
+			t.Fatalf("expected replaced value %d but got %d", i, old)
+		}
+		expected[i] = i * 2
+	}
+	checkList(t, list, expected)
+
+	for _, index := range []int{-1, 1000} {
+		_, err := list.Set(index, 0)
+		checkIndexError(t, err, index, 1000)
+	}
+	checkList(t, list, expected)
+}
+
+func testListSize(t *testing.T, newList func() collections.List[int]) {
+	list := newList()
+	if list.Size() != 0 {
+		t.Fatalf("expected new list to have size %d but got %d", 0, list.Size())
+	}
+	for i := 1; i < 1001; i++ {
+		list.Add(i)
+		if list.Size() != i {
+			t.Fatalf("expected list size %d but got %d", i, list.Size())
+		}
+	}
+	for i := 999; i >= 0; i-- {
+		list.Remove(0)
+		if list.Size() != i {
+			t.Fatalf("expected list size %d but got %d", i, list.Size())
+		}
+	}
+}
+
+func testListSort(t *testing.T, newList func() collections.List[int]) {
+	list := newList()
+	list.Sort(func(a, b int) bool { return a < b })
+	checkList(t, list, nil)
+
+	elements := make([]int, 1000)
+	for i := range elements {
+		elements[i] = i * 7919 % 100
+	}
+	list = fillList(newList, elements)
+	list.Sort(func(a, b int) bool { return a < b })
+	checkList(t, list, slices.Sorted(slices.Values(elements)))
+
+	list.Sort(func(a, b int) bool { return a > b })
+	checkList(t, list, reversed(slices.Sorted(slices.Values(elements))))
+}
+
+func testListSortStable(t *testing.T, newList func() collections.List[int]) {
+	list := newList()
+	list.SortStable(func(a, b int) bool { return a < b })
+	checkList(t, list, nil)
+
+	// each element is a key in the thousands and its original position in the rest
+	elements := make([]int, 1000)
+	for i := range elements {
+		elements[i] = i*7919%10*1000 + i
+	}
+	list = fillList(newList, elements)
+	list.SortStable(func(a, b int) bool { return a/1000 < b/1000 })
+
+	expected := slices.Clone(elements)
+	slices.SortStableFunc(expected, func(a, b int) int { return a/1000 - b/1000 })
+	checkList(t, list, expected)
+}
+
+func testListSubList(t *testing.T, newList func() collections.List[int]) {
+	list := newList()
+	if _, err := list.SubList(0, 0); err == nil || !errors.Is(err, collections.ErrEmptyList) {
+		t.Fatalf("expected ErrEmptyList from SubList on empty list but got: %v", err)
+	}
+
+	list = fillList(newList, sequence(1000))
+	for _, bounds := range [][2]int{{-1, 10}, {10, 0}, {-10, -20}} {
+		_, err := list.SubList(bounds[0], bounds[1])
+		checkRangeError(t, err, bounds[0], bounds[1])
+	}
+	_, err := list.SubList(1000, 1005)
+	checkIndexError(t, err, 1000, 1000)
+	_, err = list.SubList(0, 1001)
+	checkIndexError(t, err, 1001, 1000)
+
+	subList, err := list.SubList(250, 750)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	checkList(t, subList, sequence(750)[250:])
+	if whole, err := list.SubList(0, 1000); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else {
+		checkList(t, whole, sequence(1000))
+	}
+
+	// the SubList is a copy, so changes to either list do not affect the other
+	subList.Set(0, -1)
+	subList.Add(-2)
+	list.Set(250, -3)
+	list.Remove(999)
+	if element, _ := list.Get(250); element != -3 {
+		t.Fatalf("expected element with value %d but got %d", -3, element)
+	}
+	if element, _ := subList.Get(0); element != -1 {
+		t.Fatalf("expected element with value %d but got %d", -1, element)
+	}
+	checkSize(t, list, 999)
+	checkSize(t, subList, 501)
+
+	empty, err := list.SubList(10, 10)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	checkList(t, empty, nil)
+	empty.Add(0)
+	checkList(t, empty, []int{0})
+}
+
+func testListValues(t *testing.T, newList func() collections.List[int]) {
+	list := newList()
+	for range list.Values() {
+		t.Fatal("expected no elements from Values on empty list")
+	}
+
+	list = fillList(newList, sequence(1000))
+	expected := 0
+	for element := range list.Values() {
+		if element != expected {
+			t.Fatalf("expected element with value %d but got %d", expected, element)
+		}
+		expected++
+	}
+	if expected != 1000 {
+		t.Fatalf("expected %d elements from Values but got %d", 1000, expected)
+	}
+
+	for element := range list.Values() {
+		if element == 10 {
+			break
+		}
+	}
+}
@@ -0,0 +1,105 @@
+// ©2022 Brandon Moller
+
+package collectionstest
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/bmoller/collections"
+)
+
+// opcodes for the Map operations of FuzzMap and FuzzSortedMap
+const (
+	mapClear = iota
+	mapContainsKey
+	mapDelete
+	mapGet
+	mapPut
+	mapOperations // the number of opcodes handled by stepMap
+)
+
+/*
+FuzzMap applies the operations decoded from each input to one of the Maps returned by newMap and to a reference Map backed by a map.
+It fails if their results or errors differ, or if their entries differ after any operation.
+As the order of a Map's entries is up to each implementation, and may change from one iteration to the next, Keys and Values are only checked to produce the same keys and values as Entries.
+*/
+func FuzzMap(f *testing.F, newMap func() collections.Map[int, int]) {
+	addSeeds(f)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		ops := newOperations(t, data)
+		m := newMap()
+		model := make(map[int]int)
+		for op, ok := ops.next(mapOperations); ok; op, ok = ops.next(mapOperations) {
+			stepMap(t, ops, op, m, model)
+			checkMap(t, m, model)
+		}
+	})
+}
+
+// checkMap fails the test unless m holds exactly the entries of expected, and Keys and Values agree with Entries.
+// The keys produced by Entries are returned in the order produced.
+func checkMap(t *testing.T, m collections.Map[int, int], expected map[int]int) []int {
+	t.Helper()
+
+	checkSize(t, m, len(expected))
+	var keys, values []int
+	for key, value := range m.Entries() {
+		if len(keys) == len(expected) {
+			t.Fatalf("expected %d entries from Entries but got more", len(expected))
+		} else if expectedValue, ok := expected[key]; !ok {
+			t.Fatalf("unexpected key %d from Entries", key)
+		} else if value != expectedValue {
+			t.Fatalf("expected value %d for key %d from Entries but got %d", expectedValue, key, value)
+		} else if slices.Contains(keys, key) {
+			t.Fatalf("key %d returned more than once by Entries", key)
+		}
+		keys, values = append(keys, key), append(values, value)
+	}
+	if len(keys) != len(expected) {
+		t.Fatalf("expected %d entries from Entries but got %d", len(expected), len(keys))
+	} else if actual, keys := slices.Sorted(m.Keys()), slices.Sorted(slices.Values(keys)); !slices.Equal(actual, keys) {
+		t.Fatalf("expected %v from Keys but got %v", keys, actual)
+	} else if actual, values := slices.Sorted(m.Values()), slices.Sorted(slices.Values(values)); !slices.Equal(actual, values) {
+		t.Fatalf("expected %v from Values but got %v", values, actual)
+	}
+
+	return keys
+}
+
+// stepMap applies the Map operation op to both m and model.
+func stepMap(t *testing.T, ops *operations, op int, m collections.Map[int, int], model map[int]int) {
+	t.Helper()
+
+	switch op {
+	case mapClear:
+		ops.record("Clear()")
+		m.Clear()
+		clear(model)
+	case mapContainsKey:
+		key := ops.key()
+		ops.record("ContainsKey(%d)", key)
+		_, ok := model[key]
+		checkResult(t, "ContainsKey", m.ContainsKey(key), ok)
+	case mapDelete:
+		key := ops.key()
+		ops.record("Delete(%d)", key)
+		m.Delete(key)
+		delete(model, key)
+	case mapGet:
+		key := ops.key()
+		ops.record("Get(%d)", key)
+		value, err := m.Get(key)
+		if expected, ok := model[key]; ok {
+			checkError(t, "Get", err, nil)
+			checkResult(t, "Get", value, expected)
+		} else {
+			checkError(t, "Get", err, collections.ErrKeyNotFound)
+		}
+	case mapPut:
+		key, value := ops.key(), ops.value()
+		ops.record("Put(%d, %d)", key, value)
+		m.Put(key, value)
+		model[key] = value
+	}
+}
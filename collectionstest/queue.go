@@ -0,0 +1,330 @@
+// ©2022 Brandon Moller
+
+package collectionstest
+
+import (
+	"errors"
+	"io"
+	"slices"
+	"testing"
+
+	"github.com/bmoller/collections"
+)
+
+// opcodes for the operations of FuzzQueue
+const (
+	queuePeek = iota
+	queuePop
+	queuePush
+	queueOperations // the number of opcodes
+)
+
+/*
+FuzzPriorityQueue applies the operations decoded from each input to one of the Queues returned by newQueue and to a reference model kept as a sorted slice.
+The Queues must return lower values first, as one ordered by [cmp.Less] does.
+It fails if their results or errors differ, or if their elements differ after any operation.
+*/
+func FuzzPriorityQueue(f *testing.F, newQueue func() collections.Queue[int]) {
+	fuzzQueue(f, newQueue, func(model []int, value int) []int {
+		i, _ := slices.BinarySearch(model, value)
+		return slices.Insert(model, i, value)
+	})
+}
+
+/*
+FuzzQueue applies the operations decoded from each input to one of the Queues returned by newQueue and to a reference Queue backed by a slice.
+It fails if their results or errors differ, or if their elements differ after any operation.
+A Queue that implements [io.Closer] is closed once its input is done.
+*/
+func FuzzQueue(f *testing.F, newQueue func() collections.Queue[int]) {
+	fuzzQueue(f, newQueue, func(model []int, value int) []int {
+		return append(model, value)
+	})
+}
+
+/*
+TestQueue runs the conformance tests for [collections.Queue] against the Queues returned by newQueue.
+Elements are always pushed in ascending order, so a priority queue that returns lower values first can be tested as well.
+*/
+func TestQueue(t *testing.T, newQueue func() collections.Queue[int]) {
+	t.Run("All", func(t *testing.T) { testQueueAll(t, newQueue) })
+	t.Run("Empty", func(t *testing.T) { testQueueEmpty(t, newQueue) })
+	t.Run("Iterator", func(t *testing.T) { testQueueIterator(t, newQueue) })
+	t.Run("Peek", func(t *testing.T) { testQueuePeek(t, newQueue) })
+	t.Run("Pop", func(t *testing.T) { testQueuePop(t, newQueue) })
+	t.Run("Push", func(t *testing.T) { testQueuePush(t, newQueue) })
+	t.Run("RemovingIterator", func(t *testing.T) { testQueueRemovingIterator(t, newQueue) })
+	t.Run("Size", func(t *testing.T) { testQueueSize(t, newQueue) })
+	t.Run("Values", func(t *testing.T) { testQueueValues(t, newQueue) })
+}
+
+// checkQueue fails the test unless queue holds exactly the elements of expected, from front to back.
+func checkQueue(t *testing.T, queue collections.Queue[int], expected []int) {
+	t.Helper()
+
+	checkSize(t, queue, len(expected))
+	checkIterable(t, queue, expected)
+}
+
+// fillQueue pushes the elements of elements to a new Queue.
+func fillQueue(newQueue func() collections.Queue[int], elements []int) collections.Queue[int] {
+	queue := newQueue()
+	for _, element := range elements {
+		queue.Push(element)
+	}
+
+	return queue
+}
+
+// fuzzQueue runs the operations of FuzzQueue, using insert to add each pushed value to the model in the order in which it should be returned.
+func fuzzQueue(f *testing.F, newQueue func() collections.Queue[int], insert func(model []int, value int) []int) {
+	addSeeds(f)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		ops := newOperations(t, data)
+		queue := newQueue()
+		if closer, ok := queue.(io.Closer); ok {
+			defer closer.Close()
+		}
+		var model []int
+		for op, ok := ops.next(queueOperations); ok; op, ok = ops.next(queueOperations) {
+			switch op {
+			case queuePeek, queuePop:
+				var (
+					element int
+					err     error
+				)
+				method := "Peek"
+				if op == queuePop {
+					method = "Pop"
+					element, err = queue.Pop()
+				} else {
+					element, err = queue.Peek()
+				}
+				ops.record("%s()", method)
+				if len(model) == 0 {
+					checkError(t, method, err, collections.ErrEmptyQueue)
+					break
+				}
+				checkError(t, method, err, nil)
+				checkResult(t, method, element, model[0])
+				if op == queuePop {
+					model = model[1:]
+				}
+			case queuePush:
+				value := ops.value()
+				ops.record("Push(%d)", value)
+				queue.Push(value)
+				model = insert(model, value)
+			}
+			checkQueue(t, queue, model)
+		}
+	})
+}
+
+func testQueueAll(t *testing.T, newQueue func() collections.Queue[int]) {
+	queue := newQueue()
+	checkSeq(t, "All", queue.All(), nil)
+
+	queue = fillQueue(newQueue, sequence(1000))
+	checkSeq(t, "All", queue.All(), sequence(1000))
+	for i := range queue.All() {
+		if i == 10 {
+			break
+		}
+	}
+	checkQueue(t, queue, sequence(1000))
+}
+
+func testQueueEmpty(t *testing.T, newQueue func() collections.Queue[int]) {
+	queue := newQueue()
+	if !queue.Empty() {
+		t.Fatal("expected new queue to be empty")
+	}
+	queue.Push(1)
+	if queue.Empty() {
+		t.Fatal("expected queue to not be empty after pushing an item")
+	}
+	queue.Pop()
+	if !queue.Empty() {
+		t.Fatal("expected queue to be empty after popping its only item")
+	}
+}
+
+func testQueueIterator(t *testing.T, newQueue func() collections.Queue[int]) {
+	queue := newQueue()
+	if _, err := queue.Iterator()(); err == nil || !errors.Is(err, collections.ErrNoMoreItems) {
+		t.Fatalf("expected ErrNoMoreItems from Iterator on empty queue but got: %v", err)
+	}
+
+	queue = fillQueue(newQueue, sequence(1000))
+	itr := queue.Iterator()
+	for i := 0; i < 1000; i++ {
+		if element, err := itr(); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if element != i {
+			t.Fatalf("expected element with value %d but got %d", i, element)
+		}
+	}
+	if _, err := itr(); err == nil || !errors.Is(err, collections.ErrNoMoreItems) {
+		t.Fatalf("exhausted iterator should return ErrNoMoreItems but got %v", err)
+	}
+	if queue.Size() != 1000 {
+		t.Fatalf("expected iteration to leave queue size %d but got %d", 1000, queue.Size())
+	}
+
+	// Peek and a Pop that fails leave an iterator valid
+	queue = newQueue()
+	itr = queue.Iterator()
+	queue.Pop()
+	if _, err := itr(); err == nil || !errors.Is(err, collections.ErrNoMoreItems) {
+		t.Fatalf("expected ErrNoMoreItems from Iterator after a failed Pop but got: %v", err)
+	}
+	queue = fillQueue(newQueue, sequence(10))
+	itr = queue.Iterator()
+	itr()
+	queue.Peek()
+	checkIterator(t, itr, sequence(10)[1:])
+
+	modifications := []struct {
+		method string
+		modify func(collections.Queue[int])
+	}{
+		{"Pop", func(queue collections.Queue[int]) { queue.Pop() }},
+		{"Push", func(queue collections.Queue[int]) { queue.Push(10) }},
+	}
+	for _, m := range modifications {
+		queue = fillQueue(newQueue, sequence(10))
+		itr = queue.Iterator()
+		itr()
+		m.modify(queue)
+		checkInvalidated(t, itr, m.method)
+	}
+}
+
+func testQueuePeek(t *testing.T, newQueue func() collections.Queue[int]) {
+	queue := newQueue()
+	if _, err := queue.Peek(); err == nil || !errors.Is(err, collections.ErrEmptyQueue) {
+		t.Fatalf("expected ErrEmptyQueue from Peek on new queue but got: %v", err)
+	}
+
+	for i := 0; i < 1000; i++ {
+		queue.Push(i)
+		if element, err := queue.Peek(); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if element != 0 {
+			t.Fatalf("expected element with value %d but got %d", 0, element)
+		}
+	}
+	for i := 0; i < 1000; i++ {
+		if element, err := queue.Peek(); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if element != i {
+			t.Fatalf("expected element with value %d but got %d", i, element)
+		} else if queue.Size() != 1000-i {
+			t.Fatalf("expected Peek to leave queue size %d but got %d", 1000-i, queue.Size())
+		}
+		queue.Pop()
+	}
+	if _, err := queue.Peek(); err == nil || !errors.Is(err, collections.ErrEmptyQueue) {
+		t.Fatalf("expected ErrEmptyQueue from Peek after popping every element but got: %v", err)
+	}
+}
+
+func testQueuePop(t *testing.T, newQueue func() collections.Queue[int]) {
+	queue := newQueue()
+	if _, err := queue.Pop(); err == nil || !errors.Is(err, collections.ErrEmptyQueue) {
+		t.Fatalf("expected ErrEmptyQueue from Pop on new queue but got: %v", err)
+	}
+
+	queue = fillQueue(newQueue, sequence(1000))
+	for i := 0; i < 1000; i++ {
+		if element, err := queue.Pop(); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if element != i {
+			t.Fatalf("expected element %d but got %d", i, element)
+		} else if queue.Size() != 999-i {
+			t.Fatalf("expected queue size %d but got %d", 999-i, queue.Size())
+		}
+	}
+	if _, err := queue.Pop(); err == nil || !errors.Is(err, collections.ErrEmptyQueue) {
+		t.Fatalf("expected ErrEmptyQueue from Pop on empty queue but got: %v", err)
+	}
+}
+
+func testQueuePush(t *testing.T, newQueue func() collections.Queue[int]) {
+	queue := fillQueue(newQueue, sequence(1000))
+	checkQueue(t, queue, sequence(1000))
+
+	// pushes and pops interleaved, so that the front moves through any backing storage without exceeding 1000 elements
+	queue = newQueue()
+	var expected []int
+	for i := 0; i < 2997; i++ {
+		if i%3 != 2 {
+			queue.Push(i)
+			expected = append(expected, i)
+			continue
+		}
+		if element, err := queue.Pop(); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if element != expected[0] {
+			t.Fatalf("expected element %d but got %d", expected[0], element)
+		}
+		expected = expected[1:]
+	}
+	checkQueue(t, queue, expected)
+
+	for range expected {
+		queue.Pop()
+	}
+	checkQueue(t, queue, nil)
+	for i := 0; i < 10; i++ {
+		queue.Push(i)
+	}
+	checkQueue(t, queue, sequence(10))
+}
+
+func testQueueRemovingIterator(t *testing.T, newQueue func() collections.Queue[int]) {
+	queue := fillQueue(newQueue, sequence(1000))
+	checkRemovingIterator(t, queue, func() { queue.Pop() })
+	// the last element was removed, so the element before it must now be the back of the queue
+	queue.Push(1000)
+	checkQueue(t, queue, append(evens(1000)[1:], 1000))
+}
+
+func testQueueSize(t *testing.T, newQueue func() collections.Queue[int]) {
+	queue := newQueue()
+	if queue.Size() != 0 {
+		t.Fatalf("expected new queue to have size %d but got %d", 0, queue.Size())
+	}
+	for i := 1; i < 1001; i++ {
+		queue.Push(i)
+		if queue.Size() != i {
+			t.Fatalf("expected queue size %d but got %d", i, queue.Size())
+		}
+	}
+}
+
+func testQueueValues(t *testing.T, newQueue func() collections.Queue[int]) {
+	queue := newQueue()
+	for range queue.Values() {
+		t.Fatal("expected no elements from Values on empty queue")
+	}
+
+	queue = fillQueue(newQueue, sequence(1000))
+	expected := 0
+	for element := range queue.Values() {
+		if element != expected {
+			t.Fatalf("expected element with value %d but got %d", expected, element)
+		}
+		expected++
+	}
+	if expected != 1000 {
+		t.Fatalf("expected %d elements from Values but got %d", 1000, expected)
+	}
+
+	for element := range queue.Values() {
+		if element == 10 {
+			break
+		}
+	}
+}
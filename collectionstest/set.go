@@ -0,0 +1,359 @@
+// ©2022 Brandon Moller
+
+package collectionstest
+
+import (
+	"errors"
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/bmoller/collections"
+)
+
+// opcodes for the Set operations of FuzzSet and FuzzSortedSet
+const (
+	setAdd = iota
+	setContains
+	setPop
+	setRemove
+	setOperations // the number of opcodes handled by stepSet
+)
+
+/*
+FuzzSet applies the operations decoded from each input to one of the Sets returned by newSet and to a reference Set backed by a map.
+It fails if their results or errors differ, or if their elements differ after any operation.
+*/
+func FuzzSet(f *testing.F, newSet func() collections.Set[int]) {
+	addSeeds(f)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		ops := newOperations(t, data)
+		set := newSet()
+		model := make(map[int]bool)
+		for op, ok := ops.next(setOperations); ok; op, ok = ops.next(setOperations) {
+			stepSet(t, ops, op, set, model, false)
+			checkSet(t, set, slices.Sorted(maps.Keys(model)))
+		}
+	})
+}
+
+/*
+TestSet runs the conformance tests for [collections.Set] against the Sets returned by newSet.
+As the order of a Set's elements is up to each implementation, the iterators are only checked to produce every element exactly once.
+*/
+func TestSet(t *testing.T, newSet func() collections.Set[int]) {
+	t.Run("Add", func(t *testing.T) { testSetAdd(t, newSet) })
+	t.Run("All", func(t *testing.T) { testSetAll(t, newSet) })
+	t.Run("Contains", func(t *testing.T) { testSetContains(t, newSet) })
+	t.Run("Empty", func(t *testing.T) { testSetEmpty(t, newSet) })
+	t.Run("Iterator", func(t *testing.T) { testSetIterator(t, newSet) })
+	t.Run("Pop", func(t *testing.T) { testSetPop(t, newSet) })
+	t.Run("Remove", func(t *testing.T) { testSetRemove(t, newSet) })
+	t.Run("RemovingIterator", func(t *testing.T) { testSetRemovingIterator(t, newSet) })
+	t.Run("Size", func(t *testing.T) { testSetSize(t, newSet) })
+	t.Run("Values", func(t *testing.T) { testSetValues(t, newSet) })
+}
+
+// checkSet fails the test unless set holds exactly the elements of expected, which must be sorted.
+func checkSet(t *testing.T, set collections.Set[int], expected []int) {
+	t.Helper()
+
+	checkSize(t, set, len(expected))
+	for _, element := range expected {
+		if !set.Contains(element) {
+			t.Fatalf("expected set to contain %d", element)
+		}
+	}
+
+	var fromAll []int
+	for i, element := range set.All() {
+		if i != len(fromAll) {
+			t.Fatalf("expected index %d from All but got %d", len(fromAll), i)
+		}
+		fromAll = append(fromAll, element)
+	}
+	fromIterator := slices.Collect(collections.ToSeq(set.Iterator()))
+	fromValues := slices.Collect(set.Values())
+	for name, actual := range map[string][]int{"All": fromAll, "Iterator": fromIterator, "Values": fromValues} {
+		slices.Sort(actual)
+		if !slices.Equal(actual, expected) {
+			t.Fatalf("expected %v from %s but got %v", expected, name, actual)
+		}
+	}
+}
+
+// fillSet adds the elements of elements to a new Set.
+func fillSet(newSet func() collections.Set[int], elements []int) collections.Set[int] {
+	set := newSet()
+	for _, element := range elements {
+		set.Add(element)
+	}
+
+	return set
+}
+
+// stepSet applies the Set operation op to both set and model.
+// If smallest is true, Pop must remove the smallest element.
+func stepSet(t *testing.T, ops *operations, op int, set collections.Set[int], model map[int]bool, smallest bool) {
+	t.Helper()
+
+	switch op {
+	case setAdd:
+		value := ops.key()
+		ops.record("Add(%d)", value)
+		set.Add(value)
+		model[value] = true
+	case setContains:
+		value := ops.key()
+		ops.record("Contains(%d)", value)
+		checkResult(t, "Contains", set.Contains(value), model[value])
+	case setPop:
+		ops.record("Pop()")
+		element, err := set.Pop()
+		if len(model) == 0 {
+			checkError(t, "Pop", err, collections.ErrEmptySet)
+			return
+		}
+		checkError(t, "Pop", err, nil)
+		if !model[element] {
+			t.Fatalf("unexpected element %d from Pop", element)
+		} else if smallest {
+			checkResult(t, "Pop", element, slices.Min(slices.Collect(maps.Keys(model))))
+		}
+		delete(model, element)
+	case setRemove:
+		value := ops.key()
+		ops.record("Remove(%d)", value)
+		set.Remove(value)
+		delete(model, value)
+	}
+}
+
+func testSetAdd(t *testing.T, newSet func() collections.Set[int]) {
+	set := newSet()
+	for i := 0; i < 1000; i++ {
+		set.Add(i)
+		if set.Size() != i+1 {
+			t.Fatalf("expected set size %d but got %d", i+1, set.Size())
+		}
+	}
+	checkSet(t, set, sequence(1000))
+
+	for i := 0; i < 1000; i++ {
+		set.Add(i)
+	}
+	checkSet(t, set, sequence(1000))
+}
+
+func testSetAll(t *testing.T, newSet func() collections.Set[int]) {
+	set := newSet()
+	checkSeq(t, "All", set.All(), nil)
+
+	set = fillSet(newSet, sequence(1000))
+	seen := make(map[int]bool)
+	expected := 0
+	for i, element := range set.All() {
+		if i != expected {
+			t.Fatalf("expected index %d but got %d", expected, i)
+		} else if seen[element] {
+			t.Fatalf("element %d returned more than once", element)
+		}
+		seen[element] = true
+		expected++
+	}
+	if len(seen) != 1000 {
+		t.Fatalf("expected %d elements from All but got %d", 1000, len(seen))
+	}
+
+	for i := range set.All() {
+		if i == 10 {
+			break
+		}
+	}
+}
+
+func testSetContains(t *testing.T, newSet func() collections.Set[int]) {
+	set := newSet()
+	if set.Contains(0) {
+		t.Fatal("expected empty set not to contain any element")
+	}
+
+	for i := 0; i < 1000; i += 2 {
+		set.Add(i)
+	}
+	for i := -1; i < 1001; i++ {
+		if set.Contains(i) != (i >= 0 && i < 1000 && i%2 == 0) {
+			t.Fatalf("unexpected result from Contains for %d", i)
+		}
+	}
+}
+
+func testSetEmpty(t *testing.T, newSet func() collections.Set[int]) {
+	set := newSet()
+	if !set.Empty() {
+		t.Fatal("expected new set to be empty")
+	}
+	set.Add(0)
+	if set.Empty() {
+		t.Fatal("expected set not to be empty after adding an element")
+	}
+	set.Remove(0)
+	if !set.Empty() {
+		t.Fatal("expected set to be empty after removing its only element")
+	}
+}
+
+func testSetIterator(t *testing.T, newSet func() collections.Set[int]) {
+	set := newSet()
+	if _, err := set.Iterator()(); err == nil || !errors.Is(err, collections.ErrNoMoreItems) {
+		t.Fatalf("expected ErrNoMoreItems from Iterator on empty set but got: %v", err)
+	}
+
+	set = fillSet(newSet, sequence(1000))
+	seen := make(map[int]bool)
+	itr := set.Iterator()
+	for i := 0; i < 1000; i++ {
+		if element, err := itr(); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if seen[element] || !set.Contains(element) {
+			t.Fatalf("unexpected element %d from Iterator", element)
+		} else {
+			seen[element] = true
+		}
+	}
+	if _, err := itr(); err == nil || !errors.Is(err, collections.ErrNoMoreItems) {
+		t.Fatalf("exhausted iterator should return ErrNoMoreItems but got %v", err)
+	}
+
+	// adding an element already present, removing one that is absent and a Pop that fails do not change the set, so they leave an iterator valid
+	set = newSet()
+	itr = set.Iterator()
+	set.Pop()
+	if _, err := itr(); err == nil || !errors.Is(err, collections.ErrNoMoreItems) {
+		t.Fatalf("expected ErrNoMoreItems from Iterator after a failed Pop but got: %v", err)
+	}
+	set = fillSet(newSet, sequence(10))
+	itr = set.Iterator()
+	set.Add(5)
+	set.Contains(5)
+	set.Remove(10)
+	seen = make(map[int]bool)
+	for element := range collections.ToSeq(itr) {
+		seen[element] = true
+	}
+	if len(seen) != 10 {
+		t.Fatalf("expected %d elements from Iterator but got %d", 10, len(seen))
+	}
+
+	modifications := []struct {
+		method string
+		modify func(collections.Set[int])
+	}{
+		{"Add", func(set collections.Set[int]) { set.Add(10) }},
+		{"Pop", func(set collections.Set[int]) { set.Pop() }},
+		{"Remove", func(set collections.Set[int]) { set.Remove(5) }},
+	}
+	for _, m := range modifications {
+		set = fillSet(newSet, sequence(10))
+		itr = set.Iterator()
+		itr()
+		m.modify(set)
+		checkInvalidated(t, itr, m.method)
+	}
+}
+
+func testSetPop(t *testing.T, newSet func() collections.Set[int]) {
+	set := newSet()
+	if _, err := set.Pop(); err == nil || !errors.Is(err, collections.ErrEmptySet) {
+		t.Fatalf("expected ErrEmptySet from Pop on new set but got: %v", err)
+	}
+
+	set = fillSet(newSet, sequence(1000))
+	seen := make(map[int]bool)
+	for i := 999; i > -1; i-- {
+		element, err := set.Pop()
+		if err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if element < 0 || element >= 1000 || seen[element] {
+			t.Fatalf("unexpected element %d from Pop", element)
+		} else if set.Contains(element) {
+			t.Fatalf("expected Pop to remove element %d", element)
+		} else if set.Size() != i {
+			t.Fatalf("expected set size %d but got %d", i, set.Size())
+		}
+		seen[element] = true
+	}
+	if _, err := set.Pop(); err == nil || !errors.Is(err, collections.ErrEmptySet) {
+		t.Fatalf("expected ErrEmptySet from Pop on empty set but got: %v", err)
+	}
+	checkSet(t, set, nil)
+}
+
+func testSetRemove(t *testing.T, newSet func() collections.Set[int]) {
+	set := newSet()
+	set.Remove(0)
+	checkSet(t, set, nil)
+
+	set = fillSet(newSet, sequence(1000))
+	var expected []int
+	for i := 0; i < 1000; i++ {
+		if i%3 == 0 {
+			set.Remove(i)
+		} else {
+			expected = append(expected, i)
+		}
+	}
+	set.Remove(-1)
+	set.Remove(1000)
+	checkSet(t, set, expected)
+
+	for _, element := range expected {
+		set.Remove(element)
+	}
+	checkSet(t, set, nil)
+	set.Add(0)
+	checkSet(t, set, []int{0})
+}
+
+func testSetRemovingIterator(t *testing.T, newSet func() collections.Set[int]) {
+	set := fillSet(newSet, sequence(1000))
+	checkRemovingIterator(t, set, func() { set.Remove(0) })
+	checkSet(t, set, evens(1000)[1:])
+}
+
+func testSetSize(t *testing.T, newSet func() collections.Set[int]) {
+	set := newSet()
+	if set.Size() != 0 {
+		t.Fatalf("expected new set to have size %d but got %d", 0, set.Size())
+	}
+	for i := 1; i < 1001; i++ {
+		set.Add(i)
+		set.Add(i)
+		if set.Size() != i {
+			t.Fatalf("expected set size %d but got %d", i, set.Size())
+		}
+	}
+}
+
+func testSetValues(t *testing.T, newSet func() collections.Set[int]) {
+	set := newSet()
+	for range set.Values() {
+		t.Fatal("expected no elements from Values on empty set")
+	}
+
+	set = fillSet(newSet, sequence(1000))
+	seen := make(map[int]bool)
+	for element := range set.Values() {
+		if seen[element] || !set.Contains(element) {
+			t.Fatalf("unexpected element %d from Values", element)
+		}
+		seen[element] = true
+	}
+	if len(seen) != 1000 {
+		t.Fatalf("expected %d elements from Values but got %d", 1000, len(seen))
+	}
+
+	for range set.Values() {
+		break
+	}
+}
@@ -0,0 +1,135 @@
+// ©2022 Brandon Moller
+
+package collectionstest
+
+import (
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/bmoller/collections"
+)
+
+// opcodes for the SortedMap operations of FuzzSortedMap, which follow those of FuzzMap
+const (
+	sortedMapCeiling = iota
+	sortedMapFloor
+	sortedMapHigher
+	sortedMapLower
+	sortedMapMax
+	sortedMapMin
+	sortedMapOperations // the number of opcodes handled by stepSortedMap
+)
+
+/*
+FuzzSortedMap applies the operations decoded from each input to one of the SortedMaps returned by newMap and to a reference Map backed by a map, as FuzzMap does.
+The entries of the SortedMap must also be in ascending order of their keys, and its nearest-key queries must agree with the sorted keys of the reference Map.
+*/
+func FuzzSortedMap(f *testing.F, newMap func() collections.SortedMap[int, int]) {
+	addSeeds(f)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		ops := newOperations(t, data)
+		m := newMap()
+		model := make(map[int]int)
+		for op, ok := ops.next(mapOperations + sortedMapOperations); ok; op, ok = ops.next(mapOperations + sortedMapOperations) {
+			if op < mapOperations {
+				stepMap(t, ops, op, m, model)
+			} else {
+				stepSortedMap(t, ops, op-mapOperations, m, model)
+			}
+			checkSortedMap(t, m, model)
+		}
+	})
+}
+
+// checkSortedMap fails the test unless m holds exactly the entries of expected, and every sequence produces them in ascending or, for Backward, descending order of their keys.
+func checkSortedMap(t *testing.T, m collections.SortedMap[int, int], expected map[int]int) {
+	t.Helper()
+
+	sorted := slices.Sorted(maps.Keys(expected))
+	values := make([]int, len(sorted))
+	for i, key := range sorted {
+		values[i] = expected[key]
+	}
+	if keys := checkMap(t, m, expected); !slices.Equal(keys, sorted) {
+		t.Fatalf("expected keys %v from Entries but got %v", sorted, keys)
+	} else if actual := slices.Collect(m.Keys()); !slices.Equal(actual, sorted) {
+		t.Fatalf("expected %v from Keys but got %v", sorted, actual)
+	} else if actual := slices.Collect(m.Values()); !slices.Equal(actual, values) {
+		t.Fatalf("expected %v from Values but got %v", values, actual)
+	}
+
+	var backward []int
+	for key, value := range m.Backward() {
+		if len(backward) == len(expected) {
+			t.Fatalf("expected %d entries from Backward but got more", len(expected))
+		} else if value != expected[key] {
+			t.Fatalf("expected value %d for key %d from Backward but got %d", expected[key], key, value)
+		}
+		backward = append(backward, key)
+	}
+	if !slices.Equal(backward, reversed(sorted)) {
+		t.Fatalf("expected keys %v from Backward but got %v", reversed(sorted), backward)
+	}
+}
+
+// stepSortedMap applies the SortedMap operation op to m and checks the result against model.
+func stepSortedMap(t *testing.T, ops *operations, op int, m collections.SortedMap[int, int], model map[int]int) {
+	t.Helper()
+
+	sorted := slices.Sorted(maps.Keys(model))
+	var (
+		err         error
+		expectedErr error
+		expectedKey int
+		key, value  int
+		method      string
+	)
+	switch op {
+	case sortedMapCeiling, sortedMapFloor, sortedMapHigher, sortedMapLower:
+		query := ops.key()
+		below, inclusive := op == sortedMapFloor || op == sortedMapLower, op == sortedMapCeiling || op == sortedMapFloor
+		switch op {
+		case sortedMapCeiling:
+			method = "Ceiling"
+			key, value, err = m.Ceiling(query)
+		case sortedMapFloor:
+			method = "Floor"
+			key, value, err = m.Floor(query)
+		case sortedMapHigher:
+			method = "Higher"
+			key, value, err = m.Higher(query)
+		case sortedMapLower:
+			method = "Lower"
+			key, value, err = m.Lower(query)
+		}
+		ops.record("%s(%d)", method, query)
+		var ok bool
+		if expectedKey, ok = nearest(sorted, query, below, inclusive); !ok {
+			expectedErr = collections.ErrNoSuchElement
+		}
+	case sortedMapMax, sortedMapMin:
+		method = "Min"
+		if op == sortedMapMax {
+			method = "Max"
+			key, value, err = m.Max()
+		} else {
+			key, value, err = m.Min()
+		}
+		ops.record("%s()", method)
+		switch {
+		case len(sorted) == 0:
+			expectedErr = collections.ErrEmptyMap
+		case op == sortedMapMax:
+			expectedKey = sorted[len(sorted)-1]
+		default:
+			expectedKey = sorted[0]
+		}
+	}
+
+	checkError(t, method, err, expectedErr)
+	if expectedErr == nil {
+		checkResult(t, method, key, expectedKey)
+		checkResult(t, method, value, model[expectedKey])
+	}
+}
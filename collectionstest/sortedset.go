@@ -0,0 +1,140 @@
+// ©2022 Brandon Moller
+
+package collectionstest
+
+import (
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/bmoller/collections"
+)
+
+// opcodes for the SortedSet operations of FuzzSortedSet, which follow those of FuzzSet
+const (
+	sortedSetCeiling = iota
+	sortedSetFloor
+	sortedSetHigher
+	sortedSetLower
+	sortedSetMax
+	sortedSetMin
+	sortedSetRange
+	sortedSetOperations // the number of opcodes handled by stepSortedSet
+)
+
+/*
+FuzzSortedSet applies the operations decoded from each input to one of the SortedSets returned by newSet and to a reference Set backed by a map, as FuzzSet does.
+The elements of the SortedSet must also be in ascending order, and its nearest-element queries and Range must agree with the sorted elements of the reference Set.
+*/
+func FuzzSortedSet(f *testing.F, newSet func() collections.SortedSet[int]) {
+	addSeeds(f)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		ops := newOperations(t, data)
+		set := newSet()
+		model := make(map[int]bool)
+		for op, ok := ops.next(setOperations + sortedSetOperations); ok; op, ok = ops.next(setOperations + sortedSetOperations) {
+			if op < setOperations {
+				stepSet(t, ops, op, set, model, true)
+			} else {
+				stepSortedSet(t, ops, op-setOperations, set, slices.Sorted(maps.Keys(model)))
+			}
+			checkSortedSet(t, set, slices.Sorted(maps.Keys(model)))
+		}
+	})
+}
+
+// checkSortedSet fails the test unless set holds exactly the elements of expected, which must be sorted, in ascending order.
+func checkSortedSet(t *testing.T, set collections.SortedSet[int], expected []int) {
+	t.Helper()
+
+	checkSet(t, set, expected)
+	checkIterable(t, set, expected)
+	checkBackward(t, set.Backward(), expected)
+}
+
+// nearest returns the element of sorted nearest to value, above or below it, and whether there is one.
+// Unless inclusive, an element equal to value is skipped.
+func nearest(sorted []int, value int, below, inclusive bool) (int, bool) {
+	i, found := slices.BinarySearch(sorted, value)
+	switch {
+	case found && inclusive:
+		return sorted[i], true
+	case below && i > 0:
+		return sorted[i-1], true
+	case !below && found && i+1 < len(sorted):
+		return sorted[i+1], true
+	case !below && !found && i < len(sorted):
+		return sorted[i], true
+	}
+
+	return 0, false
+}
+
+// stepSortedSet applies the SortedSet operation op to set and checks the result against sorted, the elements of the reference Set.
+func stepSortedSet(t *testing.T, ops *operations, op int, set collections.SortedSet[int], sorted []int) {
+	t.Helper()
+
+	var (
+		element int
+		err     error
+		method  string
+	)
+	switch op {
+	case sortedSetCeiling, sortedSetFloor, sortedSetHigher, sortedSetLower:
+		value := ops.key()
+		below, inclusive := op == sortedSetFloor || op == sortedSetLower, op == sortedSetCeiling || op == sortedSetFloor
+		switch op {
+		case sortedSetCeiling:
+			method = "Ceiling"
+			element, err = set.Ceiling(value)
+		case sortedSetFloor:
+			method = "Floor"
+			element, err = set.Floor(value)
+		case sortedSetHigher:
+			method = "Higher"
+			element, err = set.Higher(value)
+		case sortedSetLower:
+			method = "Lower"
+			element, err = set.Lower(value)
+		}
+		ops.record("%s(%d)", method, value)
+		if expected, ok := nearest(sorted, value, below, inclusive); ok {
+			checkError(t, method, err, nil)
+			checkResult(t, method, element, expected)
+		} else {
+			checkError(t, method, err, collections.ErrNoSuchElement)
+		}
+	case sortedSetMax, sortedSetMin:
+		method = "Min"
+		if op == sortedSetMax {
+			method = "Max"
+			element, err = set.Max()
+		} else {
+			element, err = set.Min()
+		}
+		ops.record("%s()", method)
+		switch {
+		case len(sorted) == 0:
+			checkError(t, method, err, collections.ErrEmptySet)
+		case op == sortedSetMax:
+			checkError(t, method, err, nil)
+			checkResult(t, method, element, sorted[len(sorted)-1])
+		default:
+			checkError(t, method, err, nil)
+			checkResult(t, method, element, sorted[0])
+		}
+	case sortedSetRange:
+		from, to := ops.key(), ops.key()
+		ops.record("Range(%d, %d)", from, to)
+		var expected []int
+		for _, element := range sorted {
+			if element >= from && element < to {
+				expected = append(expected, element)
+			}
+		}
+		subset := set.Range(from, to)
+		checkSortedSet(t, subset, expected)
+		subset.Add(-1)
+		checkSortedSet(t, subset, append([]int{-1}, expected...))
+	}
+}
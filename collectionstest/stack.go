@@ -0,0 +1,307 @@
+// ©2022 Brandon Moller
+
+package collectionstest
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bmoller/collections"
+)
+
+// opcodes for the operations of FuzzStack
+const (
+	stackPeek = iota
+	stackPop
+	stackPush
+	stackOperations // the number of opcodes
+)
+
+/*
+FuzzStack applies the operations decoded from each input to one of the Stacks returned by newStack and to a reference Stack backed by a slice.
+It fails if their results or errors differ, or if their elements differ after any operation.
+*/
+func FuzzStack(f *testing.F, newStack func() collections.Stack[int]) {
+	addSeeds(f)
+	f.Fuzz(func(t *testing.T, data []byte) {
+		ops := newOperations(t, data)
+		stack := newStack()
+		// the top of the stack is the end of the model
+		var model []int
+		for op, ok := ops.next(stackOperations); ok; op, ok = ops.next(stackOperations) {
+			switch op {
+			case stackPeek, stackPop:
+				var (
+					element int
+					err     error
+				)
+				method := "Peek"
+				if op == stackPop {
+					method = "Pop"
+					element, err = stack.Pop()
+				} else {
+					element, err = stack.Peek()
+				}
+				ops.record("%s()", method)
+				if len(model) == 0 {
+					checkError(t, method, err, collections.ErrEmptyStack)
+					break
+				}
+				checkError(t, method, err, nil)
+				checkResult(t, method, element, model[len(model)-1])
+				if op == stackPop {
+					model = model[:len(model)-1]
+				}
+			case stackPush:
+				value := ops.value()
+				ops.record("Push(%d)", value)
+				stack.Push(value)
+				model = append(model, value)
+			}
+			checkStack(t, stack, reversed(model))
+		}
+	})
+}
+
+/*
+TestStack runs the conformance tests for [collections.Stack] against the Stacks returned by newStack.
+*/
+func TestStack(t *testing.T, newStack func() collections.Stack[int]) {
+	t.Run("All", func(t *testing.T) { testStackAll(t, newStack) })
+	t.Run("Empty", func(t *testing.T) { testStackEmpty(t, newStack) })
+	t.Run("Iterator", func(t *testing.T) { testStackIterator(t, newStack) })
+	t.Run("Peek", func(t *testing.T) { testStackPeek(t, newStack) })
+	t.Run("Pop", func(t *testing.T) { testStackPop(t, newStack) })
+	t.Run("Push", func(t *testing.T) { testStackPush(t, newStack) })
+	t.Run("RemovingIterator", func(t *testing.T) { testStackRemovingIterator(t, newStack) })
+	t.Run("Size", func(t *testing.T) { testStackSize(t, newStack) })
+	t.Run("Values", func(t *testing.T) { testStackValues(t, newStack) })
+}
+
+// checkStack fails the test unless stack holds exactly the elements of expected, from top to bottom.
+func checkStack(t *testing.T, stack collections.Stack[int], expected []int) {
+	t.Helper()
+
+	checkSize(t, stack, len(expected))
+	checkIterable(t, stack, expected)
+}
+
+// fillStack pushes the elements of elements to a new Stack, so that the last is on top.
+func fillStack(newStack func() collections.Stack[int], elements []int) collections.Stack[int] {
+	stack := newStack()
+	for _, element := range elements {
+		stack.Push(element)
+	}
+
+	return stack
+}
+
+func testStackAll(t *testing.T, newStack func() collections.Stack[int]) {
+	stack := newStack()
+	checkSeq(t, "All", stack.All(), nil)
+
+	stack = fillStack(newStack, sequence(1000))
+	checkSeq(t, "All", stack.All(), reversed(sequence(1000)))
+	for i := range stack.All() {
+		if i == 10 {
+			break
+		}
+	}
+	checkStack(t, stack, reversed(sequence(1000)))
+}
+
+func testStackEmpty(t *testing.T, newStack func() collections.Stack[int]) {
+	stack := newStack()
+	if !stack.Empty() {
+		t.Fatal("expected new stack to be empty")
+	}
+	stack.Push(0)
+	if stack.Empty() {
+		t.Fatal("expected stack not to be empty after pushing an element")
+	}
+	stack.Pop()
+	if !stack.Empty() {
+		t.Fatal("expected stack to be empty after popping its only element")
+	}
+}
+
+func testStackIterator(t *testing.T, newStack func() collections.Stack[int]) {
+	stack := newStack()
+	if _, err := stack.Iterator()(); err == nil || !errors.Is(err, collections.ErrNoMoreItems) {
+		t.Fatalf("expected ErrNoMoreItems from Iterator on empty stack but got: %v", err)
+	}
+
+	stack = fillStack(newStack, sequence(1000))
+	itr := stack.Iterator()
+	for i := 999; i > -1; i-- {
+		if element, err := itr(); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if element != i {
+			t.Fatalf("expected element with value %d but got %d", i, element)
+		}
+	}
+	if _, err := itr(); err == nil || !errors.Is(err, collections.ErrNoMoreItems) {
+		t.Fatalf("exhausted iterator should return ErrNoMoreItems but got %v", err)
+	}
+	if stack.Size() != 1000 {
+		t.Fatalf("expected iteration to leave stack size %d but got %d", 1000, stack.Size())
+	}
+
+	// Peek and a Pop that fails leave an iterator valid
+	stack = newStack()
+	itr = stack.Iterator()
+	stack.Pop()
+	if _, err := itr(); err == nil || !errors.Is(err, collections.ErrNoMoreItems) {
+		t.Fatalf("expected ErrNoMoreItems from Iterator after a failed Pop but got: %v", err)
+	}
+	stack = fillStack(newStack, sequence(10))
+	itr = stack.Iterator()
+	itr()
+	stack.Peek()
+	checkIterator(t, itr, reversed(sequence(10))[1:])
+
+	modifications := []struct {
+		method string
+		modify func(collections.Stack[int])
+	}{
+		{"Pop", func(stack collections.Stack[int]) { stack.Pop() }},
+		{"Push", func(stack collections.Stack[int]) { stack.Push(10) }},
+	}
+	for _, m := range modifications {
+		stack = fillStack(newStack, sequence(10))
+		itr = stack.Iterator()
+		itr()
+		m.modify(stack)
+		checkInvalidated(t, itr, m.method)
+	}
+}
+
+func testStackPeek(t *testing.T, newStack func() collections.Stack[int]) {
+	stack := newStack()
+	if _, err := stack.Peek(); err == nil || !errors.Is(err, collections.ErrEmptyStack) {
+		t.Fatalf("expected ErrEmptyStack from Peek on new stack but got: %v", err)
+	}
+
+	for i := 0; i < 1000; i++ {
+		stack.Push(i)
+		if element, err := stack.Peek(); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if element != i {
+			t.Fatalf("expected element with value %d but got %d", i, element)
+		}
+	}
+	for i := 999; i > -1; i-- {
+		switch element, err := stack.Peek(); {
+		case err != nil:
+			t.Fatalf("unexpected error: %s", err)
+		case element != i:
+			t.Fatalf("expected element with value %d but got %d", i, element)
+		case stack.Size() != i+1:
+			t.Fatalf("expected Peek to leave stack size %d but got %d", i+1, stack.Size())
+		}
+		stack.Pop()
+	}
+	if _, err := stack.Peek(); err == nil || !errors.Is(err, collections.ErrEmptyStack) {
+		t.Fatalf("expected ErrEmptyStack from Peek after popping every element but got: %v", err)
+	}
+}
+
+func testStackPop(t *testing.T, newStack func() collections.Stack[int]) {
+	stack := newStack()
+	if _, err := stack.Pop(); err == nil || !errors.Is(err, collections.ErrEmptyStack) {
+		t.Fatalf("expected ErrEmptyStack from Pop on new stack but got: %v", err)
+	}
+
+	stack = fillStack(newStack, sequence(1000))
+	for i := 999; i > -1; i-- {
+		switch element, err := stack.Pop(); {
+		case err != nil:
+			t.Fatalf("unexpected error: %s", err)
+		case element != i:
+			t.Fatalf("expected element with value %d but got %d", i, element)
+		case stack.Size() != i:
+			t.Fatalf("expected stack size %d but got %d", i, stack.Size())
+		}
+	}
+	if _, err := stack.Pop(); err == nil || !errors.Is(err, collections.ErrEmptyStack) {
+		t.Fatalf("expected ErrEmptyStack from Pop on empty stack but got: %v", err)
+	}
+}
+
+func testStackPush(t *testing.T, newStack func() collections.Stack[int]) {
+	stack := fillStack(newStack, sequence(1000))
+	checkStack(t, stack, reversed(sequence(1000)))
+
+	// pushes and pops interleaved, so that the top moves up and down through any backing storage without exceeding 1000 elements
+	stack = newStack()
+	var expected []int
+	for i := 0; i < 2997; i++ {
+		if i%3 != 2 {
+			stack.Push(i)
+			expected = append([]int{i}, expected...)
+			continue
+		}
+		if element, err := stack.Pop(); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if element != expected[0] {
+			t.Fatalf("expected element %d but got %d", expected[0], element)
+		}
+		expected = expected[1:]
+	}
+	checkStack(t, stack, expected)
+
+	for range expected {
+		stack.Pop()
+	}
+	checkStack(t, stack, nil)
+	for i := 0; i < 10; i++ {
+		stack.Push(i)
+	}
+	checkStack(t, stack, reversed(sequence(10)))
+}
+
+func testStackRemovingIterator(t *testing.T, newStack func() collections.Stack[int]) {
+	stack := fillStack(newStack, sequence(1000))
+	checkRemovingIterator(t, stack, func() { stack.Pop() })
+	// the top element was removed, so the element below it must now be under a new top
+	stack.Push(1000)
+	checkStack(t, stack, append([]int{1000}, reversed(evens(1000))[1:]...))
+}
+
+func testStackSize(t *testing.T, newStack func() collections.Stack[int]) {
+	stack := newStack()
+	if stack.Size() != 0 {
+		t.Fatalf("expected new stack to have size %d but got %d", 0, stack.Size())
+	}
+	for i := 1; i < 1001; i++ {
+		stack.Push(i)
+		if stack.Size() != i {
+			t.Fatalf("expected stack size %d but got %d", i, stack.Size())
+		}
+	}
+}
+
+func testStackValues(t *testing.T, newStack func() collections.Stack[int]) {
+	stack := newStack()
+	for range stack.Values() {
+		t.Fatal("expected no elements from Values on empty stack")
+	}
+
+	stack = fillStack(newStack, sequence(1000))
+	expected := 999
+	for element := range stack.Values() {
+		if element != expected {
+			t.Fatalf("expected element with value %d but got %d", expected, element)
+		}
+		expected--
+	}
+	if expected != -1 {
+		t.Fatalf("expected %d elements from Values but got %d", 1000, 999-expected)
+	}
+
+	for element := range stack.Values() {
+		if element == 990 {
+			break
+		}
+	}
+}
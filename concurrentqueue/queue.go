@@ -0,0 +1,190 @@
+// ©2022 Brandon Moller
+
+/*
+Package concurrentqueue provides a lock-free implementation of [collections.Queue] that is safe for concurrent use.
+
+The Queue uses the Michael–Scott algorithm: like linkedqueue, each element is stored in a node that points to the next node, but the head and tail pointers and each node's next pointer are updated with atomic compare-and-swap.
+The head always points to a sentinel node whose successor holds the element at the front of the Queue.
+A Push that finds the tail lagging behind the last node advances it before retrying, so a goroutine that stalls part way through a Push never blocks the others.
+
+Elements pushed by a single goroutine are popped in the order in which they were pushed.
+Sequences start from the front of the Queue when they begin and follow next pointers from there; they produce every element present at that moment, even if it is popped during iteration, and may also produce elements pushed afterwards.
+Iterators walk the nodes in the same way, but fail with ErrConcurrentModification once any element has been pushed or popped since they were created.
+
+Queues implement [collections.RemovingIterable].
+Removing an element through a RemovingIterator only marks its node, which stays linked until a Pop reaches it; whichever of the two marks a node first is the one that removes its element.
+Peek, Pop, Empty, iterators and sequences all pass over marked nodes.
+Size is maintained by a separate atomic counter and is only eventually consistent; while other goroutines are pushing or popping it may briefly disagree with the number of elements.
+Empty always reflects the state of the nodes after the head.
+*/
+package concurrentqueue
+
+import (
+	"iter"
+	"sync/atomic"
+
+	"github.com/bmoller/collections"
+)
+
+type iterator[T comparable] struct {
+	last          *queueNode[T]
+	modifications uint64
+	next          *queueNode[T]
+	queue         *queue[T]
+}
+
+func (i *iterator[T]) Next() (element T, err error) {
+	if i.queue.modifications.Load() != i.modifications {
+		return element, collections.ErrConcurrentModification
+	}
+	i.last = present(i.next)
+	if i.last == nil {
+		return element, collections.ErrNoMoreItems
+	}
+	i.next = i.last.next.Load()
+
+	return i.last.value, nil
+}
+
+func (i *iterator[T]) Remove() error {
+	q := i.queue
+	if q.modifications.Load() != i.modifications {
+		return collections.ErrConcurrentModification
+	} else if i.last == nil {
+		return collections.ErrNothingToRemove
+	} else if !q.modifications.CompareAndSwap(i.modifications, i.modifications+1) || !i.last.removed.CompareAndSwap(false, true) {
+		// a Pop marks its node before counting itself, so it may have taken the element without changing the count yet
+		return collections.ErrConcurrentModification
+	}
+	q.size.Add(-1)
+	i.last, i.modifications = nil, i.modifications+1
+
+	return nil
+}
+
+type queueNode[T comparable] struct {
+	next    atomic.Pointer[queueNode[T]]
+	removed atomic.Bool
+	value   T
+}
+
+// present returns n or the first node after it that has not been removed, or nil if there is none.
+func present[T comparable](n *queueNode[T]) *queueNode[T] {
+	for n != nil && n.removed.Load() {
+		n = n.next.Load()
+	}
+
+	return n
+}
+
+type queue[T comparable] struct {
+	head          atomic.Pointer[queueNode[T]]
+	modifications atomic.Uint64
+	size          atomic.Int64
+	tail          atomic.Pointer[queueNode[T]]
+}
+
+func New[T comparable]() collections.Queue[T] {
+	q := new(queue[T])
+	sentinel := new(queueNode[T])
+	q.head.Store(sentinel)
+	q.tail.Store(sentinel)
+
+	return q
+}
+
+func (q *queue[T]) All() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		i := 0
+		for node := present(q.head.Load().next.Load()); node != nil; node = present(node.next.Load()) {
+			if !yield(i, node.value) {
+				return
+			}
+			i++
+		}
+	}
+}
+
+func (q *queue[T]) Empty() bool {
+	return present(q.head.Load().next.Load()) == nil
+}
+
+func (q *queue[T]) Iterator() collections.Iterator[T] {
+	return q.RemovingIterator().Next
+}
+
+func (q *queue[T]) Peek() (element T, err error) {
+	first := present(q.head.Load().next.Load())
+	if first == nil {
+		return element, collections.ErrEmptyQueue
+	}
+
+	return first.value, nil
+}
+
+func (q *queue[T]) Pop() (element T, err error) {
+	for {
+		head := q.head.Load()
+		tail := q.tail.Load()
+		first := head.next.Load()
+		if first == nil {
+			return element, collections.ErrEmptyQueue
+		}
+		if head == tail {
+			// the last Push has linked its node but not yet moved the tail
+			q.tail.CompareAndSwap(tail, first)
+			continue
+		}
+		if q.head.CompareAndSwap(head, first) && first.removed.CompareAndSwap(false, true) {
+			q.modifications.Add(1)
+			q.size.Add(-1)
+			return first.value, nil
+		}
+	}
+}
+
+func (q *queue[T]) Push(item T) {
+	element := &queueNode[T]{
+		value: item,
+	}
+	for {
+		tail := q.tail.Load()
+		if next := tail.next.Load(); next != nil {
+			// another Push has linked its node but not yet moved the tail
+			q.tail.CompareAndSwap(tail, next)
+			continue
+		}
+		if tail.next.CompareAndSwap(nil, element) {
+			q.tail.CompareAndSwap(tail, element)
+			q.modifications.Add(1)
+			q.size.Add(1)
+			return
+		}
+	}
+}
+
+func (q *queue[T]) RemovingIterator() collections.RemovingIterator[T] {
+	// read before the front, so a Push or Pop racing with this call is reported rather than missed
+	modifications := q.modifications.Load()
+
+	return &iterator[T]{
+		modifications: modifications,
+		next:          q.head.Load().next.Load(),
+		queue:         q,
+	}
+}
+
+func (q *queue[T]) Size() int {
+	// a Pop can be counted before the Push that preceded it
+	return int(max(q.size.Load(), 0))
+}
+
+func (q *queue[T]) Values() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for node := present(q.head.Load().next.Load()); node != nil; node = present(node.next.Load()) {
+			if !yield(node.value) {
+				return
+			}
+		}
+	}
+}
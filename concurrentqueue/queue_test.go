@@ -0,0 +1,174 @@
+// ©2022 Brandon Moller
+
+package concurrentqueue_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/bmoller/collections"
+	"github.com/bmoller/collections/collectionstest"
+	"github.com/bmoller/collections/concurrentqueue"
+	"github.com/bmoller/collections/linkedqueue"
+	"github.com/bmoller/collections/synchronized"
+)
+
+type item struct {
+	producer int
+	sequence int
+}
+
+func FuzzQueue(f *testing.F) {
+	collectionstest.FuzzQueue(f, concurrentqueue.New[int])
+}
+
+func TestQueue(t *testing.T) {
+	collectionstest.TestQueue(t, concurrentqueue.New[int])
+}
+
+func TestQueueConcurrent(t *testing.T) {
+	const (
+		producers   = 8
+		consumers   = 8
+		perProducer = 10000
+	)
+	queue := concurrentqueue.New[item]()
+
+	var (
+		wg     sync.WaitGroup
+		popped [consumers][]item
+	)
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perProducer; i++ {
+				queue.Push(item{p, i})
+			}
+		}()
+	}
+	for c := 0; c < consumers; c++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for len(popped[c]) < producers*perProducer/consumers/2 {
+				if element, err := queue.Pop(); err == nil {
+					popped[c] = append(popped[c], element)
+				}
+				queue.Peek()
+				queue.Size()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if size := queue.Size(); size != producers*perProducer/2 {
+		t.Fatalf("expected queue size %d but got %d", producers*perProducer/2, size)
+	}
+
+	// each consumer must see every producer's elements in the order they were pushed
+	var (
+		next [producers]int
+		seen [producers][perProducer]bool
+	)
+	for _, elements := range popped {
+		var last [producers]int
+		for _, element := range elements {
+			if element.sequence < last[element.producer] {
+				t.Fatalf("element %d from producer %d was popped after element %d", element.sequence, element.producer, last[element.producer]-1)
+			} else if seen[element.producer][element.sequence] {
+				t.Fatalf("element %d from producer %d was popped more than once", element.sequence, element.producer)
+			}
+			last[element.producer] = element.sequence + 1
+			seen[element.producer][element.sequence] = true
+			next[element.producer] = max(next[element.producer], element.sequence+1)
+		}
+	}
+
+	// the remaining elements must follow everything already popped from the same producer
+	for element, err := queue.Pop(); err == nil; element, err = queue.Pop() {
+		if element.sequence != next[element.producer] {
+			t.Fatalf("expected element %d from producer %d but got %d", next[element.producer], element.producer, element.sequence)
+		}
+		next[element.producer]++
+		seen[element.producer][element.sequence] = true
+	}
+	for producer := range seen {
+		for sequence, ok := range seen[producer] {
+			if !ok {
+				t.Fatalf("element %d from producer %d was lost", sequence, producer)
+			}
+		}
+	}
+}
+
+func TestQueueRemovingIteratorConcurrent(t *testing.T) {
+	const size = 10000
+	queue := concurrentqueue.New[int]()
+	for i := 0; i < size; i++ {
+		queue.Push(i)
+	}
+
+	// odd elements are removed through RemovingIterators while another goroutine pops from the front
+	var popped []int
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for element, err := queue.Pop(); err == nil; element, err = queue.Pop() {
+			popped = append(popped, element)
+		}
+	}()
+	var removed []int
+	for {
+		itr := queue.(collections.RemovingIterable[int]).RemovingIterator()
+		element, err := itr.Next()
+		if errors.Is(err, collections.ErrNoMoreItems) {
+			break
+		}
+		for ; err == nil; element, err = itr.Next() {
+			if element%2 == 1 && itr.Remove() == nil {
+				removed = append(removed, element)
+			}
+		}
+	}
+	<-done
+
+	seen := make([]bool, size)
+	for _, element := range append(popped, removed...) {
+		if seen[element] {
+			t.Fatalf("element %d was returned more than once", element)
+		}
+		seen[element] = true
+	}
+	for element, ok := range seen {
+		if !ok {
+			t.Fatalf("element %d was lost", element)
+		}
+	}
+	if !queue.Empty() || queue.Size() != 0 {
+		t.Fatalf("expected queue to be empty but got size %d", queue.Size())
+	}
+}
+
+// benchmarks
+
+func benchmarkPushPop(b *testing.B, queue collections.Queue[int]) {
+	b.RunParallel(func(pb *testing.PB) {
+		for i := 0; pb.Next(); i++ {
+			if i%2 == 0 {
+				queue.Push(i)
+			} else {
+				queue.Pop()
+			}
+		}
+	})
+}
+
+func BenchmarkConcurrentQueue(b *testing.B) {
+	benchmarkPushPop(b, concurrentqueue.New[int]())
+}
+
+func BenchmarkSynchronizedLinkedQueue(b *testing.B) {
+	benchmarkPushPop(b, synchronized.Queue(linkedqueue.New[int]()))
+}
@@ -0,0 +1,246 @@
+// ©2022 Brandon Moller
+
+/*
+Package concurrentset provides an implementation of [collections.Set] that is safe for concurrent use and scales with the number of goroutines using it.
+
+Elements are spread across a number of shards by their hash, and each shard is a map guarded by its own [sync.RWMutex].
+Goroutines working with elements in different shards never contend for the same lock, so membership checks and updates from many goroutines can run in parallel.
+
+Each method is atomic for the shard it touches, but Size, Empty, Pop and iteration visit the shards one at a time.
+While other goroutines are modifying the Set, Size and Empty may reflect a state that never existed as a whole, and sequences may or may not include elements added or removed during iteration.
+Iterators copy the elements of every shard when they are created, and fail with ErrConcurrentModification once any element has been added or removed since.
+Iterators and sequences never hold a lock while the caller's loop body runs.
+Sets implement [collections.RemovingIterable], and removing an element through a RemovingIterator takes the lock of its shard as Remove does.
+
+Because the Set only relies on Iterator and Contains, the mapset functions Union, Intersection, Difference and IsSubset all accept it, and start over if it is modified while they read it.
+*/
+package concurrentset
+
+import (
+	"hash/maphash"
+	"iter"
+	"sync"
+	"sync/atomic"
+
+	"github.com/bmoller/collections"
+)
+
+const defaultShards int = 32
+
+type iterator[T comparable] struct {
+	elements      []T
+	index         int
+	modifications uint64
+	removable     bool
+	set           *set[T]
+}
+
+func (i *iterator[T]) Next() (element T, err error) {
+	if i.set.modifications.Load() != i.modifications {
+		return element, collections.ErrConcurrentModification
+	} else if i.index == len(i.elements) {
+		return element, collections.ErrNoMoreItems
+	}
+	element = i.elements[i.index]
+	i.index++
+	i.removable = true
+
+	return element, nil
+}
+
+func (i *iterator[T]) Remove() error {
+	s := i.set
+	if s.modifications.Load() != i.modifications {
+		return collections.ErrConcurrentModification
+	} else if !i.removable {
+		return collections.ErrNothingToRemove
+	}
+	element := i.elements[i.index-1]
+	sh := s.shard(element)
+	sh.lock.Lock()
+	defer sh.lock.Unlock()
+
+	// with the shard locked, an unchanged count means that the element is still present
+	if !s.modifications.CompareAndSwap(i.modifications, i.modifications+1) {
+		return collections.ErrConcurrentModification
+	}
+	delete(sh.data, element)
+	i.modifications, i.removable = i.modifications+1, false
+
+	return nil
+}
+
+type shard[T comparable] struct {
+	data map[T]bool
+	lock sync.RWMutex
+}
+
+type set[T comparable] struct {
+	hash          func(T) uint64
+	modifications atomic.Uint64
+	next          atomic.Uint64
+	shards        []shard[T]
+}
+
+/*
+New creates a Set that hashes its elements with [maphash.Comparable] and a random seed.
+*/
+func New[T comparable]() collections.Set[T] {
+	seed := maphash.MakeSeed()
+
+	return NewWithHash(defaultShards, func(item T) uint64 {
+		return maphash.Comparable(seed, item)
+	})
+}
+
+/*
+NewWithHash creates a Set with the given number of shards that uses hash to assign elements to them.
+The hash function must return the same value for equal elements and should spread unequal elements evenly; it is called concurrently.
+At least one shard is always created.
+*/
+func NewWithHash[T comparable](shards int, hash func(T) uint64) collections.Set[T] {
+	if shards < 1 {
+		shards = 1
+	}
+
+	s := &set[T]{
+		hash:   hash,
+		shards: make([]shard[T], shards),
+	}
+	for i := range s.shards {
+		s.shards[i].data = make(map[T]bool)
+	}
+
+	return s
+}
+
+func (s *set[T]) shard(item T) *shard[T] {
+	return &s.shards[s.hash(item)%uint64(len(s.shards))]
+}
+
+func (sh *shard[T]) snapshot() []T {
+	sh.lock.RLock()
+	defer sh.lock.RUnlock()
+
+	elements := make([]T, 0, len(sh.data))
+	for element := range sh.data {
+		elements = append(elements, element)
+	}
+
+	return elements
+}
+
+func (s *set[T]) Add(item T) {
+	sh := s.shard(item)
+	sh.lock.Lock()
+	defer sh.lock.Unlock()
+
+	if !sh.data[item] {
+		sh.data[item] = true
+		s.modifications.Add(1)
+	}
+}
+
+func (s *set[T]) All() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		i := 0
+		for element := range s.Values() {
+			if !yield(i, element) {
+				return
+			}
+			i++
+		}
+	}
+}
+
+func (s *set[T]) Contains(item T) bool {
+	sh := s.shard(item)
+	sh.lock.RLock()
+	defer sh.lock.RUnlock()
+
+	return sh.data[item]
+}
+
+func (s *set[T]) Empty() bool {
+	for i := range s.shards {
+		s.shards[i].lock.RLock()
+		size := len(s.shards[i].data)
+		s.shards[i].lock.RUnlock()
+		if size != 0 {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (s *set[T]) Iterator() collections.Iterator[T] {
+	return s.RemovingIterator().Next
+}
+
+func (s *set[T]) Pop() (element T, err error) {
+	// start at a different shard each time so that concurrent calls spread out
+	start := s.next.Add(1)
+	for i := range uint64(len(s.shards)) {
+		sh := &s.shards[(start+i)%uint64(len(s.shards))]
+		sh.lock.Lock()
+		for key := range sh.data {
+			delete(sh.data, key)
+			s.modifications.Add(1)
+			sh.lock.Unlock()
+			return key, nil
+		}
+		sh.lock.Unlock()
+	}
+
+	return element, collections.ErrEmptySet
+}
+
+func (s *set[T]) Remove(item T) {
+	sh := s.shard(item)
+	sh.lock.Lock()
+	defer sh.lock.Unlock()
+
+	if sh.data[item] {
+		delete(sh.data, item)
+		s.modifications.Add(1)
+	}
+}
+
+func (s *set[T]) RemovingIterator() collections.RemovingIterator[T] {
+	// read before the shards are copied, so a change racing with the copy is reported rather than missed
+	modifications := s.modifications.Load()
+	var elements []T
+	for i := range s.shards {
+		elements = append(elements, s.shards[i].snapshot()...)
+	}
+
+	return &iterator[T]{
+		elements:      elements,
+		modifications: modifications,
+		set:           s,
+	}
+}
+
+func (s *set[T]) Size() int {
+	var size int
+	for i := range s.shards {
+		s.shards[i].lock.RLock()
+		size += len(s.shards[i].data)
+		s.shards[i].lock.RUnlock()
+	}
+
+	return size
+}
+
+func (s *set[T]) Values() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for i := range s.shards {
+			for _, element := range s.shards[i].snapshot() {
+				if !yield(element) {
+					return
+				}
+			}
+		}
+	}
+}
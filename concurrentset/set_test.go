@@ -0,0 +1,172 @@
+// ©2022 Brandon Moller
+
+package concurrentset_test
+
+import (
+	"math/rand"
+	"sync"
+	"testing"
+
+	"github.com/bmoller/collections"
+	"github.com/bmoller/collections/collectionstest"
+	"github.com/bmoller/collections/concurrentset"
+	"github.com/bmoller/collections/mapset"
+	"github.com/bmoller/collections/synchronized"
+)
+
+func FuzzSet(f *testing.F) {
+	collectionstest.FuzzSet(f, concurrentset.New[int])
+}
+
+func TestSet(t *testing.T) {
+	collectionstest.TestSet(t, concurrentset.New[int])
+}
+
+func TestSetNewWithHash(t *testing.T) {
+	// every element lands in the same shard
+	testSet := concurrentset.NewWithHash(0, func(int) uint64 { return 0 })
+	for i := 0; i < 1000; i++ {
+		testSet.Add(i)
+	}
+	for i := 0; i < 1000; i++ {
+		if !testSet.Contains(i) {
+			t.Fatal("set does not contain an added element")
+		}
+	}
+	for i := 0; i < 1000; i++ {
+		if _, err := testSet.Pop(); err != nil {
+			t.Fatalf("failed to pop element from set on call %d", i)
+		}
+	}
+	if !testSet.Empty() {
+		t.Fatal("set does not report as empty after popping all elements")
+	}
+
+	type point struct {
+		x, y int
+	}
+	points := concurrentset.NewWithHash(16, func(p point) uint64 { return uint64(p.x*31 + p.y) })
+	for i := 0; i < 100; i++ {
+		points.Add(point{i, i})
+	}
+	if !points.Contains(point{50, 50}) || points.Contains(point{50, 51}) {
+		t.Fatal("unexpected result from Contains with custom hash")
+	}
+}
+
+func TestSetOperations(t *testing.T) {
+	a := concurrentset.New[int]()
+	for i := 1; i < 11; i++ {
+		a.Add(i)
+	}
+	b := mapset.New[int]()
+	for i := 6; i < 16; i++ {
+		b.Add(i)
+	}
+
+	if union := mapset.Union(a, b); union.Size() != 15 {
+		t.Fatalf("expected union size %d but got %d", 15, union.Size())
+	}
+	if intersection := mapset.Intersection(a, b); intersection.Size() != 5 || !intersection.Contains(6) {
+		t.Fatalf("expected intersection of size %d containing %d", 5, 6)
+	}
+	if difference := mapset.Difference(b, a); difference.Size() != 5 || !difference.Contains(15) {
+		t.Fatalf("expected difference of size %d containing %d", 5, 15)
+	}
+	if mapset.IsSubset(a, b) {
+		t.Fatal("expected a to not be subset of b")
+	}
+	a.Remove(1)
+	for i := 2; i < 6; i++ {
+		b.Add(i)
+	}
+	if !mapset.IsSubset(a, b) {
+		t.Fatal("expected a to be subset of b")
+	}
+}
+
+func TestSetConcurrent(t *testing.T) {
+	testSet := concurrentset.New[int]()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				testSet.Add(i*1000 + j)
+				testSet.Add(j)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				testSet.Contains(j)
+				testSet.Size()
+				testSet.Empty()
+				for range testSet.Values() {
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if testSet.Size() != 10000 {
+		t.Fatalf("expected set size %d but got %d", 10000, testSet.Size())
+	}
+
+	popped := make([][]int, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for element, err := testSet.Pop(); err == nil; element, err = testSet.Pop() {
+				popped[i] = append(popped[i], element)
+			}
+		}()
+	}
+	wg.Wait()
+
+	seen := make(map[int]bool)
+	for _, elements := range popped {
+		for _, element := range elements {
+			if seen[element] {
+				t.Fatalf("element %d was popped more than once", element)
+			}
+			seen[element] = true
+		}
+	}
+	if len(seen) != 10000 {
+		t.Fatalf("expected %d elements to be popped but got %d", 10000, len(seen))
+	}
+}
+
+// benchmarks
+
+func benchmarkMixed(b *testing.B, testSet collections.Set[int]) {
+	for i := 0; i < 1000; i++ {
+		testSet.Add(i)
+	}
+
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			item := rand.Intn(2000)
+			switch rand.Intn(10) {
+			case 0:
+				testSet.Add(item)
+			case 1:
+				testSet.Remove(item)
+			default:
+				testSet.Contains(item)
+			}
+		}
+	})
+}
+
+func BenchmarkConcurrentSet(b *testing.B) {
+	benchmarkMixed(b, concurrentset.New[int]())
+}
+
+func BenchmarkSynchronizedMapSet(b *testing.B) {
+	benchmarkMixed(b, synchronized.Set(mapset.New[int]()))
+}
@@ -0,0 +1,169 @@
+// ©2022 Brandon Moller
+
+/*
+Package concurrentstack provides a lock-free implementation of [collections.Stack] that is safe for concurrent use.
+
+The Stack is a Treiber stack: like linkedstack, each element is stored in a node that points to the node beneath it, and Push and Pop replace the top pointer with an atomic compare-and-swap.
+Many goroutines can Push and Pop at once without blocking each other; a goroutine that loses a race simply retries.
+
+Nodes are never relinked once pushed, so iterators and sequences walk a consistent snapshot of the Stack as it was when they started.
+Sequences finish walking that snapshot regardless of later changes, but Iterators fail with ErrConcurrentModification once any element has been pushed or popped since they were created.
+
+Stacks implement [collections.RemovingIterable].
+Removing an element through a RemovingIterator only marks its node, which stays linked until a Pop reaches it; whichever of the two marks a node first is the one that removes its element.
+Peek, Pop, Empty, iterators and sequences all pass over marked nodes.
+Size is maintained by a separate atomic counter and is only eventually consistent; while other goroutines are pushing or popping it may briefly disagree with the number of elements.
+Empty always reflects the state of the top pointer.
+*/
+package concurrentstack
+
+import (
+	"iter"
+	"sync/atomic"
+
+	"github.com/bmoller/collections"
+)
+
+type iterator[T comparable] struct {
+	last          *node[T]
+	modifications uint64
+	next          *node[T]
+	stack         *stack[T]
+}
+
+func (i *iterator[T]) Next() (element T, err error) {
+	if i.stack.modifications.Load() != i.modifications {
+		return element, collections.ErrConcurrentModification
+	}
+	i.last = present(i.next)
+	if i.last == nil {
+		return element, collections.ErrNoMoreItems
+	}
+	i.next = i.last.previous
+
+	return i.last.value, nil
+}
+
+func (i *iterator[T]) Remove() error {
+	s := i.stack
+	if s.modifications.Load() != i.modifications {
+		return collections.ErrConcurrentModification
+	} else if i.last == nil {
+		return collections.ErrNothingToRemove
+	} else if !s.modifications.CompareAndSwap(i.modifications, i.modifications+1) || !i.last.removed.CompareAndSwap(false, true) {
+		// a Pop marks its node before counting itself, so it may have taken the element without changing the count yet
+		return collections.ErrConcurrentModification
+	}
+	s.size.Add(-1)
+	i.last, i.modifications = nil, i.modifications+1
+
+	return nil
+}
+
+type node[T comparable] struct {
+	previous *node[T]
+	removed  atomic.Bool
+	value    T
+}
+
+// present returns n or the first node below it that has not been removed, or nil if there is none.
+func present[T comparable](n *node[T]) *node[T] {
+	for n != nil && n.removed.Load() {
+		n = n.previous
+	}
+
+	return n
+}
+
+type stack[T comparable] struct {
+	modifications atomic.Uint64
+	size          atomic.Int64
+	top           atomic.Pointer[node[T]]
+}
+
+func New[T comparable]() collections.Stack[T] {
+	return new(stack[T])
+}
+
+func (s *stack[T]) All() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		i := 0
+		for n := present(s.top.Load()); n != nil; n = present(n.previous) {
+			if !yield(i, n.value) {
+				return
+			}
+			i++
+		}
+	}
+}
+
+func (s *stack[T]) Empty() bool {
+	return present(s.top.Load()) == nil
+}
+
+func (s *stack[T]) Iterator() collections.Iterator[T] {
+	return s.RemovingIterator().Next
+}
+
+func (s *stack[T]) Peek() (element T, err error) {
+	top := present(s.top.Load())
+	if top == nil {
+		return element, collections.ErrEmptyStack
+	}
+
+	return top.value, nil
+}
+
+func (s *stack[T]) Pop() (element T, err error) {
+	for {
+		top := s.top.Load()
+		if top == nil {
+			return element, collections.ErrEmptyStack
+		}
+		if s.top.CompareAndSwap(top, top.previous) && top.removed.CompareAndSwap(false, true) {
+			s.modifications.Add(1)
+			s.size.Add(-1)
+			return top.value, nil
+		}
+	}
+}
+
+func (s *stack[T]) Push(item T) {
+	top := &node[T]{
+		value: item,
+	}
+	for {
+		top.previous = s.top.Load()
+		if s.top.CompareAndSwap(top.previous, top) {
+			s.modifications.Add(1)
+			s.size.Add(1)
+			return
+		}
+	}
+}
+
+func (s *stack[T]) RemovingIterator() collections.RemovingIterator[T] {
+	// read before the top, so a Push or Pop racing with this call is reported rather than missed
+	modifications := s.modifications.Load()
+
+	return &iterator[T]{
+		modifications: modifications,
+		next:          s.top.Load(),
+		stack:         s,
+	}
+}
+
+func (s *stack[T]) Size() int {
+	// a Pop can be counted before the Push that preceded it
+	return int(max(s.size.Load(), 0))
+}
+
+func (s *stack[T]) Values() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for n := present(s.top.Load()); n != nil; n = present(n.previous) {
+			if !yield(n.value) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,200 @@
+// ©2022 Brandon Moller
+
+package concurrentstack_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/bmoller/collections"
+	"github.com/bmoller/collections/collectionstest"
+	"github.com/bmoller/collections/concurrentstack"
+	"github.com/bmoller/collections/linkedstack"
+	"github.com/bmoller/collections/synchronized"
+)
+
+func FuzzStack(f *testing.F) {
+	collectionstest.FuzzStack(f, concurrentstack.New[int])
+}
+
+func TestStack(t *testing.T) {
+	collectionstest.TestStack(t, concurrentstack.New[int])
+}
+
+func TestStackIterator(t *testing.T) {
+	stack := concurrentstack.New[int]()
+	if _, err := stack.Iterator()(); err == nil || !errors.Is(err, collections.ErrNoMoreItems) {
+		t.Fatalf("expected ErrNoMoreItems from Iterator on empty Stack but got: %v", err)
+	}
+
+	for i := 0; i < 1000; i++ {
+		stack.Push(i)
+	}
+	itr := stack.Iterator()
+	for i := 999; i > 499; i-- {
+		if element, err := itr(); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if element != i {
+			t.Fatalf("expected element with value %d but got %d", i, element)
+		}
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		stack.Pop()
+	}()
+	<-done
+	if _, err := itr(); err == nil || !errors.Is(err, collections.ErrConcurrentModification) {
+		t.Fatalf("expected ErrConcurrentModification after a Pop from another goroutine but got: %v", err)
+	}
+}
+
+func TestStackValues(t *testing.T) {
+	stack := concurrentstack.New[int]()
+	for i := 0; i < 1000; i++ {
+		stack.Push(i)
+	}
+
+	expected := 999
+	for element := range stack.Values() {
+		if element != expected {
+			t.Fatalf("expected element with value %d but got %d", expected, element)
+		}
+		stack.Pop()
+		expected--
+	}
+	if expected != -1 {
+		t.Fatalf("expected %d elements from Values but got %d", 1000, 999-expected)
+	}
+
+	stack.Push(0)
+	for range stack.Values() {
+		break
+	}
+}
+
+func TestStackConcurrent(t *testing.T) {
+	const (
+		goroutines = 8
+		perRoutine = 10000
+	)
+	stack := concurrentstack.New[int]()
+
+	var (
+		wg     sync.WaitGroup
+		popped [goroutines][]int
+	)
+	for g := 0; g < goroutines; g++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perRoutine; i++ {
+				stack.Push(g*perRoutine + i)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for len(popped[g]) < perRoutine/2 {
+				if element, err := stack.Pop(); err == nil {
+					popped[g] = append(popped[g], element)
+				}
+				stack.Peek()
+				stack.Size()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if size := stack.Size(); size != goroutines*perRoutine/2 {
+		t.Fatalf("expected stack size %d but got %d", goroutines*perRoutine/2, size)
+	}
+	seen := make([]bool, goroutines*perRoutine)
+	for _, elements := range popped {
+		for _, element := range elements {
+			if seen[element] {
+				t.Fatalf("element %d was returned more than once", element)
+			}
+			seen[element] = true
+		}
+	}
+	for element := range stack.Values() {
+		if seen[element] {
+			t.Fatalf("element %d was returned more than once", element)
+		}
+		seen[element] = true
+	}
+	for element, ok := range seen {
+		if !ok {
+			t.Fatalf("element %d was lost", element)
+		}
+	}
+}
+
+func TestStackRemovingIteratorConcurrent(t *testing.T) {
+	const size = 10000
+	stack := concurrentstack.New[int]()
+	for i := 0; i < size; i++ {
+		stack.Push(i)
+	}
+
+	// elements are removed from the top through RemovingIterators while another goroutine pops them
+	var popped []int
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for element, err := stack.Pop(); err == nil; element, err = stack.Pop() {
+			popped = append(popped, element)
+		}
+	}()
+	var removed []int
+	for {
+		itr := stack.(collections.RemovingIterable[int]).RemovingIterator()
+		element, err := itr.Next()
+		if errors.Is(err, collections.ErrNoMoreItems) {
+			break
+		} else if err == nil && itr.Remove() == nil {
+			removed = append(removed, element)
+		}
+	}
+	<-done
+
+	seen := make([]bool, size)
+	for _, element := range append(popped, removed...) {
+		if seen[element] {
+			t.Fatalf("element %d was returned more than once", element)
+		}
+		seen[element] = true
+	}
+	for element, ok := range seen {
+		if !ok {
+			t.Fatalf("element %d was lost", element)
+		}
+	}
+	if !stack.Empty() || stack.Size() != 0 {
+		t.Fatalf("expected stack to be empty but got size %d", stack.Size())
+	}
+}
+
+// benchmarks
+
+func benchmarkPushPop(b *testing.B, stack collections.Stack[int]) {
+	b.RunParallel(func(pb *testing.PB) {
+		for i := 0; pb.Next(); i++ {
+			if i%2 == 0 {
+				stack.Push(i)
+			} else {
+				stack.Pop()
+			}
+		}
+	})
+}
+
+func BenchmarkConcurrentStack(b *testing.B) {
+	benchmarkPushPop(b, concurrentstack.New[int]())
+}
+
+func BenchmarkSynchronizedLinkedStack(b *testing.B) {
+	benchmarkPushPop(b, synchronized.Stack(linkedstack.New[int]()))
+}
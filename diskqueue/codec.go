@@ -0,0 +1,62 @@
+// ©2022 Brandon Moller
+
+package diskqueue
+
+import (
+	"bytes"
+	"encoding/gob"
+	"encoding/json"
+)
+
+/*
+A Codec converts elements to and from the payload of a log record.
+Decode must accept any payload produced by Encode, including one written by an earlier run of the program.
+*/
+type Codec[T any] interface {
+	Decode(data []byte) (T, error)
+	Encode(element T) ([]byte, error)
+}
+
+type gobCodec[T any] struct{}
+
+/*
+GobCodec returns a Codec that encodes each element with [encoding/gob].
+It is used when no other Codec is configured.
+*/
+func GobCodec[T any]() Codec[T] {
+	return gobCodec[T]{}
+}
+
+func (gobCodec[T]) Decode(data []byte) (element T, err error) {
+	err = gob.NewDecoder(bytes.NewReader(data)).Decode(&element)
+
+	return element, err
+}
+
+func (gobCodec[T]) Encode(element T) ([]byte, error) {
+	var buffer bytes.Buffer
+	if err := gob.NewEncoder(&buffer).Encode(element); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+type jsonCodec[T any] struct{}
+
+/*
+JSONCodec returns a Codec that encodes each element with [encoding/json], which keeps the log readable by other tools.
+*/
+func JSONCodec[T any]() Codec[T] {
+	return jsonCodec[T]{}
+}
+
+func (jsonCodec[T]) Decode(data []byte) (element T, err error) {
+	err = json.Unmarshal(data, &element)
+
+	return element, err
+}
+
+func (jsonCodec[T]) Encode(element T) ([]byte, error) {
+	return json.Marshal(element)
+}
@@ -0,0 +1,103 @@
+// ©2022 Brandon Moller
+
+package diskqueue
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// Each record is a header holding the length and CRC-32 of its body, followed by the body.
+// The body is a record type, the sequence number of the element and, for a push, the encoded element.
+const (
+	headerSize    = 8
+	maxRecordSize = 1 << 30
+	recordPop     = byte(2)
+	recordPush    = byte(1)
+	recordRemove  = byte(3)
+	segmentSuffix = ".log"
+)
+
+// errTorn reports a record that the log ends part way through, or that is damaged with nothing after it, as a crash during its write would leave it.
+var errTorn = errors.New("torn record")
+
+type record struct {
+	kind    byte
+	payload []byte
+	seq     uint64
+}
+
+func appendRecord(buffer []byte, r record) []byte {
+	body := make([]byte, 9, 9+len(r.payload))
+	body[0] = r.kind
+	binary.BigEndian.PutUint64(body[1:], r.seq)
+	body = append(body, r.payload...)
+
+	buffer = binary.BigEndian.AppendUint32(buffer, uint32(len(body)))
+	buffer = binary.BigEndian.AppendUint32(buffer, crc32.ChecksumIEEE(body))
+
+	return append(buffer, body...)
+}
+
+// damaged returns errTorn if nothing follows a damaged record in reader, and err otherwise.
+func damaged(reader *bufio.Reader, err error) error {
+	if _, peekErr := reader.Peek(1); peekErr == io.EOF {
+		return errTorn
+	}
+
+	return err
+}
+
+// readRecord returns the next record and its size on disk, or io.EOF at a clean end of the log.
+// If the log ends part way through the next record, or the record is damaged and is the last one, it returns errTorn; a damaged record with more data after it is reported with any other error.
+func readRecord(reader *bufio.Reader) (r record, size int64, err error) {
+	var header [headerSize]byte
+	if _, err := io.ReadFull(reader, header[:]); err == io.EOF {
+		return r, 0, io.EOF
+	} else if err != nil {
+		return r, 0, errTorn
+	}
+
+	length := binary.BigEndian.Uint32(header[:4])
+	if length < 9 || length > maxRecordSize {
+		return r, 0, damaged(reader, fmt.Errorf("record length %d is invalid", length))
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(reader, body); err != nil {
+		return r, 0, errTorn
+	}
+	if crc32.ChecksumIEEE(body) != binary.BigEndian.Uint32(header[4:]) {
+		return r, 0, damaged(reader, errors.New("record checksum does not match"))
+	}
+
+	r.kind = body[0]
+	r.seq = binary.BigEndian.Uint64(body[1:9])
+	r.payload = body[9:]
+	if r.kind != recordPush && r.kind != recordPop && r.kind != recordRemove {
+		return r, 0, fmt.Errorf("record type %d is unknown", r.kind)
+	}
+
+	return r, headerSize + int64(length), nil
+}
+
+func segmentName(dir string, index uint64) string {
+	return filepath.Join(dir, fmt.Sprintf("%016x%s", index, segmentSuffix))
+}
+
+// segmentIndex parses the index from the name of a segment file, reporting false for any other file.
+func segmentIndex(name string) (uint64, bool) {
+	hex, ok := strings.CutSuffix(name, segmentSuffix)
+	if !ok || len(hex) != 16 {
+		return 0, false
+	}
+	index, err := strconv.ParseUint(hex, 16, 64)
+
+	return index, err == nil
+}
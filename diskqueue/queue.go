@@ -0,0 +1,500 @@
+// ©2022 Brandon Moller
+
+/*
+Package diskqueue provides a durable implementation of [collections.Queue] whose contents survive a restart or crash.
+
+Every Push and Pop appends a record to a write-ahead log before the queue changes, and Open rebuilds the queue by replaying the log.
+The log is divided into segment files in a directory owned by the queue; when a segment grows beyond the configured size a new one is started.
+Once every element pushed in a segment has been popped or removed the segment is no longer needed, and it is deleted.
+
+Queues implement [collections.RemovingIterable].
+Removing an element through a RemovingIterator appends a record naming the element, in the same way as Pop, and fails with the same errors.
+
+A crash can leave the last record of the newest segment partly written.
+Open discards such a record, so the Push or Pop that was being recorded is treated as never having happened.
+A record is only treated this way if the log ends within it, or if it fails its checksum and nothing follows it.
+Damage anywhere else in the log, including any earlier record of the newest segment, is reported as an error wrapping [collections.ErrInvalidEncoding].
+
+Records reach the operating system as soon as they are written, but are only flushed to stable storage according to the SyncEvery option, by Sync and by Close.
+Elements are encoded with a Codec, which is gob unless another is configured.
+
+The log provides durability rather than capacity: all elements are also kept in memory, and Peek and iteration never read from disk.
+A Queue is not safe for concurrent use, and a directory must only be opened by one Queue at a time.
+
+Push has no way to report an error, so a failure to encode or record an element is kept and returned by Err.
+After any error the Queue stops accepting changes; Pop returns the error, and it can only be cleared by closing and reopening the Queue.
+*/
+package diskqueue
+
+import (
+	"bufio"
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"os"
+	"slices"
+
+	"github.com/bmoller/collections"
+	"github.com/bmoller/collections/linkedqueue"
+)
+
+// defaultSegmentSize is used when Options.SegmentSize is not positive.
+const defaultSegmentSize int64 = 16 << 20
+
+/*
+Options configure a Queue when it is opened.
+The zero value uses gob encoding, 16 MiB segments and leaves syncing to Sync and Close.
+*/
+type Options[T comparable] struct {
+	Codec       Codec[T] // Codec for elements; GobCodec when nil
+	SegmentSize int64    // Size in bytes at which a new segment is started
+	SyncEvery   int      // Number of records between flushes to stable storage; 0 only flushes on Sync and Close
+}
+
+/*
+A Queue is a [collections.Queue] backed by a write-ahead log.
+Sync flushes every record written so far to stable storage.
+Close flushes the log and releases its files; every later change returns or records ErrQueueClosed.
+Err returns the error that stopped the Queue accepting changes, if any.
+*/
+type Queue[T comparable] interface {
+	collections.Queue[T]
+
+	Close() error
+	Err() error
+	Sync() error
+}
+
+// entry pairs an element with the sequence number it was pushed with, which records use to refer to it.
+type entry[T comparable] struct {
+	seq   uint64
+	value T
+}
+
+type iterator[T comparable] struct {
+	entries       collections.RemovingIterator[entry[T]]
+	last          uint64 // sequence number of the element returned last
+	modifications int
+	queue         *queue[T]
+	removable     bool
+}
+
+func (i *iterator[T]) Next() (element T, err error) {
+	if i.queue.modifications != i.modifications {
+		return element, collections.ErrConcurrentModification
+	}
+	e, err := i.entries.Next()
+	if err != nil {
+		return element, err
+	}
+	i.last, i.removable = e.seq, true
+
+	return e.value, nil
+}
+
+func (i *iterator[T]) Remove() error {
+	q := i.queue
+	if q.modifications != i.modifications {
+		return collections.ErrConcurrentModification
+	} else if !i.removable {
+		return collections.ErrNothingToRemove
+	}
+
+	if err := q.write(record{kind: recordRemove, seq: i.last}); err != nil {
+		return err
+	}
+	i.entries.Remove()
+	q.modifications++
+	i.modifications, i.removable = q.modifications, false
+	if err := q.compact(); err != nil {
+		q.fail(err)
+	}
+
+	return nil
+}
+
+type segment struct {
+	index uint64
+	last  uint64 // highest sequence number pushed in this or any earlier segment
+}
+
+type queue[T comparable] struct {
+	active        *os.File
+	activeSize    int64
+	codec         Codec[T]
+	dir           string
+	elements      collections.Queue[entry[T]]
+	err           error
+	modifications int
+	next          uint64 // sequence number for the next element pushed
+	options       Options[T]
+	segments      []segment
+	unsynced      int
+}
+
+/*
+Open opens the Queue stored in dir, creating the directory if needed, and replays its log.
+*/
+func Open[T comparable](dir string, options Options[T]) (Queue[T], error) {
+	if options.Codec == nil {
+		options.Codec = GobCodec[T]()
+	}
+	if options.SegmentSize <= 0 {
+		options.SegmentSize = defaultSegmentSize
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	q := &queue[T]{
+		codec:    options.Codec,
+		dir:      dir,
+		elements: linkedqueue.New[entry[T]](),
+		next:     1,
+		options:  options,
+	}
+	if err := q.replay(); err != nil {
+		return nil, err
+	}
+
+	created := len(q.segments) == 0
+	if created {
+		q.segments = append(q.segments, segment{index: 1})
+	}
+	active, err := os.OpenFile(segmentName(dir, q.segments[len(q.segments)-1].index), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	q.active = active
+	info, err := active.Stat()
+	if err == nil {
+		q.activeSize = info.Size()
+		err = q.compact()
+	}
+	if err == nil && created {
+		err = q.syncDir()
+	}
+	if err != nil {
+		q.active.Close()
+		return nil, err
+	}
+
+	return q, nil
+}
+
+// compact deletes the oldest segments while all of their elements have been popped or removed; the active segment is always kept.
+func (q *queue[T]) compact() error {
+	for len(q.segments) > 1 && q.segments[0].last < q.front() {
+		if err := os.Remove(segmentName(q.dir, q.segments[0].index)); err != nil {
+			return err
+		}
+		q.segments = q.segments[1:]
+	}
+
+	return nil
+}
+
+// front returns the sequence number of the element at the front, or the one the next Push will use if the queue is empty.
+func (q *queue[T]) front() uint64 {
+	if e, err := q.elements.Peek(); err == nil {
+		return e.seq
+	}
+
+	return q.next
+}
+
+// fail records err as the reason the queue stopped accepting changes.
+func (q *queue[T]) fail(err error) error {
+	if q.err == nil {
+		q.err = err
+	}
+
+	return q.err
+}
+
+// replay rebuilds the queue from the segments in the directory, truncating a torn record at the end of the newest one.
+func (q *queue[T]) replay() error {
+	entries, err := os.ReadDir(q.dir)
+	if err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if index, ok := segmentIndex(entry.Name()); ok && entry.Type().IsRegular() {
+			q.segments = append(q.segments, segment{index: index})
+		}
+	}
+	slices.SortFunc(q.segments, func(a, b segment) int {
+		return cmp.Compare(a.index, b.index)
+	})
+
+	var last, highest uint64
+	for i := range q.segments {
+		name := segmentName(q.dir, q.segments[i].index)
+		file, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+
+		var offset int64
+		reader := bufio.NewReader(file)
+		for {
+			r, size, err := readRecord(reader)
+			if errors.Is(err, errTorn) && i == len(q.segments)-1 {
+				file.Close()
+				if err := os.Truncate(name, offset); err != nil {
+					return err
+				}
+				break
+			} else if err == io.EOF {
+				file.Close()
+				break
+			} else if err == nil {
+				err = q.apply(r)
+			}
+			if err != nil {
+				file.Close()
+				return fmt.Errorf("%w: %s at offset %d: %w", collections.ErrInvalidEncoding, name, offset, err)
+			}
+			offset += size
+			highest = max(highest, r.seq)
+			if r.kind == recordPush {
+				last = r.seq
+			}
+		}
+		q.segments[i].last = last
+	}
+
+	if q.elements.Empty() {
+		q.next = highest + 1
+	}
+
+	return nil
+}
+
+// apply replays a single record against the elements in memory.
+func (q *queue[T]) apply(r record) error {
+	switch r.kind {
+	case recordPush:
+		if !q.elements.Empty() && r.seq != q.next {
+			return fmt.Errorf("push of element %d when %d was expected", r.seq, q.next)
+		}
+		element, err := q.codec.Decode(r.payload)
+		if err != nil {
+			return err
+		}
+		q.elements.Push(entry[T]{
+			seq:   r.seq,
+			value: element,
+		})
+		q.next = r.seq + 1
+	case recordPop:
+		// pops of elements from deleted segments are skipped
+		front, err := q.elements.Peek()
+		if err != nil || r.seq < front.seq {
+			return nil
+		} else if r.seq != front.seq {
+			return fmt.Errorf("pop of element %d when %d was at the front", r.seq, front.seq)
+		}
+		q.elements.Pop()
+	default:
+		// as with pops, removals of elements that are no longer present are skipped
+		if q.elements.Empty() {
+			return nil
+		} else if r.seq >= q.next {
+			return fmt.Errorf("removal of element %d when only %d have been pushed", r.seq, q.next-1)
+		}
+		itr := q.elements.(collections.RemovingIterable[entry[T]]).RemovingIterator()
+		for e, err := itr.Next(); err == nil; e, err = itr.Next() {
+			if e.seq == r.seq {
+				return itr.Remove()
+			}
+		}
+	}
+
+	return nil
+}
+
+// roll closes the active segment and starts the next one.
+func (q *queue[T]) roll() error {
+	if err := q.active.Sync(); err != nil {
+		return err
+	}
+	if err := q.active.Close(); err != nil {
+		return err
+	}
+
+	current := q.segments[len(q.segments)-1]
+	next := segment{
+		index: current.index + 1,
+		last:  current.last,
+	}
+	file, err := os.OpenFile(segmentName(q.dir, next.index), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+	q.active, q.activeSize, q.unsynced = file, 0, 0
+	q.segments = append(q.segments, next)
+	if err := q.syncDir(); err != nil {
+		return err
+	}
+
+	return q.compact()
+}
+
+// syncDir makes the creation of a segment durable when the queue is configured to sync.
+func (q *queue[T]) syncDir() error {
+	if q.options.SyncEvery <= 0 {
+		return nil
+	}
+	dir, err := os.Open(q.dir)
+	if err != nil {
+		return err
+	}
+	defer dir.Close()
+
+	return dir.Sync()
+}
+
+// write appends a record to the active segment, starting a new segment first if it is full.
+func (q *queue[T]) write(r record) error {
+	if q.err != nil {
+		return q.err
+	}
+	if q.activeSize >= q.options.SegmentSize {
+		if err := q.roll(); err != nil {
+			return q.fail(err)
+		}
+	}
+
+	n, err := q.active.Write(appendRecord(nil, r))
+	q.activeSize += int64(n)
+	if err != nil {
+		return q.fail(err)
+	}
+	q.unsynced++
+	if q.options.SyncEvery > 0 && q.unsynced >= q.options.SyncEvery {
+		if err := q.Sync(); err != nil {
+			return q.fail(err)
+		}
+	}
+
+	return nil
+}
+
+func (q *queue[T]) All() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for i, e := range q.elements.All() {
+			if !yield(i, e.value) {
+				return
+			}
+		}
+	}
+}
+
+func (q *queue[T]) Close() error {
+	if errors.Is(q.err, collections.ErrQueueClosed) {
+		return nil
+	}
+	q.fail(collections.ErrQueueClosed)
+
+	syncErr := q.active.Sync()
+	if err := q.active.Close(); err != nil {
+		return err
+	}
+
+	return syncErr
+}
+
+func (q *queue[T]) Empty() bool {
+	return q.elements.Empty()
+}
+
+func (q *queue[T]) Err() error {
+	return q.err
+}
+
+func (q *queue[T]) Iterator() collections.Iterator[T] {
+	return q.RemovingIterator().Next
+}
+
+func (q *queue[T]) Peek() (T, error) {
+	e, err := q.elements.Peek()
+
+	return e.value, err
+}
+
+func (q *queue[T]) Pop() (element T, err error) {
+	if q.err != nil {
+		return element, q.err
+	}
+	if q.elements.Empty() {
+		return element, collections.ErrEmptyQueue
+	}
+
+	if err := q.write(record{kind: recordPop, seq: q.front()}); err != nil {
+		return element, err
+	}
+	e, _ := q.elements.Pop()
+	q.modifications++
+	if err := q.compact(); err != nil {
+		q.fail(err)
+	}
+
+	return e.value, nil
+}
+
+func (q *queue[T]) Push(item T) {
+	if q.err != nil {
+		return
+	}
+	payload, err := q.codec.Encode(item)
+	if err != nil {
+		q.fail(err)
+		return
+	}
+
+	seq := q.next
+	if err := q.write(record{kind: recordPush, payload: payload, seq: seq}); err != nil {
+		return
+	}
+	q.elements.Push(entry[T]{
+		seq:   seq,
+		value: item,
+	})
+	q.modifications++
+	q.next++
+	q.segments[len(q.segments)-1].last = seq
+}
+
+func (q *queue[T]) RemovingIterator() collections.RemovingIterator[T] {
+	return &iterator[T]{
+		entries:       q.elements.(collections.RemovingIterable[entry[T]]).RemovingIterator(),
+		modifications: q.modifications,
+		queue:         q,
+	}
+}
+
+func (q *queue[T]) Size() int {
+	return q.elements.Size()
+}
+
+func (q *queue[T]) Sync() error {
+	if errors.Is(q.err, collections.ErrQueueClosed) {
+		return q.err
+	}
+	q.unsynced = 0
+
+	return q.active.Sync()
+}
+
+func (q *queue[T]) Values() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for e := range q.elements.Values() {
+			if !yield(e.value) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,466 @@
+// ©2022 Brandon Moller
+
+package diskqueue_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/bmoller/collections"
+	"github.com/bmoller/collections/collectionstest"
+	"github.com/bmoller/collections/diskqueue"
+)
+
+type failingCodec struct {
+	decode bool
+}
+
+func (c failingCodec) Decode(data []byte) (int, error) {
+	if c.decode {
+		return 0, errors.New("decode failed")
+	}
+	return diskqueue.GobCodec[int]().Decode(data)
+}
+
+func (c failingCodec) Encode(element int) ([]byte, error) {
+	if !c.decode && element < 0 {
+		return nil, errors.New("encode failed")
+	}
+	return diskqueue.GobCodec[int]().Encode(element)
+}
+
+func open(t *testing.T, dir string, options diskqueue.Options[int]) diskqueue.Queue[int] {
+	t.Helper()
+	queue, err := diskqueue.Open(dir, options)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	t.Cleanup(func() { queue.Close() })
+
+	return queue
+}
+
+func segments(t *testing.T, dir string) []string {
+	t.Helper()
+	names, err := filepath.Glob(filepath.Join(dir, "*.log"))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	slices.Sort(names)
+
+	return names
+}
+
+func FuzzQueue(f *testing.F) {
+	dir := f.TempDir()
+	collectionstest.FuzzQueue(f, func() collections.Queue[int] {
+		queueDir, err := os.MkdirTemp(dir, "")
+		if err != nil {
+			panic(err)
+		}
+		queue, err := diskqueue.Open(queueDir, diskqueue.Options[int]{SegmentSize: 4096})
+		if err != nil {
+			panic(err)
+		}
+
+		return queue
+	})
+}
+
+func TestQueue(t *testing.T) {
+	// small segments, so that the tests roll over and compact segments as they go
+	collectionstest.TestQueue(t, func() collections.Queue[int] {
+		queue, err := diskqueue.Open(t.TempDir(), diskqueue.Options[int]{SegmentSize: 4096})
+		if err != nil {
+			panic(err)
+		}
+		t.Cleanup(func() { queue.Close() })
+
+		return queue
+	})
+}
+
+func TestQueueClose(t *testing.T) {
+	queue := open(t, t.TempDir(), diskqueue.Options[int]{})
+	queue.Push(1)
+	if err := queue.Close(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := queue.Close(); err != nil {
+		t.Fatalf("expected second Close to succeed but got: %s", err)
+	}
+
+	if _, err := queue.Pop(); err == nil || !errors.Is(err, collections.ErrQueueClosed) {
+		t.Fatalf("expected ErrQueueClosed from Pop on closed queue but got: %v", err)
+	}
+	if err := queue.Sync(); err == nil || !errors.Is(err, collections.ErrQueueClosed) {
+		t.Fatalf("expected ErrQueueClosed from Sync on closed queue but got: %v", err)
+	}
+	queue.Push(2)
+	if queue.Size() != 1 {
+		t.Fatalf("expected Push on closed queue to be ignored but size is %d", queue.Size())
+	}
+	if err := queue.Err(); err == nil || !errors.Is(err, collections.ErrQueueClosed) {
+		t.Fatalf("expected ErrQueueClosed from Err on closed queue but got: %v", err)
+	}
+}
+
+func TestQueueCodec(t *testing.T) {
+	type job struct {
+		ID   int
+		Name string
+	}
+
+	dir := t.TempDir()
+	options := diskqueue.Options[job]{Codec: diskqueue.JSONCodec[job]()}
+	queue, err := diskqueue.Open(dir, options)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	for i := 0; i < 100; i++ {
+		queue.Push(job{ID: i, Name: "job"})
+	}
+	queue.Close()
+
+	queue, err = diskqueue.Open(dir, options)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	defer queue.Close()
+	for i := 0; i < 100; i++ {
+		if element, err := queue.Pop(); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if element != (job{ID: i, Name: "job"}) {
+			t.Fatalf("expected element %v but got %v", job{ID: i, Name: "job"}, element)
+		}
+	}
+}
+
+func TestQueueCompaction(t *testing.T) {
+	dir := t.TempDir()
+	queue := open(t, dir, diskqueue.Options[int]{SegmentSize: 256})
+	for i := 0; i < 1000; i++ {
+		queue.Push(i)
+	}
+	if n := len(segments(t, dir)); n < 10 {
+		t.Fatalf("expected the log to be split into segments but found %d", n)
+	}
+
+	for i := 0; i < 1000; i++ {
+		if element, err := queue.Pop(); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if element != i {
+			t.Fatalf("expected element %d but got %d", i, element)
+		}
+	}
+	queue.Push(1000)
+	if n := len(segments(t, dir)); n > 2 {
+		t.Fatalf("expected consumed segments to be deleted but found %d", n)
+	}
+	queue.Close()
+
+	queue = open(t, dir, diskqueue.Options[int]{SegmentSize: 256})
+	if queue.Size() != 1 {
+		t.Fatalf("expected queue size %d but got %d", 1, queue.Size())
+	} else if element, _ := queue.Peek(); element != 1000 {
+		t.Fatalf("expected element with value %d but got %d", 1000, element)
+	}
+}
+
+func TestQueueCorruption(t *testing.T) {
+	dir := t.TempDir()
+	queue := open(t, dir, diskqueue.Options[int]{SegmentSize: 256})
+	for i := 0; i < 100; i++ {
+		queue.Push(i)
+	}
+	queue.Close()
+
+	names := segments(t, dir)
+	data, err := os.ReadFile(names[0])
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	data[len(data)-1] ^= 0xff
+	if err := os.WriteFile(names[0], data, 0o644); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, err := diskqueue.Open(dir, diskqueue.Options[int]{}); err == nil || !errors.Is(err, collections.ErrInvalidEncoding) {
+		t.Fatalf("expected ErrInvalidEncoding from Open with a damaged segment but got: %v", err)
+	}
+}
+
+func TestQueueCorruptionNewestSegment(t *testing.T) {
+	dir := t.TempDir()
+	queue := open(t, dir, diskqueue.Options[int]{})
+	for i := 0; i < 7; i++ {
+		queue.Push(i)
+	}
+	queue.Close()
+
+	// the first record fails its checksum, but is followed by the other six
+	name := segments(t, dir)[0]
+	data, err := os.ReadFile(name)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	data[9] ^= 0xff
+	if err := os.WriteFile(name, data, 0o644); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, err := diskqueue.Open(dir, diskqueue.Options[int]{}); err == nil || !errors.Is(err, collections.ErrInvalidEncoding) {
+		t.Fatalf("expected ErrInvalidEncoding from Open with a damaged record in the newest segment but got: %v", err)
+	}
+	if info, err := os.Stat(name); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if info.Size() != int64(len(data)) {
+		t.Fatalf("expected damaged segment to be left at %d bytes but got %d", len(data), info.Size())
+	}
+}
+
+func TestQueueCrash(t *testing.T) {
+	dir := t.TempDir()
+	queue := open(t, dir, diskqueue.Options[int]{})
+	for i := 0; i < 1000; i++ {
+		queue.Push(i)
+	}
+	for i := 0; i < 500; i++ {
+		queue.Pop()
+	}
+
+	recovered := open(t, dir, diskqueue.Options[int]{})
+	if recovered.Size() != 500 {
+		t.Fatalf("expected queue size %d but got %d", 500, recovered.Size())
+	}
+	expected := 500
+	for element := range recovered.Values() {
+		if element != expected {
+			t.Fatalf("expected element with value %d but got %d", expected, element)
+		}
+		expected++
+	}
+}
+
+func TestQueueErr(t *testing.T) {
+	queue := open(t, t.TempDir(), diskqueue.Options[int]{Codec: failingCodec{}})
+	queue.Push(1)
+	if err := queue.Err(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	queue.Push(-1)
+	if err := queue.Err(); err == nil {
+		t.Fatal("expected error from Err after a failed Push")
+	}
+	queue.Push(2)
+	if queue.Size() != 1 {
+		t.Fatalf("expected queue size %d but got %d", 1, queue.Size())
+	}
+	if _, err := queue.Pop(); err == nil || err != queue.Err() {
+		t.Fatalf("expected the error from Err from Pop but got: %v", err)
+	}
+}
+
+func TestQueueReopen(t *testing.T) {
+	dir := t.TempDir()
+	queue := open(t, dir, diskqueue.Options[int]{})
+	for i := 0; i < 1000; i++ {
+		queue.Push(i)
+	}
+	for i := 0; i < 500; i++ {
+		queue.Pop()
+	}
+	queue.Close()
+
+	queue = open(t, dir, diskqueue.Options[int]{})
+	for i := 1000; i < 1500; i++ {
+		queue.Push(i)
+	}
+	queue.Close()
+
+	queue = open(t, dir, diskqueue.Options[int]{})
+	for i := 500; i < 1500; i++ {
+		if element, err := queue.Pop(); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if element != i {
+			t.Fatalf("expected element %d but got %d", i, element)
+		}
+	}
+	queue.Close()
+
+	queue = open(t, dir, diskqueue.Options[int]{})
+	if !queue.Empty() {
+		t.Fatalf("expected reopened queue to be empty but got size %d", queue.Size())
+	}
+	queue.Push(1500)
+	queue.Close()
+	if queue = open(t, dir, diskqueue.Options[int]{}); queue.Size() != 1 {
+		t.Fatalf("expected queue size %d but got %d", 1, queue.Size())
+	}
+}
+
+func TestQueueRemovingIterator(t *testing.T) {
+	dir := t.TempDir()
+	queue := open(t, dir, diskqueue.Options[int]{SegmentSize: 256})
+	for i := 0; i < 1000; i++ {
+		queue.Push(i)
+	}
+	itr := queue.(collections.RemovingIterable[int]).RemovingIterator()
+	for element, err := itr.Next(); err == nil; element, err = itr.Next() {
+		if element%2 == 0 && element < 500 {
+			continue
+		}
+		if err := itr.Remove(); err != nil {
+			t.Fatalf("unexpected error removing %d: %s", element, err)
+		}
+	}
+	queue.Close()
+
+	queue = open(t, dir, diskqueue.Options[int]{SegmentSize: 256})
+	if queue.Size() != 250 {
+		t.Fatalf("expected removals to be replayed leaving size %d but got %d", 250, queue.Size())
+	}
+	for i := 0; i < 500; i += 2 {
+		if element, err := queue.Pop(); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if element != i {
+			t.Fatalf("expected element %d but got %d", i, element)
+		}
+	}
+	if n := len(segments(t, dir)); n > 2 {
+		t.Fatalf("expected segments with only popped or removed elements to be deleted but found %d", n)
+	}
+	queue.Push(1000)
+	queue.Close()
+
+	queue = open(t, dir, diskqueue.Options[int]{SegmentSize: 256})
+	if element, err := queue.Pop(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if element != 1000 {
+		t.Fatalf("expected element %d but got %d", 1000, element)
+	}
+	if !queue.Empty() {
+		t.Fatalf("expected reopened queue to be empty but got size %d", queue.Size())
+	}
+
+	queue.Push(1001)
+	itr = queue.(collections.RemovingIterable[int]).RemovingIterator()
+	itr.Next()
+	queue.Close()
+	if err := itr.Remove(); err == nil || !errors.Is(err, collections.ErrQueueClosed) {
+		t.Fatalf("expected ErrQueueClosed from Remove on closed queue but got: %v", err)
+	}
+}
+
+func TestQueueReplay(t *testing.T) {
+	dir := t.TempDir()
+	queue := open(t, dir, diskqueue.Options[int]{})
+	queue.Push(1)
+	queue.Close()
+	name := segments(t, dir)[0]
+	single, _ := os.ReadFile(name)
+
+	queue = open(t, dir, diskqueue.Options[int]{})
+	queue.Push(2)
+	queue.Pop()
+	queue.Close()
+	popped, _ := os.ReadFile(name)
+	queue = open(t, dir, diskqueue.Options[int]{})
+	queue.Pop()
+	queue.Close()
+	both, _ := os.ReadFile(name)
+
+	// the last record pops the second element, which is not at the front of a log holding only the first push
+	os.WriteFile(name, append(single, both[len(popped):]...), 0o644)
+	if _, err := diskqueue.Open(dir, diskqueue.Options[int]{}); err == nil || !errors.Is(err, collections.ErrInvalidEncoding) {
+		t.Fatalf("expected ErrInvalidEncoding from Open with an out of order pop but got: %v", err)
+	}
+
+	// the first push repeated is out of sequence
+	os.WriteFile(name, append(single, single...), 0o644)
+	if _, err := diskqueue.Open(dir, diskqueue.Options[int]{}); err == nil || !errors.Is(err, collections.ErrInvalidEncoding) {
+		t.Fatalf("expected ErrInvalidEncoding from Open with an out of order push but got: %v", err)
+	}
+
+	os.WriteFile(name, single, 0o644)
+	if _, err := diskqueue.Open(dir, diskqueue.Options[int]{Codec: failingCodec{decode: true}}); err == nil || !errors.Is(err, collections.ErrInvalidEncoding) {
+		t.Fatalf("expected ErrInvalidEncoding from Open with a failing codec but got: %v", err)
+	}
+}
+
+func TestQueueSync(t *testing.T) {
+	dir := t.TempDir()
+	queue := open(t, dir, diskqueue.Options[int]{SegmentSize: 256, SyncEvery: 1})
+	for i := 0; i < 100; i++ {
+		queue.Push(i)
+	}
+	if err := queue.Sync(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if err := queue.Err(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+}
+
+func TestQueueTornRecord(t *testing.T) {
+	dir := t.TempDir()
+	queue := open(t, dir, diskqueue.Options[int]{})
+	for i := 0; i < 10; i++ {
+		queue.Push(i)
+	}
+	queue.Close()
+
+	name := segments(t, dir)[0]
+	info, _ := os.Stat(name)
+	if err := os.Truncate(name, info.Size()-3); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	queue = open(t, dir, diskqueue.Options[int]{})
+	if queue.Size() != 9 {
+		t.Fatalf("expected the torn push to be discarded but got size %d", queue.Size())
+	}
+	queue.Push(9)
+	queue.Close()
+
+	for _, tail := range [][]byte{{0, 0, 0}, make([]byte, 8), {0, 0, 0, 9, 1, 2, 3, 4, 1}, {0, 0, 0, 9, 1, 2, 3, 4, 1, 0, 0, 0, 0, 0, 0, 0, 10}} {
+		file, _ := os.OpenFile(name, os.O_WRONLY|os.O_APPEND, 0o644)
+		file.Write(tail)
+		file.Close()
+		if queue = open(t, dir, diskqueue.Options[int]{}); queue.Size() != 10 {
+			t.Fatalf("expected invalid record %v to be discarded but got size %d", tail, queue.Size())
+		}
+		queue.Close()
+	}
+
+	queue = open(t, dir, diskqueue.Options[int]{})
+	for i := 0; i < 10; i++ {
+		if element, err := queue.Pop(); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if element != i {
+			t.Fatalf("expected element %d but got %d", i, element)
+		}
+	}
+}
+
+func TestOpen(t *testing.T) {
+	dir := t.TempDir()
+	for _, name := range []string{"README", "zzzzzzzzzzzzzzzz.log", "1.log"} {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte("not a segment"), 0o644); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	queue := open(t, dir, diskqueue.Options[int]{})
+	queue.Push(1)
+	queue.Close()
+	if queue = open(t, dir, diskqueue.Options[int]{}); queue.Size() != 1 {
+		t.Fatalf("expected other files in the directory to be ignored but got size %d", queue.Size())
+	}
+
+	file := filepath.Join(dir, "README")
+	if _, err := diskqueue.Open(file, diskqueue.Options[int]{}); err == nil {
+		t.Fatal("expected error from Open on a file")
+	}
+}
@@ -0,0 +1,89 @@
+// ©2022 Brandon Moller
+
+/*
+Package hashmap is an implementation of [collections.Map] backed by a built-in Go map.
+
+All lookup and capacity operations are handled by the backing map, so performance should match the performance of a map of the same size.
+No order of entries is guaranteed, even between successive iterations.
+*/
+package hashmap
+
+import (
+	"iter"
+
+	"github.com/bmoller/collections"
+)
+
+type hashMap[K comparable, V any] struct {
+	data map[K]V
+}
+
+func New[K comparable, V any]() collections.Map[K, V] {
+	return &hashMap[K, V]{
+		data: make(map[K]V),
+	}
+}
+
+func (m *hashMap[K, V]) Clear() {
+	clear(m.data)
+}
+
+func (m *hashMap[K, V]) ContainsKey(key K) bool {
+	_, ok := m.data[key]
+	return ok
+}
+
+func (m *hashMap[K, V]) Delete(key K) {
+	delete(m.data, key)
+}
+
+func (m *hashMap[K, V]) Empty() bool {
+	return len(m.data) == 0
+}
+
+func (m *hashMap[K, V]) Entries() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for key, value := range m.data {
+			if !yield(key, value) {
+				return
+			}
+		}
+	}
+}
+
+func (m *hashMap[K, V]) Get(key K) (V, error) {
+	value, ok := m.data[key]
+	if !ok {
+		return value, collections.ErrKeyNotFound
+	}
+
+	return value, nil
+}
+
+func (m *hashMap[K, V]) Keys() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for key := range m.data {
+			if !yield(key) {
+				return
+			}
+		}
+	}
+}
+
+func (m *hashMap[K, V]) Put(key K, value V) {
+	m.data[key] = value
+}
+
+func (m *hashMap[K, V]) Size() int {
+	return len(m.data)
+}
+
+func (m *hashMap[K, V]) Values() iter.Seq[V] {
+	return func(yield func(V) bool) {
+		for _, value := range m.data {
+			if !yield(value) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,181 @@
+// ©2022 Brandon Moller
+
+package hashmap_test
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/bmoller/collections"
+	"github.com/bmoller/collections/collectionstest"
+	"github.com/bmoller/collections/hashmap"
+)
+
+func FuzzMap(f *testing.F) {
+	collectionstest.FuzzMap(f, hashmap.New[int, int])
+}
+
+func TestMapClear(t *testing.T) {
+	testMap := hashmap.New[int, string]()
+	for i := 0; i < 1000; i++ {
+		testMap.Put(i, strconv.Itoa(i))
+	}
+	testMap.Clear()
+	if testMap.Size() != 0 || !testMap.Empty() {
+		t.Fatal("expected map to be empty after Clear")
+	}
+	for range testMap.Entries() {
+		t.Fatal("expected no entries after Clear")
+	}
+	testMap.Put(0, "0")
+	if testMap.Size() != 1 {
+		t.Fatalf("expected map size %d but got %d", 1, testMap.Size())
+	}
+}
+
+func TestMapContainsKey(t *testing.T) {
+	testMap := hashmap.New[int, string]()
+	for i := 0; i < 1000; i += 2 {
+		testMap.Put(i, strconv.Itoa(i))
+	}
+	for i := 0; i < 1000; i++ {
+		if testMap.ContainsKey(i) != (i%2 == 0) {
+			t.Fatalf("unexpected result from ContainsKey for %d", i)
+		}
+	}
+}
+
+func TestMapDelete(t *testing.T) {
+	testMap := hashmap.New[int, string]()
+	for i := 0; i < 1000; i++ {
+		testMap.Put(i, strconv.Itoa(i))
+	}
+	testMap.Delete(-1)
+	if testMap.Size() != 1000 {
+		t.Fatalf("expected map size %d after deleting missing key but got %d", 1000, testMap.Size())
+	}
+	for i := 0; i < 1000; i += 2 {
+		testMap.Delete(i)
+	}
+	if testMap.Size() != 500 {
+		t.Fatalf("expected map size %d but got %d", 500, testMap.Size())
+	}
+	for i := 0; i < 1000; i++ {
+		if testMap.ContainsKey(i) != (i%2 == 1) {
+			t.Fatalf("unexpected result from ContainsKey for %d", i)
+		}
+	}
+	for key, value := range testMap.Entries() {
+		if key%2 == 0 || value != strconv.Itoa(key) {
+			t.Fatalf("unexpected entry %d: %s", key, value)
+		}
+	}
+}
+
+func TestMapEmpty(t *testing.T) {
+	testMap := hashmap.New[int, string]()
+	if !testMap.Empty() {
+		t.Fatal("expected new map to be empty")
+	}
+	testMap.Put(0, "0")
+	if testMap.Empty() {
+		t.Fatal("expected map to not be empty after Put")
+	}
+}
+
+func TestMapGet(t *testing.T) {
+	testMap := hashmap.New[int, string]()
+	if _, err := testMap.Get(0); err == nil || !errors.Is(err, collections.ErrKeyNotFound) {
+		t.Fatalf("expected ErrKeyNotFound from Get on empty map but got: %v", err)
+	}
+
+	for i := 0; i < 1000; i++ {
+		testMap.Put(i, strconv.Itoa(i))
+	}
+	for i := 0; i < 1000; i++ {
+		if value, err := testMap.Get(i); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if value != strconv.Itoa(i) {
+			t.Fatalf("expected value %q but got %q", strconv.Itoa(i), value)
+		}
+	}
+	if _, err := testMap.Get(1000); err == nil || !errors.Is(err, collections.ErrKeyNotFound) {
+		t.Fatalf("expected ErrKeyNotFound from Get with missing key but got: %v", err)
+	}
+}
+
+func TestMapPut(t *testing.T) {
+	testMap := hashmap.New[int, string]()
+	for i := 0; i < 1000; i++ {
+		testMap.Put(i%100, strconv.Itoa(i))
+	}
+	if testMap.Size() != 100 {
+		t.Fatalf("expected map size %d but got %d", 100, testMap.Size())
+	}
+	for i := 0; i < 100; i++ {
+		if value, _ := testMap.Get(i); value != strconv.Itoa(i+900) {
+			t.Fatalf("expected value %q but got %q", strconv.Itoa(i+900), value)
+		}
+	}
+}
+
+func TestMapSize(t *testing.T) {
+	testMap := hashmap.New[int, string]()
+	for i := 1; i < 1001; i++ {
+		testMap.Put(i, strconv.Itoa(i))
+		if testMap.Size() != i {
+			t.Fatalf("expected map size %d but got %d", i, testMap.Size())
+		}
+	}
+}
+
+func TestMapIteration(t *testing.T) {
+	testMap := hashmap.New[int, string]()
+	for i := 0; i < 1000; i++ {
+		testMap.Put(i, strconv.Itoa(i))
+	}
+
+	keys := make(map[int]bool)
+	for key, value := range testMap.Entries() {
+		if value != strconv.Itoa(key) {
+			t.Fatalf("expected value %q for key %d but got %q", strconv.Itoa(key), key, value)
+		}
+		keys[key] = true
+	}
+	if len(keys) != 1000 {
+		t.Fatalf("expected %d entries but got %d", 1000, len(keys))
+	}
+
+	count := 0
+	for key := range testMap.Keys() {
+		if !keys[key] {
+			t.Fatalf("unexpected key %d", key)
+		}
+		count++
+	}
+	if count != 1000 {
+		t.Fatalf("expected %d keys but got %d", 1000, count)
+	}
+
+	count = 0
+	for value := range testMap.Values() {
+		if key, err := strconv.Atoi(value); err != nil || !keys[key] {
+			t.Fatalf("unexpected value %q", value)
+		}
+		count++
+	}
+	if count != 1000 {
+		t.Fatalf("expected %d values but got %d", 1000, count)
+	}
+
+	for range testMap.Entries() {
+		break
+	}
+	for range testMap.Keys() {
+		break
+	}
+	for range testMap.Values() {
+		break
+	}
+}
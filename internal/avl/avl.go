@@ -0,0 +1,319 @@
+// ©2022 Brandon Moller
+
+/*
+Package avl implements the AVL-balanced binary search tree shared by treemap and treeset.
+
+A Tree maps keys to values, ordered by a compare function supplied when the tree is created.
+Insertion, deletion, lookup and the nearest-key queries all run in O(log n) time.
+*/
+package avl
+
+type node[K, V any] struct {
+	height int
+	key    K
+	left   *node[K, V]
+	right  *node[K, V]
+	value  V
+}
+
+func height[K, V any](n *node[K, V]) int {
+	if n == nil {
+		return 0
+	}
+
+	return n.height
+}
+
+func (n *node[K, V]) balance() *node[K, V] {
+	n.update()
+	switch skew := height(n.left) - height(n.right); {
+	case skew > 1:
+		if height(n.left.left) < height(n.left.right) {
+			n.left = n.left.rotateLeft()
+		}
+		return n.rotateRight()
+	case skew < -1:
+		if height(n.right.right) < height(n.right.left) {
+			n.right = n.right.rotateRight()
+		}
+		return n.rotateLeft()
+	}
+
+	return n
+}
+
+func (n *node[K, V]) rotateLeft() *node[K, V] {
+	root := n.right
+	n.right = root.left
+	root.left = n
+	n.update()
+	root.update()
+
+	return root
+}
+
+func (n *node[K, V]) rotateRight() *node[K, V] {
+	root := n.left
+	n.left = root.right
+	root.right = n
+	n.update()
+	root.update()
+
+	return root
+}
+
+func (n *node[K, V]) update() {
+	n.height = max(height(n.left), height(n.right)) + 1
+}
+
+/*
+A Tree is an AVL-balanced binary search tree of keys and their values.
+*/
+type Tree[K, V any] struct {
+	compare func(a, b K) int
+	root    *node[K, V]
+	size    int
+}
+
+/*
+New creates an empty Tree with keys ordered by compare, which must behave as [cmp.Compare] does.
+*/
+func New[K, V any](compare func(a, b K) int) *Tree[K, V] {
+	return &Tree[K, V]{
+		compare: compare,
+	}
+}
+
+func (t *Tree[K, V]) ascend(n *node[K, V], yield func(K, V) bool) bool {
+	if n == nil {
+		return true
+	}
+
+	return t.ascend(n.left, yield) && yield(n.key, n.value) && t.ascend(n.right, yield)
+}
+
+func (t *Tree[K, V]) ascendRange(n *node[K, V], from, to K, yield func(K, V) bool) bool {
+	if n == nil {
+		return true
+	}
+
+	lower, upper := t.compare(n.key, from), t.compare(n.key, to)
+	if lower > 0 && !t.ascendRange(n.left, from, to, yield) {
+		return false
+	}
+	if lower >= 0 && upper < 0 && !yield(n.key, n.value) {
+		return false
+	}
+	if upper < 0 {
+		return t.ascendRange(n.right, from, to, yield)
+	}
+
+	return true
+}
+
+// delete removes key from the subtree rooted at n and returns its new root, and whether key was present.
+func (t *Tree[K, V]) delete(n *node[K, V], key K) (*node[K, V], bool) {
+	if n == nil {
+		return nil, false
+	}
+
+	var deleted bool
+	switch c := t.compare(key, n.key); {
+	case c < 0:
+		n.left, deleted = t.delete(n.left, key)
+	case c > 0:
+		n.right, deleted = t.delete(n.right, key)
+	default:
+		if n.left == nil {
+			return n.right, true
+		} else if n.right == nil {
+			return n.left, true
+		}
+		successor := n.right
+		for successor.left != nil {
+			successor = successor.left
+		}
+		n.key, n.value = successor.key, successor.value
+		n.right, deleted = t.delete(n.right, successor.key)
+	}
+
+	return n.balance(), deleted
+}
+
+func (t *Tree[K, V]) descend(n *node[K, V], yield func(K, V) bool) bool {
+	if n == nil {
+		return true
+	}
+
+	return t.descend(n.right, yield) && yield(n.key, n.value) && t.descend(n.left, yield)
+}
+
+// insert adds key to the subtree rooted at n, or replaces its value if it is present, and returns the new root and whether key was added.
+func (t *Tree[K, V]) insert(n *node[K, V], key K, value V) (*node[K, V], bool) {
+	if n == nil {
+		return &node[K, V]{
+			height: 1,
+			key:    key,
+			value:  value,
+		}, true
+	}
+
+	var inserted bool
+	switch c := t.compare(key, n.key); {
+	case c < 0:
+		n.left, inserted = t.insert(n.left, key, value)
+	case c > 0:
+		n.right, inserted = t.insert(n.right, key, value)
+	default:
+		n.value = value
+		return n, false
+	}
+
+	return n.balance(), inserted
+}
+
+/*
+Ascend calls yield for every key and value in ascending order of keys, stopping early if yield returns false.
+*/
+func (t *Tree[K, V]) Ascend(yield func(K, V) bool) {
+	t.ascend(t.root, yield)
+}
+
+/*
+AscendRange calls yield in ascending order for every key from from (inclusive) to to (exclusive), stopping early if yield returns false.
+Subtrees outside the range are not visited.
+*/
+func (t *Tree[K, V]) AscendRange(from, to K, yield func(K, V) bool) {
+	t.ascendRange(t.root, from, to, yield)
+}
+
+/*
+Clear removes every key from the Tree.
+*/
+func (t *Tree[K, V]) Clear() {
+	t.root = nil
+	t.size = 0
+}
+
+/*
+Delete removes key and its value from the Tree, and reports whether it was present.
+*/
+func (t *Tree[K, V]) Delete(key K) bool {
+	var deleted bool
+	if t.root, deleted = t.delete(t.root, key); deleted {
+		t.size--
+	}
+
+	return deleted
+}
+
+/*
+Descend calls yield for every key and value in descending order of keys, stopping early if yield returns false.
+*/
+func (t *Tree[K, V]) Descend(yield func(K, V) bool) {
+	t.descend(t.root, yield)
+}
+
+/*
+Get returns the value of key, and whether it is present.
+*/
+func (t *Tree[K, V]) Get(key K) (value V, ok bool) {
+	for n := t.root; n != nil; {
+		switch c := t.compare(key, n.key); {
+		case c < 0:
+			n = n.left
+		case c > 0:
+			n = n.right
+		default:
+			return n.value, true
+		}
+	}
+
+	return value, false
+}
+
+/*
+Height returns the number of nodes on the longest path from the root, which for an AVL tree is at most about 1.44 log2(n+2).
+*/
+func (t *Tree[K, V]) Height() int {
+	return height(t.root)
+}
+
+/*
+Max returns the largest key and its value, and false if the Tree is empty.
+*/
+func (t *Tree[K, V]) Max() (key K, value V, ok bool) {
+	if t.root == nil {
+		return key, value, false
+	}
+
+	n := t.root
+	for n.right != nil {
+		n = n.right
+	}
+
+	return n.key, n.value, true
+}
+
+/*
+Min returns the smallest key and its value, and false if the Tree is empty.
+*/
+func (t *Tree[K, V]) Min() (key K, value V, ok bool) {
+	if t.root == nil {
+		return key, value, false
+	}
+
+	n := t.root
+	for n.left != nil {
+		n = n.left
+	}
+
+	return n.key, n.value, true
+}
+
+/*
+Nearest returns the closest key below key, or above it if below is false, along with its value.
+If inclusive is true and key is present, key itself is returned.
+The result is false if there is no such key.
+*/
+func (t *Tree[K, V]) Nearest(key K, inclusive, below bool) (nearest K, value V, ok bool) {
+	for n := t.root; n != nil; {
+		switch c := t.compare(n.key, key); {
+		case c == 0 && inclusive:
+			return n.key, n.value, true
+		case below && c < 0, !below && c > 0:
+			nearest, value, ok = n.key, n.value, true
+			if below {
+				n = n.right
+			} else {
+				n = n.left
+			}
+		case below:
+			n = n.left
+		default:
+			n = n.right
+		}
+	}
+
+	return nearest, value, ok
+}
+
+/*
+Put sets the value of key, adding key if it is not present, and reports whether it was added.
+An existing key is kept as it is, and only its value is replaced.
+*/
+func (t *Tree[K, V]) Put(key K, value V) bool {
+	var inserted bool
+	if t.root, inserted = t.insert(t.root, key, value); inserted {
+		t.size++
+	}
+
+	return inserted
+}
+
+/*
+Size returns the number of keys in the Tree.
+*/
+func (t *Tree[K, V]) Size() int {
+	return t.size
+}
@@ -0,0 +1,136 @@
+// ©2022 Brandon Moller
+
+package avl_test
+
+import (
+	"cmp"
+	"math"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/bmoller/collections/internal/avl"
+)
+
+// checkHeight fails the test if tree is taller than an AVL tree of its size can be.
+func checkHeight(t *testing.T, tree *avl.Tree[int, int]) {
+	t.Helper()
+
+	if limit := int(1.45*math.Log2(float64(tree.Size()+2))) + 1; tree.Height() > limit {
+		t.Fatalf("expected height of at most %d for %d keys but got %d", limit, tree.Size(), tree.Height())
+	}
+}
+
+// checkKeys fails the test unless tree holds exactly the keys of expected, which must be sorted, each with its negation as its value.
+func checkKeys(t *testing.T, tree *avl.Tree[int, int], expected []int) {
+	t.Helper()
+
+	if tree.Size() != len(expected) {
+		t.Fatalf("expected size %d but got %d", len(expected), tree.Size())
+	}
+	var keys []int
+	tree.Ascend(func(key, value int) bool {
+		if value != -key {
+			t.Fatalf("expected value %d for key %d but got %d", -key, key, value)
+		}
+		keys = append(keys, key)
+		return true
+	})
+	if !slices.Equal(keys, expected) {
+		t.Fatalf("expected keys %v from Ascend but got %v", expected, keys)
+	}
+	keys = nil
+	tree.Descend(func(key, _ int) bool {
+		keys = append(keys, key)
+		return true
+	})
+	slices.Reverse(keys)
+	if !slices.Equal(keys, expected) {
+		t.Fatalf("expected reversed keys %v from Descend but got %v", expected, keys)
+	}
+}
+
+func TestTreeBalance(t *testing.T) {
+	tree := avl.New[int, int](cmp.Compare[int])
+	for i := 0; i < 1000; i++ {
+		if !tree.Put(i, -i) {
+			t.Fatalf("expected Put to add key %d", i)
+		}
+		checkHeight(t, tree)
+	}
+	for i := 0; i < 1000; i += 2 {
+		if !tree.Delete(i) {
+			t.Fatalf("expected Delete to remove key %d", i)
+		}
+		checkHeight(t, tree)
+	}
+	for i := 999; i > 0; i -= 2 {
+		tree.Delete(i)
+		checkHeight(t, tree)
+	}
+	checkKeys(t, tree, nil)
+}
+
+func TestTreeOperations(t *testing.T) {
+	tree := avl.New[int, int](cmp.Compare[int])
+	var expected []int
+	for range 5000 {
+		key := rand.Intn(500)
+		i, found := slices.BinarySearch(expected, key)
+		if rand.Intn(3) == 0 {
+			if tree.Delete(key) != found {
+				t.Fatalf("expected Delete of %d to report %t", key, found)
+			} else if found {
+				expected = slices.Delete(expected, i, i+1)
+			}
+		} else {
+			if tree.Put(key, -key) == found {
+				t.Fatalf("expected Put of %d to report %t", key, !found)
+			} else if !found {
+				expected = slices.Insert(expected, i, key)
+			}
+		}
+	}
+	checkKeys(t, tree, expected)
+	checkHeight(t, tree)
+
+	for key := -1; key < 501; key++ {
+		i, found := slices.BinarySearch(expected, key)
+		if value, ok := tree.Get(key); ok != found || (found && value != -key) {
+			t.Fatalf("unexpected result %d, %t from Get for %d", value, ok, key)
+		}
+		if nearest, _, ok := tree.Nearest(key, true, false); ok != (i < len(expected)) || (ok && nearest != expected[i]) {
+			t.Fatalf("unexpected result %d, %t from inclusive Nearest above %d", nearest, ok, key)
+		}
+		below := i - 1
+		if found {
+			below = i
+		}
+		if nearest, _, ok := tree.Nearest(key, true, true); ok != (below >= 0) || (ok && nearest != expected[below]) {
+			t.Fatalf("unexpected result %d, %t from inclusive Nearest below %d", nearest, ok, key)
+		}
+	}
+
+	var inRange []int
+	tree.AscendRange(100, 200, func(key, _ int) bool {
+		inRange = append(inRange, key)
+		return true
+	})
+	from, _ := slices.BinarySearch(expected, 100)
+	to, _ := slices.BinarySearch(expected, 200)
+	if !slices.Equal(inRange, expected[from:to]) {
+		t.Fatalf("expected %v from AscendRange but got %v", expected[from:to], inRange)
+	}
+
+	if key, _, ok := tree.Min(); !ok || key != expected[0] {
+		t.Fatalf("expected minimum %d but got %d", expected[0], key)
+	}
+	if key, _, ok := tree.Max(); !ok || key != expected[len(expected)-1] {
+		t.Fatalf("expected maximum %d but got %d", expected[len(expected)-1], key)
+	}
+	tree.Clear()
+	if _, _, ok := tree.Min(); ok {
+		t.Fatal("expected no minimum after Clear")
+	}
+	checkKeys(t, tree, nil)
+}
@@ -0,0 +1,178 @@
+// ©2022 Brandon Moller
+
+/*
+Package wire implements the binary format shared by the collections that support encoding.BinaryMarshaler, gob and streaming.
+
+Every encoding starts with a header: the three bytes "COL", a Kind byte and a format version byte.
+The header is followed by the number of elements as an unsigned varint, and then the elements themselves as a gob stream, one value per element.
+Each element is preceded by the length in bytes of its part of the gob stream as an unsigned varint, so a reader can stop exactly at the end of the encoding.
+Collections of the same Kind share a format, so a List encoded by one implementation can be decoded by another.
+*/
+package wire
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+
+	"github.com/bmoller/collections"
+)
+
+/*
+A Kind identifies the interface a collection was encoded from.
+*/
+type Kind byte
+
+const (
+	List Kind = iota + 1
+	Queue
+	Set
+	Stack
+)
+
+// Version is the format version written by this package; older versions are still read.
+const Version byte = 1
+
+// maxHint limits the size passed to start, so that a corrupt header cannot cause a huge allocation.
+const maxHint = 1 << 16
+
+var magic = [3]byte{'C', 'O', 'L'}
+
+// byteReader reads single bytes from r without buffering, so nothing after the byte asked for is consumed.
+type byteReader struct {
+	r io.Reader
+}
+
+func (b byteReader) ReadByte() (byte, error) {
+	var p [1]byte
+	_, err := io.ReadFull(b.r, p[:])
+
+	return p[0], err
+}
+
+type countingReader struct {
+	n int64
+	r io.Reader
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+
+	return n, err
+}
+
+type countingWriter struct {
+	n int64
+	w io.Writer
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += int64(n)
+
+	return n, err
+}
+
+/*
+Marshal returns the encoding of size elements produced by elements.
+*/
+func Marshal[T any](kind Kind, size int, elements iter.Seq[T]) ([]byte, error) {
+	var buffer bytes.Buffer
+	if _, err := Write(&buffer, kind, size, elements); err != nil {
+		return nil, err
+	}
+
+	return buffer.Bytes(), nil
+}
+
+/*
+Read decodes a collection of the given kind from r.
+Once the header has been checked, start is called with a hint for the number of elements and then add is called with each element in turn.
+Read consumes no bytes from r beyond the end of the encoding, and returns the number of bytes it consumed.
+*/
+func Read[T any](r io.Reader, kind Kind, start func(size int), add func(T)) (int64, error) {
+	counter := &countingReader{r: r}
+
+	var header [5]byte
+	if _, err := io.ReadFull(counter, header[:]); err != nil {
+		return counter.n, fmt.Errorf("%w: %w", collections.ErrInvalidEncoding, err)
+	}
+	if [3]byte(header[:3]) != magic || Kind(header[3]) != kind {
+		return counter.n, collections.ErrInvalidEncoding
+	}
+	if header[4] == 0 || header[4] > Version {
+		return counter.n, fmt.Errorf("%w: %d", collections.ErrUnsupportedVersion, header[4])
+	}
+	size, err := binary.ReadUvarint(byteReader{counter})
+	if err != nil {
+		return counter.n, fmt.Errorf("%w: %w", collections.ErrInvalidEncoding, err)
+	}
+
+	start(int(min(size, maxHint)))
+	// a bytes.Buffer is an io.ByteReader, so the decoder reads from it directly instead of buffering r
+	var buffer bytes.Buffer
+	decoder := gob.NewDecoder(&buffer)
+	for ; size > 0; size-- {
+		length, err := binary.ReadUvarint(byteReader{counter})
+		if err == nil {
+			_, err = io.CopyN(&buffer, counter, int64(length))
+		}
+		var element T
+		if err == nil {
+			err = decoder.Decode(&element)
+		}
+		if err == nil && buffer.Len() > 0 {
+			err = errors.New("element length does not match its value")
+		}
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				err = io.ErrUnexpectedEOF
+			}
+			return counter.n, fmt.Errorf("%w: %w", collections.ErrInvalidEncoding, err)
+		}
+		add(element)
+	}
+
+	return counter.n, nil
+}
+
+/*
+Unmarshal decodes a collection of the given kind from data, calling start and add as Read does.
+*/
+func Unmarshal[T any](data []byte, kind Kind, start func(size int), add func(T)) error {
+	_, err := Read(bytes.NewReader(data), kind, start, add)
+
+	return err
+}
+
+/*
+Write encodes size elements produced by elements to w and returns the number of bytes written.
+Elements are passed to w as they are encoded, so the whole encoding is never held in memory.
+*/
+func Write[T any](w io.Writer, kind Kind, size int, elements iter.Seq[T]) (int64, error) {
+	counter := &countingWriter{w: w}
+	writer := bufio.NewWriter(counter)
+
+	// a failed write is reported by a later Write or by Flush
+	writer.Write(binary.AppendUvarint([]byte{magic[0], magic[1], magic[2], byte(kind), Version}, uint64(size)))
+	var buffer bytes.Buffer
+	encoder := gob.NewEncoder(&buffer)
+	for element := range elements {
+		if err := encoder.Encode(element); err != nil {
+			return counter.n, err
+		}
+		writer.Write(binary.AppendUvarint(nil, uint64(buffer.Len())))
+		if _, err := buffer.WriteTo(writer); err != nil {
+			return counter.n, err
+		}
+	}
+	err := writer.Flush()
+
+	return counter.n, err
+}
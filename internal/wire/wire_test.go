@@ -0,0 +1,111 @@
+// ©2022 Brandon Moller
+
+package wire_test
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"slices"
+	"testing"
+
+	"github.com/bmoller/collections"
+	"github.com/bmoller/collections/internal/wire"
+)
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, io.ErrClosedPipe
+}
+
+func TestRead(t *testing.T) {
+	data, err := wire.Marshal(wire.List, 1000, slices.Values(make([]int, 1000)))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	var (
+		hint  int
+		count int
+	)
+	n, err := wire.Read(bytes.NewReader(data), wire.List, func(h int) { hint = h }, func(int) { count++ })
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if n != int64(len(data)) {
+		t.Fatalf("expected %d bytes to be read but got %d", len(data), n)
+	} else if hint != 1000 || count != 1000 {
+		t.Fatalf("expected hint and count of %d but got %d and %d", 1000, hint, count)
+	}
+
+	// whatever follows the encoding is left for the next reader
+	reader := bytes.NewReader(append(slices.Clone(data), "trailing"...))
+	if n, err := wire.Read(reader, wire.List, func(int) {}, func(int) {}); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if n != int64(len(data)) {
+		t.Fatalf("expected %d bytes to be read but got %d", len(data), n)
+	} else if rest, _ := io.ReadAll(reader); string(rest) != "trailing" {
+		t.Fatalf("expected trailing data to be unread but got %q", rest)
+	}
+}
+
+func TestReadInvalid(t *testing.T) {
+	data, err := wire.Marshal(wire.Queue, 3, slices.Values([]string{"a", "b", "c"}))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+
+	wrongVersion := slices.Clone(data)
+	wrongVersion[4] = wire.Version + 1
+	zeroVersion := slices.Clone(data)
+	zeroVersion[4] = 0
+	wrongMagic := slices.Clone(data)
+	wrongMagic[0] = 'X'
+	for _, test := range []struct {
+		name     string
+		data     []byte
+		kind     wire.Kind
+		expected error
+	}{
+		{"empty", nil, wire.Queue, collections.ErrInvalidEncoding},
+		{"wrong magic", wrongMagic, wire.Queue, collections.ErrInvalidEncoding},
+		{"wrong kind", data, wire.Stack, collections.ErrInvalidEncoding},
+		{"newer version", wrongVersion, wire.Queue, collections.ErrUnsupportedVersion},
+		{"zero version", zeroVersion, wire.Queue, collections.ErrUnsupportedVersion},
+		{"missing size", data[:5], wire.Queue, collections.ErrInvalidEncoding},
+		{"missing elements", data[:6], wire.Queue, collections.ErrInvalidEncoding},
+		{"truncated element", data[:len(data)-1], wire.Queue, collections.ErrInvalidEncoding},
+	} {
+		err := wire.Unmarshal(test.data, test.kind, func(int) {}, func(string) {})
+		if err == nil || !errors.Is(err, test.expected) {
+			t.Fatalf("%s: expected %v but got: %v", test.name, test.expected, err)
+		}
+	}
+
+	// elements of the wrong type
+	if err := wire.Unmarshal(data, wire.Queue, func(int) {}, func(int) {}); err == nil || !errors.Is(err, collections.ErrInvalidEncoding) {
+		t.Fatalf("expected ErrInvalidEncoding for mismatched element type but got: %v", err)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	var buffer bytes.Buffer
+	n, err := wire.Write(&buffer, wire.Set, 3, slices.Values([]int{1, 2, 3}))
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if n != int64(buffer.Len()) {
+		t.Fatalf("expected %d bytes to be written but got %d", buffer.Len(), n)
+	} else if !bytes.HasPrefix(buffer.Bytes(), []byte{'C', 'O', 'L', byte(wire.Set), wire.Version, 3}) {
+		t.Fatalf("unexpected header % x", buffer.Bytes()[:6])
+	}
+
+	if _, err := wire.Write(failingWriter{}, wire.Set, 0, slices.Values([]int{})); err == nil || !errors.Is(err, io.ErrClosedPipe) {
+		t.Fatalf("expected error from failing writer but got: %v", err)
+	}
+	if _, err := wire.Marshal(wire.Set, 1, slices.Values([]func(){nil})); err == nil {
+		t.Fatal("expected error from encoding a value gob does not support")
+	}
+	if _, err := wire.Write(failingWriter{}, wire.Set, 10000, slices.Values(make([]int, 10000))); err == nil || !errors.Is(err, io.ErrClosedPipe) {
+		t.Fatalf("expected error from failing writer but got: %v", err)
+	}
+}
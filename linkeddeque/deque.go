@@ -0,0 +1,246 @@
+// ©2022 Brandon Moller
+
+/*
+Package linkeddeque provides an implementation of [collections.Deque] backed by individual node instances.
+Each element added to the deque is stored in a node, with pointers to the next and previous nodes.
+The deque maintains references to the nodes at the front and back for fast operations at either end.
+
+Deques, and the Stacks returned by AsStack, implement [collections.RemovingIterable], so elements can be removed from anywhere in the deque while iterating over it.
+*/
+package linkeddeque
+
+import (
+	"iter"
+
+	"github.com/bmoller/collections"
+)
+
+type dequeNode[T comparable] struct {
+	next     *dequeNode[T]
+	previous *dequeNode[T]
+	value    T
+}
+
+type iterator[T comparable] struct {
+	deque         *deque[T]
+	last          *dequeNode[T]
+	modifications int
+	next          *dequeNode[T]
+}
+
+func (i *iterator[T]) Next() (element T, err error) {
+	if i.deque.modifications != i.modifications {
+		return element, collections.ErrConcurrentModification
+	} else if i.next == nil {
+		return element, collections.ErrNoMoreItems
+	}
+	i.last, i.next = i.next, i.next.next
+
+	return i.last.value, nil
+}
+
+func (i *iterator[T]) Remove() error {
+	d := i.deque
+	if d.modifications != i.modifications {
+		return collections.ErrConcurrentModification
+	} else if i.last == nil {
+		return collections.ErrNothingToRemove
+	}
+	if i.last.previous == nil {
+		d.front = i.last.next
+	} else {
+		i.last.previous.next = i.last.next
+	}
+	if i.last.next == nil {
+		d.back = i.last.previous
+	} else {
+		i.last.next.previous = i.last.previous
+	}
+	d.modifications++
+	d.size--
+	i.last, i.modifications = nil, d.modifications
+
+	return nil
+}
+
+type deque[T comparable] struct {
+	back          *dequeNode[T]
+	front         *dequeNode[T]
+	modifications int
+	size          int
+}
+
+func New[T comparable]() collections.Deque[T] {
+	return new(deque[T])
+}
+
+func (d *deque[T]) All() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		i := 0
+		for node := d.front; node != nil; node = node.next {
+			if !yield(i, node.value) {
+				return
+			}
+			i++
+		}
+	}
+}
+
+func (d *deque[T]) AsStack() collections.Stack[T] {
+	return stack[T]{d}
+}
+
+func (d *deque[T]) Backward() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		i := d.size - 1
+		for node := d.back; node != nil; node = node.previous {
+			if !yield(i, node.value) {
+				return
+			}
+			i--
+		}
+	}
+}
+
+func (d *deque[T]) Empty() bool {
+	return d.size == 0
+}
+
+func (d *deque[T]) Iterator() collections.Iterator[T] {
+	return d.RemovingIterator().Next
+}
+
+func (d *deque[T]) Peek() (T, error) {
+	return d.PeekFront()
+}
+
+func (d *deque[T]) PeekBack() (element T, err error) {
+	if d.size == 0 {
+		return element, collections.ErrEmptyDeque
+	}
+
+	return d.back.value, nil
+}
+
+func (d *deque[T]) PeekFront() (element T, err error) {
+	if d.size == 0 {
+		return element, collections.ErrEmptyDeque
+	}
+
+	return d.front.value, nil
+}
+
+func (d *deque[T]) Pop() (T, error) {
+	return d.PopFront()
+}
+
+func (d *deque[T]) PopBack() (element T, err error) {
+	if d.size == 0 {
+		return element, collections.ErrEmptyDeque
+	}
+
+	element = d.back.value
+	d.back = d.back.previous
+	if d.back == nil {
+		d.front = nil
+	} else {
+		d.back.next = nil
+	}
+	d.modifications++
+	d.size--
+
+	return element, nil
+}
+
+func (d *deque[T]) PopFront() (element T, err error) {
+	if d.size == 0 {
+		return element, collections.ErrEmptyDeque
+	}
+
+	element = d.front.value
+	d.front = d.front.next
+	if d.front == nil {
+		d.back = nil
+	} else {
+		d.front.previous = nil
+	}
+	d.modifications++
+	d.size--
+
+	return element, nil
+}
+
+func (d *deque[T]) Push(item T) {
+	d.PushBack(item)
+}
+
+func (d *deque[T]) PushBack(item T) {
+	node := &dequeNode[T]{
+		previous: d.back,
+		value:    item,
+	}
+
+	if d.back == nil {
+		d.front = node
+	} else {
+		d.back.next = node
+	}
+	d.back = node
+	d.modifications++
+	d.size++
+}
+
+func (d *deque[T]) PushFront(item T) {
+	node := &dequeNode[T]{
+		next:  d.front,
+		value: item,
+	}
+
+	if d.front == nil {
+		d.back = node
+	} else {
+		d.front.previous = node
+	}
+	d.front = node
+	d.modifications++
+	d.size++
+}
+
+func (d *deque[T]) RemovingIterator() collections.RemovingIterator[T] {
+	return &iterator[T]{
+		deque:         d,
+		modifications: d.modifications,
+		next:          d.front,
+	}
+}
+
+func (d *deque[T]) Size() int {
+	return d.size
+}
+
+func (d *deque[T]) Values() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for node := d.front; node != nil; node = node.next {
+			if !yield(node.value) {
+				return
+			}
+		}
+	}
+}
+
+// stack presents the front of a deque as the top of a Stack.
+type stack[T comparable] struct {
+	*deque[T]
+}
+
+func (s stack[T]) Peek() (T, error) {
+	return s.PeekFront()
+}
+
+func (s stack[T]) Pop() (T, error) {
+	return s.PopFront()
+}
+
+func (s stack[T]) Push(item T) {
+	s.PushFront(item)
+}
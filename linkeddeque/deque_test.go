@@ -0,0 +1,256 @@
+// ©2022 Brandon Moller
+
+package linkeddeque_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bmoller/collections"
+	"github.com/bmoller/collections/collectionstest"
+	"github.com/bmoller/collections/linkeddeque"
+)
+
+func FuzzDeque(f *testing.F) {
+	collectionstest.FuzzDeque(f, linkeddeque.New[int])
+}
+
+func TestDeque(t *testing.T) {
+	t.Run("Queue", func(t *testing.T) {
+		collectionstest.TestQueue(t, func() collections.Queue[int] { return linkeddeque.New[int]() })
+	})
+	t.Run("Stack", func(t *testing.T) {
+		collectionstest.TestStack(t, func() collections.Stack[int] { return linkeddeque.New[int]().AsStack() })
+	})
+}
+
+func TestDequeAsStack(t *testing.T) {
+	deque := linkeddeque.New[int]()
+	stack := deque.AsStack()
+	if _, err := stack.Pop(); err == nil || !errors.Is(err, collections.ErrEmptyStack) {
+		t.Fatalf("expected ErrEmptyStack from Pop on empty Stack but got: %v", err)
+	}
+
+	for i := 0; i < 1000; i++ {
+		stack.Push(i)
+	}
+	if deque.Size() != 1000 {
+		t.Fatalf("expected deque size %d but got %d", 1000, deque.Size())
+	}
+	if element, err := deque.PeekFront(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if element != 999 {
+		t.Fatalf("expected front element %d but got %d", 999, element)
+	}
+	for element := range stack.Values() {
+		if top, _ := stack.Peek(); element != top {
+			t.Fatalf("expected element with value %d but got %d", top, element)
+		}
+		break
+	}
+	for i := 999; i > -1; i-- {
+		if element, err := stack.Pop(); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if element != i {
+			t.Fatalf("expected element with value %d but got %d", i, element)
+		}
+	}
+	if !deque.Empty() {
+		t.Fatal("expected deque to be empty after popping all elements from Stack")
+	}
+}
+
+func TestDequeBackward(t *testing.T) {
+	deque := linkeddeque.New[int]()
+	for i := 0; i < 1000; i++ {
+		deque.PushBack(i)
+	}
+
+	expected := 999
+	for i, element := range deque.Backward() {
+		if i != expected {
+			t.Fatalf("expected index %d but got %d", expected, i)
+		} else if element != i {
+			t.Fatalf("expected element with value %d but got %d", i, element)
+		}
+		expected--
+	}
+	if expected != -1 {
+		t.Fatalf("expected %d elements from Backward but got %d", 1000, 999-expected)
+	}
+
+	for i := range deque.Backward() {
+		if i == 990 {
+			break
+		}
+	}
+	for range deque.Backward() {
+		deque.PopBack()
+		deque.PopBack()
+	}
+	if !deque.Empty() {
+		t.Fatal("expected deque to be empty after popping during Backward")
+	}
+}
+
+func TestDequeIterator(t *testing.T) {
+	deque := linkeddeque.New[int]()
+	for i := 500; i < 1000; i++ {
+		deque.PushBack(i)
+	}
+	for i := 499; i > -1; i-- {
+		deque.PushFront(i)
+	}
+
+	itr := deque.Iterator()
+	for i := 0; i < 1000; i++ {
+		if element, err := itr(); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if element != i {
+			t.Fatalf("expected element with value %d but got %d", i, element)
+		}
+	}
+	if _, err := itr(); err == nil || !errors.Is(err, collections.ErrNoMoreItems) {
+		t.Fatalf("exhausted iterator should return ErrNoMoreItems but got %v", err)
+	}
+
+	expected := 0
+	for i, element := range deque.All() {
+		if i != expected || element != expected {
+			t.Fatalf("expected index and element %d but got %d and %d", expected, i, element)
+		}
+		expected++
+	}
+	expected = 0
+	for element := range deque.Values() {
+		if element != expected {
+			t.Fatalf("expected element with value %d but got %d", expected, element)
+		}
+		expected++
+	}
+
+	for i := range deque.All() {
+		if i == 10 {
+			break
+		}
+	}
+	for element := range deque.Values() {
+		if element == 10 {
+			break
+		}
+	}
+
+	itr = deque.Iterator()
+	deque.PeekBack()
+	deque.PeekFront()
+	if element, err := itr(); err != nil {
+		t.Fatalf("unexpected error after Peek: %s", err)
+	} else if element != 0 {
+		t.Fatalf("expected element with value %d but got %d", 0, element)
+	}
+	for method, modify := range map[string]func(){
+		"PopBack":   func() { deque.PopBack() },
+		"PopFront":  func() { deque.PopFront() },
+		"PushBack":  func() { deque.PushBack(0) },
+		"PushFront": func() { deque.PushFront(0) },
+	} {
+		itr = deque.Iterator()
+		modify()
+		if _, err := itr(); err == nil || !errors.Is(err, collections.ErrConcurrentModification) {
+			t.Fatalf("expected ErrConcurrentModification from Iterator after %s but got: %v", method, err)
+		}
+	}
+}
+
+func TestDequePeek(t *testing.T) {
+	deque := linkeddeque.New[int]()
+	if _, err := deque.PeekFront(); err == nil || !errors.Is(err, collections.ErrEmptyDeque) {
+		t.Fatalf("expected ErrEmptyDeque from PeekFront on new deque but got: %v", err)
+	}
+	if _, err := deque.PeekBack(); err == nil || !errors.Is(err, collections.ErrEmptyDeque) {
+		t.Fatalf("expected ErrEmptyDeque from PeekBack on new deque but got: %v", err)
+	}
+	if _, err := deque.Peek(); err == nil || !errors.Is(err, collections.ErrEmptyQueue) {
+		t.Fatalf("expected ErrEmptyQueue from Peek on new deque but got: %v", err)
+	}
+
+	deque.PushBack(1)
+	deque.PushFront(0)
+	deque.PushBack(2)
+	if element, err := deque.PeekFront(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if element != 0 {
+		t.Fatalf("expected element with value %d from PeekFront but got %d", 0, element)
+	}
+	if element, err := deque.Peek(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if element != 0 {
+		t.Fatalf("expected element with value %d from Peek but got %d", 0, element)
+	}
+	if element, err := deque.PeekBack(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if element != 2 {
+		t.Fatalf("expected element with value %d from PeekBack but got %d", 2, element)
+	}
+	if deque.Size() != 3 {
+		t.Fatalf("expected deque size %d but got %d", 3, deque.Size())
+	}
+}
+
+func TestDequePopBack(t *testing.T) {
+	deque := linkeddeque.New[int]()
+	for i := 0; i < 1000; i++ {
+		deque.PushFront(i)
+	}
+	for i := 0; i < 1000; i++ {
+		if element, err := deque.PopBack(); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if element != i {
+			t.Fatalf("expected element with value %d but got %d", i, element)
+		}
+	}
+	if _, err := deque.PopBack(); err == nil || !errors.Is(err, collections.ErrEmptyDeque) {
+		t.Fatalf("expected ErrEmptyDeque from PopBack on empty deque but got: %v", err)
+	}
+}
+
+func TestDequePopFront(t *testing.T) {
+	deque := linkeddeque.New[int]()
+	for i := 0; i < 1000; i++ {
+		deque.PushBack(i)
+	}
+	for i := 0; i < 1000; i++ {
+		if element, err := deque.PopFront(); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if element != i {
+			t.Fatalf("expected element with value %d but got %d", i, element)
+		}
+	}
+	if _, err := deque.PopFront(); err == nil || !errors.Is(err, collections.ErrEmptyDeque) {
+		t.Fatalf("expected ErrEmptyDeque from PopFront on empty deque but got: %v", err)
+	}
+}
+
+func TestDequeSize(t *testing.T) {
+	deque := linkeddeque.New[int]()
+	for i := 1; i < 1001; i++ {
+		if i%2 == 0 {
+			deque.PushFront(i)
+		} else {
+			deque.PushBack(i)
+		}
+		if deque.Size() != i {
+			t.Fatalf("expected deque size %d but got %d", i, deque.Size())
+		}
+	}
+	for i := 999; i > -1; i-- {
+		if i%2 == 0 {
+			deque.PopFront()
+		} else {
+			deque.PopBack()
+		}
+		if deque.Size() != i {
+			t.Fatalf("expected deque size %d but got %d", i, deque.Size())
+		}
+	}
+}
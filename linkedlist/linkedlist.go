@@ -4,10 +4,32 @@
 Package linkedlist is an implementation of [collections.LinkedList] backed by individual list nodes.
 The list is doubly-linked and can be traversed in either direction from any node in the list.
 For methods with nodes as their parameters, lists verify that the nodes are members of the receiving list.
+A node removed by Remove, RemoveNode or Clear is no longer a member, and is rejected if it is passed back to the list.
+Replacing the value of a member node with SetValue modifies its list, so it invalidates the list's Iterators as Set does.
+
+Sort and SortStable both use a merge sort that relinks the existing nodes rather than moving values between them.
+The sort is always stable, and references to nodes remain valid and hold the same values afterwards.
+
+The JSON encoding of a list is an array of its elements from head to tail.
+Decoding discards the existing nodes and creates new ones, so references to the old nodes are no longer members of the list.
+The same is true of the binary and gob encodings and of ReadFrom, which share a format with slicelist.
+
+Lists implement [collections.RemovingIterable], and removing an element through a RemovingIterator invalidates its node as RemoveNode does.
+
+Lists implement [collections.Validator].
+Validate follows the nodes from the head, checking that each is a member of the list and links back to the node before it, that the last is the tail, and that their number matches the size.
 */
 package linkedlist
 
-import "github.com/bmoller/collections"
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+
+	"github.com/bmoller/collections"
+	"github.com/bmoller/collections/internal/wire"
+)
 
 type listNode[T comparable] struct {
 	elementOf *linkedList[T]
@@ -24,20 +46,114 @@ func (n *listNode[T]) Previous() collections.ListNode[T] {
 	return n.previous
 }
 
+func (n *listNode[T]) SetValue(value T) {
+	if n.elementOf != nil {
+		n.elementOf.modifications++
+	}
+	n.value = value
+}
+
 func (n *listNode[T]) Value() T {
 	return n.value
 }
 
+type iterator[T comparable] struct {
+	last          *listNode[T]
+	list          *linkedList[T]
+	modifications int
+	next          *listNode[T]
+}
+
+func (i *iterator[T]) Next() (element T, err error) {
+	if i.list.modifications != i.modifications {
+		return element, collections.ErrConcurrentModification
+	} else if i.next == nil {
+		return element, collections.ErrNoMoreItems
+	}
+	i.last, i.next = i.next, i.next.next
+
+	return i.last.value, nil
+}
+
+func (i *iterator[T]) Remove() error {
+	if i.list.modifications != i.modifications {
+		return collections.ErrConcurrentModification
+	} else if i.last == nil {
+		return collections.ErrNothingToRemove
+	}
+	i.list.unlink(i.last)
+	i.last, i.modifications = nil, i.list.modifications
+
+	return nil
+}
+
+// mergeSort sorts the nodes reachable by next from head, returning the new first node.
+// Only next pointers are maintained; callers must repair previous pointers afterwards.
+func mergeSort[T comparable](head *listNode[T], less func(a, b T) bool) *listNode[T] {
+	if head == nil || head.next == nil {
+		return head
+	}
+
+	middle, fast := head, head.next
+	for fast != nil && fast.next != nil {
+		middle = middle.next
+		fast = fast.next.next
+	}
+	second := middle.next
+	middle.next = nil
+
+	a, b := mergeSort(head, less), mergeSort(second, less)
+	merged := new(listNode[T])
+	tail := merged
+	for a != nil && b != nil {
+		if less(b.value, a.value) {
+			tail.next, b = b, b.next
+		} else {
+			tail.next, a = a, a.next
+		}
+		tail = tail.next
+	}
+	if a != nil {
+		tail.next = a
+	} else {
+		tail.next = b
+	}
+
+	return merged.next
+}
+
 type linkedList[T comparable] struct {
-	head *listNode[T]
-	size int
-	tail *listNode[T]
+	head          *listNode[T]
+	modifications int
+	size          int
+	tail          *listNode[T]
 }
 
 func New[T comparable]() collections.LinkedList[T] {
 	return new(linkedList[T])
 }
 
+func (l *linkedList[T]) reset(int) {
+	l.Clear()
+}
+
+// unlink removes node from the list, so that it is no longer accepted as a member.
+func (l *linkedList[T]) unlink(node *listNode[T]) {
+	if node.next != nil {
+		node.next.previous = node.previous
+	} else {
+		l.tail = node.previous
+	}
+	if node.previous != nil {
+		node.previous.next = node.next
+	} else {
+		l.head = node.next
+	}
+	node.elementOf, node.next, node.previous = nil, nil, nil
+	l.modifications++
+	l.size--
+}
+
 func (l *linkedList[T]) Add(item T) {
 	node := &listNode[T]{
 		elementOf: l,
@@ -53,18 +169,58 @@ func (l *linkedList[T]) Add(item T) {
 		node.previous = oldTail
 		l.tail = node
 	}
+	l.modifications++
 	l.size++
 }
 
+func (l *linkedList[T]) All() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		i := 0
+		for node := l.head; node != nil; node = node.next {
+			if !yield(i, node.value) {
+				return
+			}
+			i++
+		}
+	}
+}
+
+func (l *linkedList[T]) Backward() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		i := l.size - 1
+		for node := l.tail; node != nil; node = node.previous {
+			if !yield(i, node.value) {
+				return
+			}
+			i--
+		}
+	}
+}
+
 func (l *linkedList[T]) Clear() {
-	l.head, l.tail = nil, nil
-	l.size = 0
+	for l.head != nil {
+		l.unlink(l.head)
+	}
+}
+
+func (l *linkedList[T]) Contains(item T) bool {
+	return l.IndexOf(item) != -1
 }
 
 func (l *linkedList[T]) Empty() bool {
 	return l.size == 0
 }
 
+func (l *linkedList[T]) FindNode(item T) (collections.ListNode[T], error) {
+	for node := l.head; node != nil; node = node.next {
+		if node.value == item {
+			return node, nil
+		}
+	}
+
+	return nil, collections.ErrNoSuchElement
+}
+
 func (l *linkedList[T]) Get(index int) (element T, err error) {
 	if l.size == 0 {
 		return element, collections.ErrEmptyList
@@ -101,20 +257,47 @@ func (l *linkedList[T]) GetNode(index int) (collections.ListNode[T], error) {
 	return node, nil
 }
 
+func (l *linkedList[T]) GobDecode(data []byte) error {
+	return l.UnmarshalBinary(data)
+}
+
+func (l *linkedList[T]) GobEncode() ([]byte, error) {
+	return l.MarshalBinary()
+}
+
 func (l *linkedList[T]) Head() collections.ListNode[T] {
 	return l.head
 }
 
+func (l *linkedList[T]) IndexFunc(f func(T) bool) int {
+	i := 0
+	for node := l.head; node != nil; node = node.next {
+		if f(node.value) {
+			return i
+		}
+		i++
+	}
+
+	return -1
+}
+
+func (l *linkedList[T]) IndexOf(item T) int {
+	return l.IndexFunc(func(value T) bool {
+		return value == item
+	})
+}
+
 func (l *linkedList[T]) Insert(index int, item T) error {
 	switch {
-	case l.size == 0:
-		return collections.ErrEmptyList
 	case index > l.size, index < 0:
 		err := collections.ErrIndexOutOfRange{
 			Index: index,
 			Size:  l.size,
 		}
 		return err
+	case index == l.size:
+		l.Add(item)
+		return nil
 	case index == 0:
 		node := &listNode[T]{
 			elementOf: l,
@@ -137,6 +320,7 @@ func (l *linkedList[T]) Insert(index int, item T) error {
 		current.next.previous = node
 		current.next = node
 	}
+	l.modifications++
 	l.size++
 
 	return nil
@@ -162,6 +346,7 @@ func (l *linkedList[T]) InsertAfter(node collections.ListNode[T], item T) (colle
 		typedNode.next.previous = newNode
 	}
 	typedNode.next = newNode
+	l.modifications++
 	l.size++
 
 	return newNode, nil
@@ -187,11 +372,45 @@ func (l *linkedList[T]) InsertBefore(node collections.ListNode[T], item T) (coll
 		typedNode.previous.next = newNode
 	}
 	typedNode.previous = newNode
+	l.modifications++
 	l.size++
 
 	return newNode, nil
 }
 
+func (l *linkedList[T]) Iterator() collections.Iterator[T] {
+	return l.RemovingIterator().Next
+}
+
+func (l *linkedList[T]) LastIndexOf(item T) int {
+	i := l.size - 1
+	for node := l.tail; node != nil; node = node.previous {
+		if node.value == item {
+			return i
+		}
+		i--
+	}
+
+	return -1
+}
+
+func (l *linkedList[T]) MarshalBinary() ([]byte, error) {
+	return wire.Marshal(wire.List, l.size, l.Values())
+}
+
+func (l *linkedList[T]) MarshalJSON() ([]byte, error) {
+	elements := make([]T, 0, l.size)
+	for node := l.head; node != nil; node = node.next {
+		elements = append(elements, node.value)
+	}
+
+	return json.Marshal(elements)
+}
+
+func (l *linkedList[T]) ReadFrom(r io.Reader) (int64, error) {
+	return wire.Read(r, wire.List, l.reset, l.Add)
+}
+
 func (l *linkedList[T]) Remove(index int) (element T, err error) {
 	if l.size == 0 {
 		return element, collections.ErrEmptyList
@@ -207,16 +426,7 @@ func (l *linkedList[T]) Remove(index int) (element T, err error) {
 	for i := 0; i < index; i++ {
 		current = current.next
 	}
-	if current.next != nil {
-		current.next.previous = current.previous
-	}
-	if current.previous != nil {
-		current.previous.next = current.next
-	}
-	if l.head == current {
-		l.head = current.next
-	}
-	l.size--
+	l.unlink(current)
 
 	return current.value, nil
 }
@@ -229,28 +439,63 @@ func (l *linkedList[T]) RemoveNode(node collections.ListNode[T]) error {
 		return collections.ErrNodeIsNotElement
 	}
 
-	if typedNode.next != nil {
-		typedNode.next.previous = typedNode.previous
-	}
-	if typedNode.previous != nil {
-		typedNode.previous.next = typedNode.next
+	l.unlink(typedNode)
+
+	return nil
+}
+
+func (l *linkedList[T]) RemovingIterator() collections.RemovingIterator[T] {
+	return &iterator[T]{
+		list:          l,
+		modifications: l.modifications,
+		next:          l.head,
 	}
-	if l.head == typedNode {
-		l.head = typedNode.next
+}
+
+func (l *linkedList[T]) Set(index int, item T) (element T, err error) {
+	if l.size == 0 {
+		return element, collections.ErrEmptyList
+	} else if index >= l.size || index < 0 {
+		return element, collections.ErrIndexOutOfRange{
+			Index: index,
+			Size:  l.size,
+		}
 	}
-	if l.tail == typedNode {
-		l.tail = typedNode.previous
+
+	current := l.head
+	for i := 0; i < index; i++ {
+		current = current.next
 	}
+	element, current.value = current.value, item
+	l.modifications++
 
-	return nil
+	return element, nil
 }
 
 func (l *linkedList[T]) Size() int {
 	return l.size
 }
 
+func (l *linkedList[T]) Sort(less func(a, b T) bool) {
+	l.SortStable(less)
+}
+
+func (l *linkedList[T]) SortStable(less func(a, b T) bool) {
+	l.head = mergeSort(l.head, less)
+	l.modifications++
+
+	var previous *listNode[T]
+	for node := l.head; node != nil; node = node.next {
+		node.previous = previous
+		previous = node
+	}
+	l.tail = previous
+}
+
 func (l *linkedList[T]) SubList(start int, end int) (collections.List[T], error) {
 	switch {
+	case l.size == 0:
+		return nil, collections.ErrEmptyList
 	case start < 0 || end < start:
 		return nil, collections.ErrInvalidRange{
 			End:   end,
@@ -285,3 +530,88 @@ func (l *linkedList[T]) SubList(start int, end int) (collections.List[T], error)
 func (l *linkedList[T]) Tail() collections.ListNode[T] {
 	return l.tail
 }
+
+func (l *linkedList[T]) UnmarshalBinary(data []byte) error {
+	return wire.Unmarshal(data, wire.List, l.reset, l.Add)
+}
+
+func (l *linkedList[T]) UnmarshalJSON(data []byte) error {
+	var elements []T
+	if err := json.Unmarshal(data, &elements); err != nil {
+		return err
+	}
+
+	l.Clear()
+	for _, element := range elements {
+		l.Add(element)
+	}
+
+	return nil
+}
+
+func (l *linkedList[T]) Validate() error {
+	switch {
+	case l.size < 0:
+		return collections.ErrCorrupted{
+			Index:   -1,
+			Problem: fmt.Sprintf("size %d is negative", l.size),
+		}
+	case l.head != nil && l.head.previous != nil:
+		return collections.ErrCorrupted{
+			Index:   0,
+			Problem: "head has a previous node",
+		}
+	}
+
+	var previous *listNode[T]
+	i := 0
+	for node := l.head; node != nil; node = node.next {
+		switch {
+		case i == l.size:
+			return collections.ErrCorrupted{
+				Index:   i,
+				Problem: fmt.Sprintf("more nodes than the size %d", l.size),
+			}
+		case node.elementOf != l:
+			return collections.ErrCorrupted{
+				Index:   i,
+				Problem: "node is not an element of the list",
+			}
+		case node.previous != previous:
+			return collections.ErrCorrupted{
+				Index:   i,
+				Problem: "node does not link back to the node before it",
+			}
+		}
+		previous = node
+		i++
+	}
+
+	if l.tail != previous {
+		return collections.ErrCorrupted{
+			Index:   -1,
+			Problem: "tail is not the last node",
+		}
+	} else if i != l.size {
+		return collections.ErrCorrupted{
+			Index:   -1,
+			Problem: fmt.Sprintf("%d nodes but the size is %d", i, l.size),
+		}
+	}
+
+	return nil
+}
+
+func (l *linkedList[T]) Values() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for node := l.head; node != nil; node = node.next {
+			if !yield(node.value) {
+				return
+			}
+		}
+	}
+}
+
+func (l *linkedList[T]) WriteTo(w io.Writer) (int64, error) {
+	return wire.Write(w, wire.List, l.size, l.Values())
+}
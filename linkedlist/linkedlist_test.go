@@ -3,537 +3,236 @@
 package linkedlist_test
 
 import (
+	"bytes"
+	"encoding"
+	"encoding/gob"
+	"encoding/json"
 	"errors"
+	"io"
 	"math/rand"
 	"os"
 	"reflect"
+	"slices"
 	"testing"
 	"time"
 
 	"github.com/bmoller/collections"
+	"github.com/bmoller/collections/collectionstest"
 	"github.com/bmoller/collections/linkedlist"
+	"github.com/bmoller/collections/slicelist"
 )
 
-type badNode[T comparable] struct {
-	next     *badNode[T]
-	previous *badNode[T]
-	value    T
-}
-
-func (b *badNode[T]) Next() collections.ListNode[T] {
-	return b.next
-}
-
-func (b *badNode[T]) Previous() collections.ListNode[T] {
-	return b.previous
-}
-
-func (b *badNode[T]) Value() T {
-	return b.value
-}
-
 func TestMain(m *testing.M) {
 	rand.Seed(time.Now().UTC().UnixNano())
 	os.Exit(m.Run())
 }
 
-func TestLinkedListBackward(t *testing.T) {
-	list := linkedlist.New[int]()
-	for i := 0; i < 1000; i++ {
-		list.Add(rand.Int())
-	}
-
-	node := list.Tail()
-	for i := 0; i < 999; i++ {
-		node = node.Previous()
-	}
+func FuzzLinkedList(f *testing.F) {
+	collectionstest.FuzzLinkedList(f, linkedlist.New[int])
+}
 
-	switch {
-	case node == nil:
-		t.Fatal("expected a valid node pointer but got nil")
-	case node != list.Head():
-		t.Fatal("final node does not match list head")
-	case !reflect.ValueOf(node.Previous()).IsNil():
-		// we reflect here because nodes work around pointers but signatures reference the interface
-		t.Fatal("expected head node to have nil pointer to previous node")
-	}
+func TestLinkedList(t *testing.T) {
+	collectionstest.TestLinkedList(t, linkedlist.New[int])
 }
 
-func TestLinkedListForward(t *testing.T) {
+func TestLinkedListSortNodes(t *testing.T) {
 	list := linkedlist.New[int]()
-	for i := 0; i < 1000; i++ {
-		list.Add(rand.Int())
+	nodes := make(map[int]collections.ListNode[int])
+	for _, value := range rand.Perm(1000) {
+		list.Add(value)
+		nodes[value] = list.Tail()
 	}
+	list.Sort(func(a, b int) bool { return a < b })
 
 	node := list.Head()
-	for i := 0; i < 999; i++ {
+	for i := 0; i < 1000; i++ {
+		if node != nodes[i] {
+			t.Fatalf("expected original node for value %d at index %d", i, i)
+		} else if node.Value() != i {
+			t.Fatalf("expected node with value %d, got %d", i, node.Value())
+		}
 		node = node.Next()
 	}
 
-	switch {
-	case node == nil:
-		t.Fatal("expected a valid node pointer but got nil")
-	case node != list.Tail():
-		t.Fatal("final node does not match list tail")
-	case !reflect.ValueOf(node.Next()).IsNil():
-		// we reflect here because nodes work around pointers but signatures reference the interface
-		t.Fatal("expected tail node to have nil pointer to next node")
-	}
-}
-
-func TestLinkedListClear(t *testing.T) {
-	list := linkedlist.New[int]()
-	for i := 0; i < 1000; i++ {
-		list.Add(rand.Int())
+	node = list.Tail()
+	for i := 999; i > 0; i-- {
+		if node.Value() != i {
+			t.Fatalf("expected node with value %d, got %d", i, node.Value())
+		}
+		node = node.Previous()
 	}
-	list.Clear()
-
-	switch {
-	case list.Size() != 0:
-		t.Fatalf("expected list size %d but got %d", 0, list.Size())
-	case !reflect.ValueOf(list.Head()).IsNil():
-		t.Fatal("expected list to have nil head pointer")
-	case !reflect.ValueOf(list.Tail()).IsNil():
-		t.Fatal("expected list to have nil tail pointer")
+	if node != list.Head() || !reflect.ValueOf(node.Previous()).IsNil() {
+		t.Fatal("expected previous links to be repaired after Sort")
 	}
-}
 
-func TestLinkedListEmpty(t *testing.T) {
-	list := linkedlist.New[int]()
-	if !list.Empty() {
-		t.Fatal("expected new list to be empty")
+	if _, err := list.InsertAfter(nodes[500], -1); err != nil {
+		t.Fatalf("unexpected error: %s", err)
 	}
-	list.Add(0)
-	if list.Empty() {
-		t.Fatal("expected list to not be empty afterr adding element")
+	if index := list.IndexOf(-1); index != 501 {
+		t.Fatalf("expected inserted element at index %d but got %d", 501, index)
 	}
 }
 
-func TestLinkedListGet(t *testing.T) {
+func TestLinkedListSubList(t *testing.T) {
 	list := linkedlist.New[int]()
-	if _, err := list.Get(0); err == nil || !errors.Is(err, collections.ErrEmptyList) {
-		t.Fatalf("expected ErrEmptyList but got: %s", err)
-	}
-
-	for i := 0; i < 1000; i++ {
+	for i := 0; i < 100; i++ {
 		list.Add(i)
 	}
-	for i := 0; i < 1000; i++ {
-		if element, err := list.Get(i); err != nil {
-			t.Fatalf("got unexpected error: %s", err)
-		} else if element != i {
-			t.Fatalf("expected element to have value %d but got %d", i, element)
-		}
-	}
 
-	errIndex := new(collections.ErrIndexOutOfRange)
-	if _, err := list.Get(9999); err == nil {
-		t.Fatal("expected error from call to Get out of range")
-	} else if !errors.As(err, errIndex) {
-		t.Fatalf("expected ErrIndexOutOfRange, got %T", err)
-	}
-	if _, err := list.Get(-1); err == nil {
-		t.Fatal("expected error from call to Get with index < 0")
-	} else if !errors.As(err, errIndex) {
-		t.Fatalf("expected ErrIndexOutOfRange, got %T", err)
-	}
-}
-
-func TestLinkedListGetNode(t *testing.T) {
-	list := linkedlist.New[int]()
-	if _, err := list.GetNode(0); err == nil {
-		t.Fatal("expected error from call to GetNode on new list")
-	} else if !errors.Is(err, collections.ErrEmptyList) {
-		t.Fatalf("expected ErrEmptyList but got: %s", err)
-	}
-
-	for i := 0; i < 4; i++ {
-		for j := 0; j < 249; j++ {
-			list.Add(rand.Int())
-			list.Add(0)
-		}
+	subList, err := list.SubList(11, 30)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if subList.Size() != 19 {
+		t.Fatalf("expected SubList with size %d, got %d", 19, subList.Size())
 	}
-
-	for _, index := range []int{249, 499, 749, 999} {
-		if node, err := list.GetNode(index); err != nil {
+	for i := 11; i < 30; i++ {
+		if element, err := subList.Get(i - 11); err != nil {
 			t.Fatalf("unexpected error: %s", err)
-		} else if node.Value() != 0 {
-			t.Fatalf("expected node with value %d but got %d", 0, node.Value())
+		} else if element != i {
+			t.Fatalf("expected element with value %d, got %d", i-11, element)
 		}
 	}
 
-	indexErr := new(collections.ErrIndexOutOfRange)
-	if _, err := list.GetNode(9999); err == nil {
-		t.Fatal("expected error from call to Get with index > size")
-	} else if !errors.As(err, indexErr) {
-		t.Fatalf("expected ErrInvalidIndex but got: %s", err)
-	}
-	if _, err := list.GetNode(-1); err == nil {
-		t.Fatal("expected error from call to Get with index < 0")
-	} else if !errors.As(err, indexErr) {
-		t.Fatalf("expected ErrInvalidIndex but got: %s", err)
-	}
-}
-
-func TestLinkedListHead(t *testing.T) {
-	list := linkedlist.New[int]()
-	if node := list.Head(); !reflect.ValueOf(node).IsNil() {
-		t.Fatal("expected head node of new list to be nil")
-	}
-
-	list.Add(0)
-	for i := 0; i < 1000; i++ {
-		list.Add(rand.Int())
-	}
-
-	if value := list.Head().Value(); value != 0 {
-		t.Fatalf("expected head node to have value %d but got %d", 0, value)
+	if _, ok := subList.(collections.LinkedList[int]); !ok {
+		t.Fatal("expected to be able to cast List to LinkedList")
 	}
 }
 
-func TestLinkedListInsert(t *testing.T) {
+func TestLinkedListMarshalJSON(t *testing.T) {
 	list := linkedlist.New[int]()
-	if err := list.Insert(0, 0); err == nil {
-		t.Fatal("expected error from Insert on an empty List")
-	} else if !errors.Is(err, collections.ErrEmptyList) {
-		t.Fatalf("expected ErrEmptyList, got %T", err)
+	if data, err := json.Marshal(list); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if string(data) != "[]" {
+		t.Fatalf("expected empty list to encode as %s but got %s", "[]", data)
 	}
 
-	list.Add(999)
-	for i := 998; i > -1; i-- {
-		if err := list.Insert(0, i); err != nil {
-			t.Fatalf("unexpected error: %s", err)
-		}
-	}
-	node := list.Head()
 	for i := 0; i < 1000; i++ {
-		if node.Value() != i {
-			t.Fatalf("expected node with value %d, got %d", i, node.Value())
-		}
-		node = node.Next()
+		list.Add(i)
 	}
-
-	indexErr := new(collections.ErrIndexOutOfRange)
-	if err := list.Insert(-1, 0); err == nil {
-		t.Fatal("expected error from Insert with index < 0")
-	} else if !errors.As(err, indexErr) {
-		t.Fatalf("expected ErrIndexOutOfRange, got %T", err)
+	data, err := json.Marshal(list)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
 	}
-	if err := list.Insert(9999, 0); err == nil {
-		t.Fatal("expected error from Insert with index > size")
-	} else if !errors.As(err, indexErr) {
-		t.Fatalf("expected ErrIndexOutOfRange, got %T", err)
+	var elements []int
+	if err := json.Unmarshal(data, &elements); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if len(elements) != 1000 {
+		t.Fatalf("expected %d encoded elements but got %d", 1000, len(elements))
 	}
-
-	for i := 1; i < 101; i++ {
-		if err := list.Insert(rand.Intn(1000), rand.Int()); err != nil {
-			t.Fatalf("unexpected error: %s", err)
-		}
-		if list.Size() != 1000+i {
-			t.Fatalf("expected size %d, got %d", 1000+i, list.Size())
+	for i, element := range elements {
+		if element != i {
+			t.Fatalf("expected element with value %d at index %d but got %d", i, i, element)
 		}
 	}
 }
 
-func TestLinkedListInsertAfter(t *testing.T) {
-	l1 := linkedlist.New[int]()
-	for i := 0; i < 100; i++ {
-		l1.Add(i)
-	}
-
-	node := l1.Head()
-	for i := 0; i < 50; i++ {
-		node = node.Next()
-	}
-	if newNode, err := l1.InsertAfter(node, 0); err != nil {
+func TestLinkedListUnmarshalJSON(t *testing.T) {
+	list := linkedlist.New[int]()
+	if err := json.Unmarshal([]byte("[0,1,2]"), list); err != nil {
 		t.Fatalf("unexpected error: %s", err)
-	} else if newNode.Value() != 0 {
-		t.Fatalf("expected element with value %d, got %d", 0, newNode.Value())
-	}
-	if l1.Size() != 101 {
-		t.Fatalf("expected list size %d, got %d", 101, l1.Size())
-	}
-
-	node = l1.Head()
-	for i := 0; i < 51; i++ {
-		if node.Value() != i {
-			t.Fatalf("expected element with value %d, got %d", i, node.Value())
-		}
-		node = node.Next()
 	}
-	if node.Value() != 0 {
-		t.Fatalf("expected element with value %d, got %d", 0, node.Value())
-	}
-	node = node.Next()
-	for i := 51; i < 100; i++ {
-		if node.Value() != i {
-			t.Fatalf("expected element with value %d, got %d", i, node.Value())
+	list.Add(3)
+	for i := 0; i < 4; i++ {
+		if element, err := list.Get(i); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if element != i {
+			t.Fatalf("expected element with value %d but got %d", i, element)
 		}
-		node = node.Next()
-	}
-
-	l2 := linkedlist.New[int]()
-	l2.Add(0)
-	node = l2.Head()
-	if _, err := l1.InsertAfter(node, 0); err == nil {
-		t.Fatal("expected error from InsertAfter with foreign node")
-	} else if !errors.Is(err, collections.ErrNodeIsNotElement) {
-		t.Fatalf("expected ErrNodeIsNotElement, got %T", err)
-	}
-
-	node = l1.Tail()
-	if newNode, err := l1.InsertAfter(node, 0); err != nil {
-		t.Fatalf("unexpected error: %s", err)
-	} else if newNode != l1.Tail() {
-		t.Fatal("expected inserted node to be the new tail")
 	}
 
-	bad := &badNode[int]{
-		value: 0,
+	if err := json.Unmarshal([]byte(`{"a":1}`), list); err == nil {
+		t.Fatal("expected error from decoding an object")
+	} else if list.Size() != 4 {
+		t.Fatalf("expected failed decode to leave size %d but got %d", 4, list.Size())
 	}
-	if _, err := l1.InsertAfter(bad, 0); err == nil {
-		t.Fatal("expected error from InsertAfter with bad node type")
-	} else if !errors.Is(err, collections.ErrWrongNodeType) {
-		t.Fatalf("expected ErrWrongNodeType, got %T", err)
-	}
-}
 
-func TestLinkedListInsertBefore(t *testing.T) {
-	l1 := linkedlist.New[int]()
-	for i := 0; i < 100; i++ {
-		l1.Add(i)
+	payload := struct {
+		Items collections.LinkedList[int]
+	}{
+		Items: linkedlist.New[int](),
 	}
-
-	node := l1.Head()
-	for i := 0; i < 50; i++ {
-		node = node.Next()
-	}
-	if newNode, err := l1.InsertBefore(node, 0); err != nil {
+	payload.Items.Add(99)
+	if err := json.Unmarshal([]byte(`{"Items":[5,6]}`), &payload); err != nil {
 		t.Fatalf("unexpected error: %s", err)
-	} else if newNode.Value() != 0 {
-		t.Fatalf("expected element with value %d, got %d", 0, newNode.Value())
-	}
-	if l1.Size() != 101 {
-		t.Fatalf("expected list size %d, got %d", 101, l1.Size())
+	} else if payload.Items.Size() != 2 {
+		t.Fatalf("expected decoding to replace contents leaving size %d but got %d", 2, payload.Items.Size())
 	}
-
-	node = l1.Head()
-	for i := 0; i < 50; i++ {
-		if node.Value() != i {
-			t.Fatalf("expected element with value %d, got %d", i, node.Value())
-		}
-		node = node.Next()
-	}
-	if node.Value() != 0 {
-		t.Fatalf("expected element with value %d, got %d", 0, node.Value())
-	}
-	node = node.Next()
-	for i := 50; i < 100; i++ {
-		if node.Value() != i {
-			t.Fatalf("expected element with value %d, got %d", i, node.Value())
-		}
-		node = node.Next()
-	}
-
-	l2 := linkedlist.New[int]()
-	l2.Add(0)
-	node = l2.Head()
-	if _, err := l1.InsertBefore(node, 0); err == nil {
-		t.Fatal("expected error from InsertAfter with foreign node")
-	} else if !errors.Is(err, collections.ErrNodeIsNotElement) {
-		t.Fatalf("expected ErrNodeIsNotElement, got %T", err)
-	}
-
-	node = l1.Head()
-	if newNode, err := l1.InsertBefore(node, 0); err != nil {
+	if data, err := json.Marshal(payload); err != nil {
 		t.Fatalf("unexpected error: %s", err)
-	} else if newNode != l1.Head() {
-		t.Fatal("expected inserted node to be the new head")
-	}
-
-	bad := &badNode[int]{
-		value: 0,
-	}
-	if _, err := l1.InsertBefore(bad, 0); err == nil {
-		t.Fatal("expected error from InsertBefore with bad node type")
-	} else if !errors.Is(err, collections.ErrWrongNodeType) {
-		t.Fatalf("expected ErrWrongNodeType, got %T", err)
+	} else if string(data) != `{"Items":[5,6]}` {
+		t.Fatalf("expected round trip to produce %s but got %s", `{"Items":[5,6]}`, data)
 	}
 }
 
-func TestLinkedListRemove(t *testing.T) {
+func TestLinkedListBinaryEncoding(t *testing.T) {
 	list := linkedlist.New[int]()
-	if _, err := list.Remove(0); err == nil {
-		t.Fatal("expected error from call to Remove on empty list")
-	} else if !errors.Is(err, collections.ErrEmptyList) {
-		t.Fatalf("expected ErrEmptyList but got: %s", err)
-	}
-
 	for i := 0; i < 1000; i++ {
 		list.Add(i)
 	}
+	expected := slices.Collect(list.Values())
 
-	indexErr := new(collections.ErrIndexOutOfRange)
-	if _, err := list.Remove(9999); err == nil {
-		t.Fatal("expected error from call to Remove with out of range index")
-	} else if !errors.As(err, indexErr) {
-		t.Fatalf("expected ErrInvalidIndex but got: %s", err)
-	}
-
-	for i := 999; i > -1; i-- {
-		if element, err := list.Remove(i); err != nil {
-			t.Fatalf("unexpected error: %s", err)
-		} else if element != i {
-			t.Fatalf("expected element with value %d but got %d", i, element)
-		}
-	}
-
-	for i := 0; i < 1000; i++ {
-		list.Add(rand.Int())
-	}
-	for i := 0; i < 999; i++ {
-		if _, err := list.Remove(rand.Intn(list.Size())); err != nil {
-			t.Fatalf("unexpected error: %s", err)
-		}
-	}
-}
-
-func TestLinkedListRemoveNode(t *testing.T) {
-	list := linkedlist.New[int]()
-	for i := 0; i < 100; i++ {
-		list.Add(i)
-	}
-
-	bad := &badNode[int]{}
-	if err := list.RemoveNode(bad); err == nil {
-		t.Fatal("expected error from removing wrong node type")
-	} else if !errors.Is(err, collections.ErrWrongNodeType) {
-		t.Fatalf("expected ErrWrongNodeType, got %T", err)
-	}
-
-	l2 := linkedlist.New[int]()
-	l2.Add(0)
-	if err := list.RemoveNode(l2.Head()); err == nil {
-		t.Fatal("expected error when trying to remove non-member node")
-	} else if !errors.Is(err, collections.ErrNodeIsNotElement) {
-		t.Fatalf("expected ErrNodeIsNotElement, got %T", err)
-	}
-
-	node := list.Head()
-	for i := 0; i < 50; i++ {
-		node = node.Next()
-	}
-	if err := list.RemoveNode(node); err != nil {
+	data, err := list.(encoding.BinaryMarshaler).MarshalBinary()
+	if err != nil {
 		t.Fatalf("unexpected error: %s", err)
 	}
-	node = list.Head()
-	for i := 0; i < 50; i++ {
-		if node.Value() != i {
-			t.Fatalf("expected element with value %d, got %d", i, node.Value())
-		}
-		node = node.Next()
-	}
-	for i := 51; i < 100; i++ {
-		if node.Value() != i {
-			t.Fatalf("expected element with value %d, got %d", i, node.Value())
-		}
-		node = node.Next()
-	}
-
-	if err := list.RemoveNode(list.Head()); err != nil {
+	decoded := linkedlist.New[int]()
+	if err := decoded.(encoding.BinaryUnmarshaler).UnmarshalBinary(data); err != nil {
 		t.Fatalf("unexpected error: %s", err)
+	} else if actual := slices.Collect(decoded.Values()); !slices.Equal(actual, expected) {
+		t.Fatal("decoded list does not match the original")
 	}
-	if err := list.RemoveNode(list.Tail()); err != nil {
+	decoded.Add(1000)
+	other := slicelist.New[int]()
+	if err := other.(encoding.BinaryUnmarshaler).UnmarshalBinary(data); err != nil {
 		t.Fatalf("unexpected error: %s", err)
+	} else if actual := slices.Collect(other.Values()); !slices.Equal(actual, expected) {
+		t.Fatal("list decoded by slicelist does not match the original")
 	}
-	node = list.Head()
-	for i := 1; i < 50; i++ {
-		if node.Value() != i {
-			t.Fatalf("expected element with value %d, got %d", i, node.Value())
-		}
-		node = node.Next()
-	}
-	for i := 51; i < 99; i++ {
-		if node.Value() != i {
-			t.Fatalf("expected element with value %d, got %d", i, node.Value())
-		}
-		node = node.Next()
-	}
-	if list.Tail().Value() != 98 {
-		t.Fatalf("expected element with value %d, got %d", 98, list.Tail().Value())
-	}
-}
 
-func TestLinkedListSize(t *testing.T) {
-	list := linkedlist.New[int]()
-	for i := 1; i < 1001; i++ {
-		list.Add(i)
-		if list.Size() != i {
-			t.Fatalf("expected list size %d but got %d", i, list.Size())
-		}
+	if err := decoded.(encoding.BinaryUnmarshaler).UnmarshalBinary([]byte("not a list")); err == nil || !errors.Is(err, collections.ErrInvalidEncoding) {
+		t.Fatalf("expected ErrInvalidEncoding from invalid data but got: %v", err)
 	}
 }
 
-func TestLinkedListSubList(t *testing.T) {
-	list := linkedlist.New[int]()
-	for i := 0; i < 100; i++ {
-		list.Add(i)
+func TestLinkedListGob(t *testing.T) {
+	list := linkedlist.New[string]()
+	for _, element := range []string{"a", "b", "c"} {
+		list.Add(element)
 	}
 
-	invalidErr := new(collections.ErrInvalidRange)
-	indexErr := new(collections.ErrIndexOutOfRange)
-	if _, err := list.SubList(-1, 50); err == nil {
-		t.Fatal("expected error from SubList with negative start index")
-	} else if !errors.As(err, invalidErr) {
-		t.Fatalf("expected ErrInvalidRange, got %T", err)
-	}
-	if _, err := list.SubList(20, 10); err == nil {
-		t.Fatal("expected error from SubList with end < start")
-	} else if !errors.As(err, invalidErr) {
-		t.Fatalf("expected ErrInvalidRange, got %T", err)
-	}
-	if _, err := list.SubList(110, 120); err == nil {
-		t.Fatal("expected error from SubList with start > size")
-	} else if !errors.As(err, indexErr) {
-		t.Fatalf("expected ErrIndexOutOfRange, got %T", err)
-	}
-	if _, err := list.SubList(50, 120); err == nil {
-		t.Fatal("expected error from SubList with end > size")
-	} else if !errors.As(err, indexErr) {
-		t.Fatalf("expected ErrIndexOutOfRange, got %T", err)
-	}
-
-	subList, err := list.SubList(11, 30)
-	if err != nil {
+	var buffer bytes.Buffer
+	if err := gob.NewEncoder(&buffer).Encode(list); err != nil {
 		t.Fatalf("unexpected error: %s", err)
-	} else if subList.Size() != 19 {
-		t.Fatalf("expected SubList with size %d, got %d", 19, subList.Size())
 	}
-	for i := 11; i < 30; i++ {
-		if element, err := subList.Get(i - 11); err != nil {
-			t.Fatalf("unexpected error: %s", err)
-		} else if element != i {
-			t.Fatalf("expected element with value %d, got %d", i-11, element)
-		}
-	}
-
-	if _, ok := subList.(collections.LinkedList[int]); !ok {
-		t.Fatal("expected to be able to cast List to LinkedList")
+	decoded := linkedlist.New[string]()
+	if err := gob.NewDecoder(&buffer).Decode(decoded); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if !slices.Equal(slices.Collect(decoded.Values()), slices.Collect(list.Values())) {
+		t.Fatal("decoded list does not match the original")
 	}
 }
 
-func TestLinkedListTail(t *testing.T) {
+func TestLinkedListWriteTo(t *testing.T) {
 	list := linkedlist.New[int]()
-	if node := list.Tail(); !reflect.ValueOf(node).IsNil() {
-		t.Fatal("expected tail node of new list to be nil")
+	for i := 0; i < 1000; i++ {
+		list.Add(i)
 	}
 
-	for i := 0; i < 1000; i++ {
-		list.Add(rand.Int())
+	var buffer bytes.Buffer
+	written, err := list.(io.WriterTo).WriteTo(&buffer)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if written != int64(buffer.Len()) {
+		t.Fatalf("expected WriteTo to report %d bytes but got %d", buffer.Len(), written)
 	}
-	list.Add(0)
 
-	if value := list.Tail().Value(); value != 0 {
-		t.Fatalf("expected tail node to have value %d but got %d", 0, value)
+	decoded := linkedlist.New[int]()
+	decoded.Add(-1)
+	if read, err := decoded.(io.ReaderFrom).ReadFrom(&buffer); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if read != written {
+		t.Fatalf("expected ReadFrom to report %d bytes but got %d", written, read)
+	} else if !slices.Equal(slices.Collect(decoded.Values()), slices.Collect(list.Values())) {
+		t.Fatal("decoded list does not match the original")
 	}
 }
@@ -0,0 +1,46 @@
+// ©2022 Brandon Moller
+
+package linkedlist
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bmoller/collections"
+)
+
+func TestLinkedListValidateCorrupted(t *testing.T) {
+	tests := []struct {
+		name    string
+		corrupt func(*linkedList[int])
+		index   int
+		problem string
+	}{
+		{"NegativeSize", func(l *linkedList[int]) { l.size = -1 }, -1, "size -1 is negative"},
+		{"SizeTooSmall", func(l *linkedList[int]) { l.size = 3 }, 3, "more nodes than the size 3"},
+		{"SizeTooLarge", func(l *linkedList[int]) { l.size = 7 }, -1, "5 nodes but the size is 7"},
+		{"HeadPrevious", func(l *linkedList[int]) { l.head.previous = l.tail }, 0, "head has a previous node"},
+		{"Previous", func(l *linkedList[int]) { l.head.next.next.previous = l.head }, 2, "node does not link back to the node before it"},
+		{"Tail", func(l *linkedList[int]) { l.tail = l.tail.previous }, -1, "tail is not the last node"},
+		{"ElementOf", func(l *linkedList[int]) { l.tail.previous.elementOf = new(linkedList[int]) }, 3, "node is not an element of the list"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			list := new(linkedList[int])
+			for i := 0; i < 5; i++ {
+				list.Add(i)
+			}
+			if err := list.Validate(); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			test.corrupt(list)
+			var corrupted collections.ErrCorrupted
+			if err := list.Validate(); err == nil || !errors.As(err, &corrupted) {
+				t.Fatalf("expected error %T but got %v", corrupted, err)
+			} else if corrupted.Index != test.index || corrupted.Problem != test.problem {
+				t.Fatalf("expected corruption at index %d (%q) but got index %d (%q)", test.index, test.problem, corrupted.Index, corrupted.Problem)
+			}
+		})
+	}
+}
@@ -0,0 +1,131 @@
+// ©2022 Brandon Moller
+
+/*
+Package linkedmap is an implementation of [collections.Map] that remembers the order in which keys were added.
+
+Entries are indexed by a built-in Go map and also linked together in a doubly-linked list, in the same way as a linkedlist.
+Iteration produces entries in insertion order; replacing the value of an existing key with Put does not change its position.
+A key that is deleted and then added again moves to the end of the order.
+*/
+package linkedmap
+
+import (
+	"iter"
+
+	"github.com/bmoller/collections"
+)
+
+type mapEntry[K comparable, V any] struct {
+	key      K
+	next     *mapEntry[K, V]
+	previous *mapEntry[K, V]
+	value    V
+}
+
+type linkedMap[K comparable, V any] struct {
+	data map[K]*mapEntry[K, V]
+	head *mapEntry[K, V]
+	tail *mapEntry[K, V]
+}
+
+func New[K comparable, V any]() collections.Map[K, V] {
+	return &linkedMap[K, V]{
+		data: make(map[K]*mapEntry[K, V]),
+	}
+}
+
+func (m *linkedMap[K, V]) Clear() {
+	clear(m.data)
+	m.head, m.tail = nil, nil
+}
+
+func (m *linkedMap[K, V]) ContainsKey(key K) bool {
+	_, ok := m.data[key]
+	return ok
+}
+
+func (m *linkedMap[K, V]) Delete(key K) {
+	entry, ok := m.data[key]
+	if !ok {
+		return
+	}
+
+	if entry.next != nil {
+		entry.next.previous = entry.previous
+	} else {
+		m.tail = entry.previous
+	}
+	if entry.previous != nil {
+		entry.previous.next = entry.next
+	} else {
+		m.head = entry.next
+	}
+	delete(m.data, key)
+}
+
+func (m *linkedMap[K, V]) Empty() bool {
+	return len(m.data) == 0
+}
+
+func (m *linkedMap[K, V]) Entries() iter.Seq2[K, V] {
+	return func(yield func(K, V) bool) {
+		for entry := m.head; entry != nil; entry = entry.next {
+			if !yield(entry.key, entry.value) {
+				return
+			}
+		}
+	}
+}
+
+func (m *linkedMap[K, V]) Get(key K) (value V, err error) {
+	entry, ok := m.data[key]
+	if !ok {
+		return value, collections.ErrKeyNotFound
+	}
+
+	return entry.value, nil
+}
+
+func (m *linkedMap[K, V]) Keys() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		for entry := m.head; entry != nil; entry = entry.next {
+			if !yield(entry.key) {
+				return
+			}
+		}
+	}
+}
+
+func (m *linkedMap[K, V]) Put(key K, value V) {
+	if entry, ok := m.data[key]; ok {
+		entry.value = value
+		return
+	}
+
+	entry := &mapEntry[K, V]{
+		key:      key,
+		previous: m.tail,
+		value:    value,
+	}
+	if m.tail == nil {
+		m.head = entry
+	} else {
+		m.tail.next = entry
+	}
+	m.tail = entry
+	m.data[key] = entry
+}
+
+func (m *linkedMap[K, V]) Size() int {
+	return len(m.data)
+}
+
+func (m *linkedMap[K, V]) Values() iter.Seq[V] {
+	return func(yield func(V) bool) {
+		for entry := m.head; entry != nil; entry = entry.next {
+			if !yield(entry.value) {
+				return
+			}
+		}
+	}
+}
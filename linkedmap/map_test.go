@@ -0,0 +1,198 @@
+// ©2022 Brandon Moller
+
+package linkedmap_test
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/bmoller/collections"
+	"github.com/bmoller/collections/collectionstest"
+	"github.com/bmoller/collections/linkedmap"
+)
+
+func FuzzMap(f *testing.F) {
+	collectionstest.FuzzMap(f, linkedmap.New[int, int])
+}
+
+func TestMapClear(t *testing.T) {
+	testMap := linkedmap.New[int, string]()
+	for i := 0; i < 1000; i++ {
+		testMap.Put(i, strconv.Itoa(i))
+	}
+	testMap.Clear()
+	if testMap.Size() != 0 || !testMap.Empty() {
+		t.Fatal("expected map to be empty after Clear")
+	}
+	for range testMap.Entries() {
+		t.Fatal("expected no entries after Clear")
+	}
+	testMap.Put(0, "0")
+	if testMap.Size() != 1 {
+		t.Fatalf("expected map size %d but got %d", 1, testMap.Size())
+	}
+}
+
+func TestMapContainsKey(t *testing.T) {
+	testMap := linkedmap.New[int, string]()
+	for i := 0; i < 1000; i += 2 {
+		testMap.Put(i, strconv.Itoa(i))
+	}
+	for i := 0; i < 1000; i++ {
+		if testMap.ContainsKey(i) != (i%2 == 0) {
+			t.Fatalf("unexpected result from ContainsKey for %d", i)
+		}
+	}
+}
+
+func TestMapDelete(t *testing.T) {
+	testMap := linkedmap.New[int, string]()
+	for i := 0; i < 1000; i++ {
+		testMap.Put(i, strconv.Itoa(i))
+	}
+	testMap.Delete(-1)
+	if testMap.Size() != 1000 {
+		t.Fatalf("expected map size %d after deleting missing key but got %d", 1000, testMap.Size())
+	}
+	for i := 0; i < 1000; i += 2 {
+		testMap.Delete(i)
+	}
+	if testMap.Size() != 500 {
+		t.Fatalf("expected map size %d but got %d", 500, testMap.Size())
+	}
+	for i := 0; i < 1000; i++ {
+		if testMap.ContainsKey(i) != (i%2 == 1) {
+			t.Fatalf("unexpected result from ContainsKey for %d", i)
+		}
+	}
+	for key, value := range testMap.Entries() {
+		if key%2 == 0 || value != strconv.Itoa(key) {
+			t.Fatalf("unexpected entry %d: %s", key, value)
+		}
+	}
+}
+
+func TestMapEmpty(t *testing.T) {
+	testMap := linkedmap.New[int, string]()
+	if !testMap.Empty() {
+		t.Fatal("expected new map to be empty")
+	}
+	testMap.Put(0, "0")
+	if testMap.Empty() {
+		t.Fatal("expected map to not be empty after Put")
+	}
+}
+
+func TestMapGet(t *testing.T) {
+	testMap := linkedmap.New[int, string]()
+	if _, err := testMap.Get(0); err == nil || !errors.Is(err, collections.ErrKeyNotFound) {
+		t.Fatalf("expected ErrKeyNotFound from Get on empty map but got: %v", err)
+	}
+
+	for i := 0; i < 1000; i++ {
+		testMap.Put(i, strconv.Itoa(i))
+	}
+	for i := 0; i < 1000; i++ {
+		if value, err := testMap.Get(i); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if value != strconv.Itoa(i) {
+			t.Fatalf("expected value %q but got %q", strconv.Itoa(i), value)
+		}
+	}
+	if _, err := testMap.Get(1000); err == nil || !errors.Is(err, collections.ErrKeyNotFound) {
+		t.Fatalf("expected ErrKeyNotFound from Get with missing key but got: %v", err)
+	}
+}
+
+func TestMapPut(t *testing.T) {
+	testMap := linkedmap.New[int, string]()
+	for i := 0; i < 1000; i++ {
+		testMap.Put(i%100, strconv.Itoa(i))
+	}
+	if testMap.Size() != 100 {
+		t.Fatalf("expected map size %d but got %d", 100, testMap.Size())
+	}
+	for i := 0; i < 100; i++ {
+		if value, _ := testMap.Get(i); value != strconv.Itoa(i+900) {
+			t.Fatalf("expected value %q but got %q", strconv.Itoa(i+900), value)
+		}
+	}
+}
+
+func TestMapSize(t *testing.T) {
+	testMap := linkedmap.New[int, string]()
+	for i := 1; i < 1001; i++ {
+		testMap.Put(i, strconv.Itoa(i))
+		if testMap.Size() != i {
+			t.Fatalf("expected map size %d but got %d", i, testMap.Size())
+		}
+	}
+}
+
+func TestMapIteration(t *testing.T) {
+	testMap := linkedmap.New[int, string]()
+	for i := 999; i > -1; i-- {
+		testMap.Put(i, "")
+	}
+	for i := 0; i < 1000; i++ {
+		testMap.Put(i, strconv.Itoa(i))
+	}
+	testMap.Delete(500)
+	testMap.Put(500, "500")
+
+	order := make([]int, 0, 1000)
+	for i := 999; i > -1; i-- {
+		if i != 500 {
+			order = append(order, i)
+		}
+	}
+	order = append(order, 500)
+
+	i := 0
+	for key, value := range testMap.Entries() {
+		if key != order[i] || value != strconv.Itoa(order[i]) {
+			t.Fatalf("expected entry %d but got %d: %q", order[i], key, value)
+		}
+		i++
+	}
+
+	keys := make([]int, 0, 1000)
+	for key := range testMap.Keys() {
+		keys = append(keys, key)
+	}
+	i = 0
+	for value := range testMap.Values() {
+		if value != strconv.Itoa(keys[i]) {
+			t.Fatalf("expected value %q but got %q", strconv.Itoa(keys[i]), value)
+		}
+		i++
+	}
+	if len(keys) != 1000 || i != 1000 {
+		t.Fatalf("expected %d keys and values but got %d and %d", 1000, len(keys), i)
+	}
+	if keys[0] != 999 || keys[999] != 500 {
+		t.Fatalf("expected keys in insertion order but got first %d and last %d", keys[0], keys[999])
+	}
+
+	testMap.Delete(500)
+	testMap.Delete(999)
+	testMap.Put(1000, "1000")
+	keys = keys[:0]
+	for key := range testMap.Keys() {
+		keys = append(keys, key)
+	}
+	if keys[0] != 998 || keys[len(keys)-2] != 0 || keys[len(keys)-1] != 1000 {
+		t.Fatal("expected order to be maintained after deleting the first and last keys")
+	}
+
+	for range testMap.Entries() {
+		break
+	}
+	for range testMap.Keys() {
+		break
+	}
+	for range testMap.Values() {
+		break
+	}
+}
@@ -4,11 +4,25 @@
 Package linkedqueue provides an implementation of [collections.Queue] backed by individual node instances.
 Each element added to the queue is stored in a node, with a pointer to the next node.
 The queue maintains references to the next node to return and the tail for fast Pop and Push operations.
+
+As JSON a queue is an array of its elements from front to back.
+The binary encoding, WriteTo and ReadFrom stream the elements in the same order.
+
+Queues implement [collections.RemovingIterable], so elements can be removed from anywhere in the queue while iterating over it.
+
+Queues implement [collections.Validator].
+Validate follows the nodes from the head, checking that the last is the tail and that their number matches the size.
 */
 package linkedqueue
 
 import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+
 	"github.com/bmoller/collections"
+	"github.com/bmoller/collections/internal/wire"
 )
 
 type queueNode[T comparable] struct {
@@ -16,20 +30,107 @@ type queueNode[T comparable] struct {
 	value T
 }
 
+type iterator[T comparable] struct {
+	last          *queueNode[T]
+	modifications int
+	next          *queueNode[T]
+	previous      *queueNode[T] // the node before last, as nodes only link forwards
+	queue         *queue[T]
+}
+
+func (i *iterator[T]) Next() (element T, err error) {
+	if i.queue.modifications != i.modifications {
+		return element, collections.ErrConcurrentModification
+	} else if i.next == nil {
+		return element, collections.ErrNoMoreItems
+	}
+	if i.last != nil {
+		i.previous = i.last
+	}
+	i.last, i.next = i.next, i.next.next
+
+	return i.last.value, nil
+}
+
+func (i *iterator[T]) Remove() error {
+	q := i.queue
+	if q.modifications != i.modifications {
+		return collections.ErrConcurrentModification
+	} else if i.last == nil {
+		return collections.ErrNothingToRemove
+	}
+	if i.previous == nil {
+		q.head = i.next
+	} else {
+		i.previous.next = i.next
+	}
+	if q.tail == i.last {
+		q.tail = i.previous
+	}
+	q.modifications++
+	q.size--
+	i.last, i.modifications = nil, q.modifications
+
+	return nil
+}
+
 type queue[T comparable] struct {
-	head *queueNode[T]
-	size int
-	tail *queueNode[T]
+	head          *queueNode[T]
+	modifications int
+	size          int
+	tail          *queueNode[T]
 }
 
 func New[T comparable]() collections.Queue[T] {
 	return new(queue[T])
 }
 
+func (q *queue[T]) reset(int) {
+	q.head, q.size, q.tail = nil, 0, nil
+	q.modifications++
+}
+
+func (q *queue[T]) All() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		i := 0
+		for node := q.head; node != nil; node = node.next {
+			if !yield(i, node.value) {
+				return
+			}
+			i++
+		}
+	}
+}
+
 func (q *queue[T]) Empty() bool {
 	return q.size == 0
 }
 
+func (q *queue[T]) GobDecode(data []byte) error {
+	return q.UnmarshalBinary(data)
+}
+
+func (q *queue[T]) GobEncode() ([]byte, error) {
+	return q.MarshalBinary()
+}
+
+func (q *queue[T]) Iterator() collections.Iterator[T] {
+	return q.RemovingIterator().Next
+}
+
+func (q *queue[T]) MarshalBinary() ([]byte, error) {
+	return wire.Marshal(wire.Queue, q.size, q.Values())
+}
+
+func (q *queue[T]) MarshalJSON() ([]byte, error) {
+	elements := make([]T, 0, q.size)
+	for node := q.head; node != nil; node = node.next {
+		elements = append(elements, node.value)
+	}
+
+	return json.Marshal(elements)
+}
+
 func (q *queue[T]) Peek() (element T, err error) {
 	if q.size == 0 {
 		return element, collections.ErrEmptyQueue
@@ -45,6 +146,10 @@ func (q *queue[T]) Pop() (element T, err error) {
 
 	element = q.head.value
 	q.head = q.head.next
+	if q.head == nil {
+		q.tail = nil
+	}
+	q.modifications++
 	q.size--
 
 	return element, nil
@@ -63,9 +168,90 @@ func (q *queue[T]) Push(item T) {
 		q.tail.next = element
 		q.tail = element
 	}
+	q.modifications++
 	q.size++
 }
 
+func (q *queue[T]) ReadFrom(r io.Reader) (int64, error) {
+	return wire.Read(r, wire.Queue, q.reset, q.Push)
+}
+
+func (q *queue[T]) RemovingIterator() collections.RemovingIterator[T] {
+	return &iterator[T]{
+		modifications: q.modifications,
+		next:          q.head,
+		queue:         q,
+	}
+}
+
 func (q *queue[T]) Size() int {
 	return q.size
 }
+
+func (q *queue[T]) UnmarshalBinary(data []byte) error {
+	return wire.Unmarshal(data, wire.Queue, q.reset, q.Push)
+}
+
+func (q *queue[T]) UnmarshalJSON(data []byte) error {
+	var elements []T
+	if err := json.Unmarshal(data, &elements); err != nil {
+		return err
+	}
+
+	q.reset(len(elements))
+	for _, element := range elements {
+		q.Push(element)
+	}
+
+	return nil
+}
+
+func (q *queue[T]) Validate() error {
+	if q.size < 0 {
+		return collections.ErrCorrupted{
+			Index:   -1,
+			Problem: fmt.Sprintf("size %d is negative", q.size),
+		}
+	}
+
+	var last *queueNode[T]
+	i := 0
+	for node := q.head; node != nil; node = node.next {
+		if i == q.size {
+			return collections.ErrCorrupted{
+				Index:   i,
+				Problem: fmt.Sprintf("more nodes than the size %d", q.size),
+			}
+		}
+		last = node
+		i++
+	}
+
+	if q.tail != last {
+		return collections.ErrCorrupted{
+			Index:   -1,
+			Problem: "tail is not the last node",
+		}
+	} else if i != q.size {
+		return collections.ErrCorrupted{
+			Index:   -1,
+			Problem: fmt.Sprintf("%d nodes but the size is %d", i, q.size),
+		}
+	}
+
+	return nil
+}
+
+func (q *queue[T]) Values() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for node := q.head; node != nil; node = node.next {
+			if !yield(node.value) {
+				return
+			}
+		}
+	}
+}
+
+func (q *queue[T]) WriteTo(w io.Writer) (int64, error) {
+	return wire.Write(w, wire.Queue, q.size, q.Values())
+}
@@ -3,68 +3,160 @@
 package linkedqueue_test
 
 import (
+	"bytes"
+	"encoding"
+	"encoding/gob"
+	"encoding/json"
 	"errors"
+	"io"
+	"slices"
 	"testing"
 
 	"github.com/bmoller/collections"
+	"github.com/bmoller/collections/collectionstest"
 	"github.com/bmoller/collections/linkedqueue"
 )
 
-func TestQueueEmpty(t *testing.T) {
-	queue := linkedqueue.New[int]()
-	if !queue.Empty() {
-		t.Fatal("expected new queue to be empty")
-	}
-	queue.Push(1)
-	if queue.Empty() {
-		t.Fatal("expected queue to not be empty after pushing an item")
-	}
+func FuzzQueue(f *testing.F) {
+	collectionstest.FuzzQueue(f, linkedqueue.New[int])
 }
 
-func TestQueuePeek(t *testing.T) {
+func TestQueue(t *testing.T) {
+	collectionstest.TestQueue(t, linkedqueue.New[int])
+}
+
+func TestQueueMarshalJSON(t *testing.T) {
 	queue := linkedqueue.New[int]()
-	if _, err := queue.Peek(); err == nil {
-		t.Fatal("expected error from Peek on new queue")
-	} else if !errors.Is(err, collections.ErrEmptyQueue) {
-		t.Fatalf("expected ErrEmptyQueue but got: %s", err)
+	if data, err := json.Marshal(queue); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if string(data) != "[]" {
+		t.Fatalf("expected empty queue to encode as %s but got %s", "[]", data)
 	}
 
 	for i := 0; i < 1000; i++ {
 		queue.Push(i)
 	}
-	for i := 0; i < 1000; i++ {
-		if element, err := queue.Peek(); err != nil {
+	data, err := json.Marshal(queue)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	var elements []int
+	if err := json.Unmarshal(data, &elements); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if len(elements) != 1000 {
+		t.Fatalf("expected %d encoded elements but got %d", 1000, len(elements))
+	}
+	for i, element := range elements {
+		if element != i {
+			t.Fatalf("expected element with value %d at index %d but got %d", i, i, element)
+		}
+	}
+}
+
+func TestQueueUnmarshalJSON(t *testing.T) {
+	queue := linkedqueue.New[int]()
+	if err := json.Unmarshal([]byte("[0,1,2]"), queue); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	queue.Push(3)
+	for i := 0; i < 4; i++ {
+		if element, err := queue.Pop(); err != nil {
 			t.Fatalf("unexpected error: %s", err)
 		} else if element != i {
 			t.Fatalf("expected element with value %d but got %d", i, element)
 		}
-		queue.Pop()
+	}
+	for i := 0; i < 4; i++ {
+		queue.Push(i)
+	}
+
+	if err := json.Unmarshal([]byte(`{"a":1}`), queue); err == nil {
+		t.Fatal("expected error from decoding an object")
+	} else if queue.Size() != 4 {
+		t.Fatalf("expected failed decode to leave size %d but got %d", 4, queue.Size())
+	}
+
+	payload := struct {
+		Items collections.Queue[int]
+	}{
+		Items: linkedqueue.New[int](),
+	}
+	payload.Items.Push(99)
+	if err := json.Unmarshal([]byte(`{"Items":[5,6]}`), &payload); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if payload.Items.Size() != 2 {
+		t.Fatalf("expected decoding to replace contents leaving size %d but got %d", 2, payload.Items.Size())
+	}
+	if data, err := json.Marshal(payload); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if string(data) != `{"Items":[5,6]}` {
+		t.Fatalf("expected round trip to produce %s but got %s", `{"Items":[5,6]}`, data)
 	}
 }
 
-func TestQueuePop(t *testing.T) {
+func TestQueueBinaryEncoding(t *testing.T) {
 	queue := linkedqueue.New[int]()
 	for i := 0; i < 1000; i++ {
 		queue.Push(i)
 	}
-	for i := 0; i < 1000; i++ {
-		if element, err := queue.Pop(); err != nil {
-			t.Fatalf("expected element from pop but received error: %s", err)
-		} else if element != i {
-			t.Fatalf("expected element %d but got %d", i, element)
-		}
+	expected := slices.Collect(queue.Values())
+
+	data, err := queue.(encoding.BinaryMarshaler).MarshalBinary()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	decoded := linkedqueue.New[int]()
+	if err := decoded.(encoding.BinaryUnmarshaler).UnmarshalBinary(data); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if actual := slices.Collect(decoded.Values()); !slices.Equal(actual, expected) {
+		t.Fatal("decoded queue does not match the original")
 	}
-	if _, err := queue.Pop(); err == nil || !errors.Is(err, collections.ErrEmptyQueue) {
-		t.Fatalf("expected ErrEmptyQueue from Pop on empty stack but got: %s", err)
+	decoded.Push(1000)
+
+	if err := decoded.(encoding.BinaryUnmarshaler).UnmarshalBinary([]byte("not a queue")); err == nil || !errors.Is(err, collections.ErrInvalidEncoding) {
+		t.Fatalf("expected ErrInvalidEncoding from invalid data but got: %v", err)
 	}
 }
 
-func TestQueueSize(t *testing.T) {
+func TestQueueGob(t *testing.T) {
+	queue := linkedqueue.New[string]()
+	for _, element := range []string{"a", "b", "c"} {
+		queue.Push(element)
+	}
+
+	var buffer bytes.Buffer
+	if err := gob.NewEncoder(&buffer).Encode(queue); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	decoded := linkedqueue.New[string]()
+	if err := gob.NewDecoder(&buffer).Decode(decoded); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if !slices.Equal(slices.Collect(decoded.Values()), slices.Collect(queue.Values())) {
+		t.Fatal("decoded queue does not match the original")
+	}
+}
+
+func TestQueueWriteTo(t *testing.T) {
 	queue := linkedqueue.New[int]()
-	for i := 1; i < 1001; i++ {
+	for i := 0; i < 1000; i++ {
 		queue.Push(i)
-		if queue.Size() != i {
-			t.Fatalf("expected queue size %d but got %d", i, queue.Size())
-		}
+	}
+
+	var buffer bytes.Buffer
+	written, err := queue.(io.WriterTo).WriteTo(&buffer)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if written != int64(buffer.Len()) {
+		t.Fatalf("expected WriteTo to report %d bytes but got %d", buffer.Len(), written)
+	}
+
+	decoded := linkedqueue.New[int]()
+	decoded.Push(-1)
+	if read, err := decoded.(io.ReaderFrom).ReadFrom(&buffer); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if read != written {
+		t.Fatalf("expected ReadFrom to report %d bytes but got %d", written, read)
+	} else if !slices.Equal(slices.Collect(decoded.Values()), slices.Collect(queue.Values())) {
+		t.Fatal("decoded queue does not match the original")
 	}
 }
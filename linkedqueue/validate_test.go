@@ -0,0 +1,44 @@
+// ©2022 Brandon Moller
+
+package linkedqueue
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bmoller/collections"
+)
+
+func TestQueueValidateCorrupted(t *testing.T) {
+	tests := []struct {
+		name    string
+		corrupt func(*queue[int])
+		index   int
+		problem string
+	}{
+		{"NegativeSize", func(q *queue[int]) { q.size = -1 }, -1, "size -1 is negative"},
+		{"SizeTooSmall", func(q *queue[int]) { q.size = 3 }, 3, "more nodes than the size 3"},
+		{"SizeTooLarge", func(q *queue[int]) { q.size = 7 }, -1, "5 nodes but the size is 7"},
+		{"Tail", func(q *queue[int]) { q.tail = q.head.next }, -1, "tail is not the last node"},
+		{"Next", func(q *queue[int]) { q.head.next.next = nil }, -1, "tail is not the last node"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			queue := new(queue[int])
+			for i := 0; i < 5; i++ {
+				queue.Push(i)
+			}
+			if err := queue.Validate(); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			test.corrupt(queue)
+			var corrupted collections.ErrCorrupted
+			if err := queue.Validate(); err == nil || !errors.As(err, &corrupted) {
+				t.Fatalf("expected error %T but got %v", corrupted, err)
+			} else if corrupted.Index != test.index || corrupted.Problem != test.problem {
+				t.Fatalf("expected corruption at index %d (%q) but got index %d (%q)", test.index, test.problem, corrupted.Index, corrupted.Problem)
+			}
+		})
+	}
+}
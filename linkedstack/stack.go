@@ -4,10 +4,68 @@
 Package linkedstack includes an implementation of Stack that is backed by individual node instances.
 Each node contains its element of the Stack as a value, and a pointer to the next node to be on top when removed.
 There is no backing slice or other structure to scale, so performance should be high.
+
+JSON encoding produces an array running from the bottom of the Stack to the top; decoding pushes the elements of an array in that order.
+Binary encodings use the same order; as nodes only link downwards, encoding copies the elements into a slice and reverses it, leaving the nodes untouched.
+
+Stacks implement [collections.RemovingIterable], so elements can be removed from anywhere in the stack while iterating over it.
+
+Stacks implement [collections.Validator].
+Validate follows the nodes down from the top, checking that their number matches the size.
 */
 package linkedstack
 
-import "github.com/bmoller/collections"
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"slices"
+
+	"github.com/bmoller/collections"
+	"github.com/bmoller/collections/internal/wire"
+)
+
+type iterator[T comparable] struct {
+	above         *node[T] // the node above last, as nodes only link downwards
+	last          *node[T]
+	modifications int
+	next          *node[T]
+	stack         *stack[T]
+}
+
+func (i *iterator[T]) Next() (element T, err error) {
+	if i.stack.modifications != i.modifications {
+		return element, collections.ErrConcurrentModification
+	} else if i.next == nil {
+		return element, collections.ErrNoMoreItems
+	}
+	if i.last != nil {
+		i.above = i.last
+	}
+	i.last, i.next = i.next, i.next.previous
+
+	return i.last.value, nil
+}
+
+func (i *iterator[T]) Remove() error {
+	s := i.stack
+	if s.modifications != i.modifications {
+		return collections.ErrConcurrentModification
+	} else if i.last == nil {
+		return collections.ErrNothingToRemove
+	}
+	if i.above == nil {
+		s.top = i.next
+	} else {
+		i.above.previous = i.next
+	}
+	s.modifications++
+	s.size--
+	i.last, i.modifications = nil, s.modifications
+
+	return nil
+}
 
 type node[T comparable] struct {
 	previous *node[T]
@@ -15,18 +73,64 @@ type node[T comparable] struct {
 }
 
 type stack[T comparable] struct {
-	size int
-	top  *node[T]
+	modifications int
+	size          int
+	top           *node[T]
 }
 
 func New[T comparable]() collections.Stack[T] {
 	return new(stack[T])
 }
 
+// bottomUp returns a copy of the elements in the order in which they were pushed.
+func (s *stack[T]) bottomUp() []T {
+	elements := slices.AppendSeq(make([]T, 0, s.size), s.Values())
+	slices.Reverse(elements)
+
+	return elements
+}
+
+func (s *stack[T]) reset(int) {
+	s.size, s.top = 0, nil
+	s.modifications++
+}
+
+func (s *stack[T]) All() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		i := 0
+		for node := s.top; node != nil; node = node.previous {
+			if !yield(i, node.value) {
+				return
+			}
+			i++
+		}
+	}
+}
+
 func (s *stack[T]) Empty() bool {
 	return s.size == 0
 }
 
+func (s *stack[T]) GobDecode(data []byte) error {
+	return s.UnmarshalBinary(data)
+}
+
+func (s *stack[T]) GobEncode() ([]byte, error) {
+	return s.MarshalBinary()
+}
+
+func (s *stack[T]) Iterator() collections.Iterator[T] {
+	return s.RemovingIterator().Next
+}
+
+func (s *stack[T]) MarshalBinary() ([]byte, error) {
+	return wire.Marshal(wire.Stack, s.size, slices.Values(s.bottomUp()))
+}
+
+func (s *stack[T]) MarshalJSON() ([]byte, error) {
+	return json.Marshal(s.bottomUp())
+}
+
 func (s *stack[T]) Peek() (element T, err error) {
 	if s.size == 0 {
 		return element, collections.ErrEmptyStack
@@ -42,6 +146,7 @@ func (s *stack[T]) Pop() (element T, err error) {
 
 	element = s.top.value
 	s.top = s.top.previous
+	s.modifications++
 	s.size--
 
 	return element, nil
@@ -53,9 +158,82 @@ func (s *stack[T]) Push(item T) {
 		value:    item,
 	}
 	s.top = top
+	s.modifications++
 	s.size++
 }
 
+func (s *stack[T]) ReadFrom(r io.Reader) (int64, error) {
+	return wire.Read(r, wire.Stack, s.reset, s.Push)
+}
+
+func (s *stack[T]) RemovingIterator() collections.RemovingIterator[T] {
+	return &iterator[T]{
+		modifications: s.modifications,
+		next:          s.top,
+		stack:         s,
+	}
+}
+
 func (s *stack[T]) Size() int {
 	return s.size
 }
+
+func (s *stack[T]) UnmarshalBinary(data []byte) error {
+	return wire.Unmarshal(data, wire.Stack, s.reset, s.Push)
+}
+
+func (s *stack[T]) UnmarshalJSON(data []byte) error {
+	var elements []T
+	if err := json.Unmarshal(data, &elements); err != nil {
+		return err
+	}
+
+	s.reset(len(elements))
+	for _, element := range elements {
+		s.Push(element)
+	}
+
+	return nil
+}
+
+func (s *stack[T]) Validate() error {
+	if s.size < 0 {
+		return collections.ErrCorrupted{
+			Index:   -1,
+			Problem: fmt.Sprintf("size %d is negative", s.size),
+		}
+	}
+
+	i := 0
+	for node := s.top; node != nil; node = node.previous {
+		if i == s.size {
+			return collections.ErrCorrupted{
+				Index:   i,
+				Problem: fmt.Sprintf("more nodes than the size %d", s.size),
+			}
+		}
+		i++
+	}
+	if i != s.size {
+		return collections.ErrCorrupted{
+			Index:   -1,
+			Problem: fmt.Sprintf("%d nodes but the size is %d", i, s.size),
+		}
+	}
+
+	return nil
+}
+
+func (s *stack[T]) Values() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for node := s.top; node != nil; node = node.previous {
+			if !yield(node.value) {
+				return
+			}
+		}
+	}
+}
+
+func (s *stack[T]) WriteTo(w io.Writer) (int64, error) {
+	return wire.Write(w, wire.Stack, s.size, slices.Values(s.bottomUp()))
+}
@@ -3,95 +3,215 @@
 package linkedstack_test
 
 import (
+	"bytes"
+	"encoding"
+	"encoding/gob"
+	"encoding/json"
 	"errors"
-	"math/rand"
-	"os"
+	"io"
+	"slices"
+	"sync"
 	"testing"
-	"time"
 
 	"github.com/bmoller/collections"
+	"github.com/bmoller/collections/collectionstest"
 	"github.com/bmoller/collections/linkedstack"
+	"github.com/bmoller/collections/slicestack"
 )
 
-func TestMain(m *testing.M) {
-	rand.Seed(time.Now().UTC().UnixNano())
-	os.Exit(m.Run())
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) {
+	return 0, io.ErrClosedPipe
 }
 
-func TestStackEmpty(t *testing.T) {
-	stack := linkedstack.New[int]()
-	if !stack.Empty() {
-		t.Fatal("expected new Stack to be empty")
-	}
-	stack.Push(0)
-	if stack.Empty() {
-		t.Fatal("expected Stack not to be empty after pushing an element")
-	}
+type writerFunc func([]byte) (int, error)
+
+func (f writerFunc) Write(p []byte) (int, error) {
+	return f(p)
+}
+
+func FuzzStack(f *testing.F) {
+	collectionstest.FuzzStack(f, linkedstack.New[int])
+}
+
+func TestStack(t *testing.T) {
+	collectionstest.TestStack(t, linkedstack.New[int])
 }
 
-func TestStackPeek(t *testing.T) {
+func TestStackMarshalJSON(t *testing.T) {
 	stack := linkedstack.New[int]()
-	if _, err := stack.Peek(); err == nil {
-		t.Fatal("expected error from Peek on a new Stack")
-	} else if !errors.Is(err, collections.ErrEmptyStack) {
-		t.Fatalf("expected ErrEmptyStack, got %T", err)
+	if data, err := json.Marshal(stack); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if string(data) != "[]" {
+		t.Fatalf("expected empty stack to encode as %s but got %s", "[]", data)
 	}
 
 	for i := 0; i < 1000; i++ {
 		stack.Push(i)
 	}
-	for i := 999; i > -1; i-- {
-		switch element, err := stack.Peek(); {
-		case err != nil:
-			t.Fatalf("unexpected error: %s", err)
-		case element != i:
-			t.Fatalf("expected element with value %d, got %d", i, element)
-		case stack.Size() != i+1:
-			t.Fatalf("expected Stack size %d, got %d", i+1, stack.Size())
+	data, err := json.Marshal(stack)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	var elements []int
+	if err := json.Unmarshal(data, &elements); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if len(elements) != 1000 {
+		t.Fatalf("expected %d encoded elements but got %d", 1000, len(elements))
+	}
+	for i, element := range elements {
+		if element != i {
+			t.Fatalf("expected element with value %d at index %d but got %d", i, i, element)
 		}
-		stack.Pop()
 	}
-}
 
-func TestStackPop(t *testing.T) {
-	stack := linkedstack.New[int]()
-	if _, err := stack.Pop(); err == nil {
-		t.Fatal("expected error from Pop on a new Stack")
-	} else if !errors.Is(err, collections.ErrEmptyStack) {
-		t.Fatalf("expected ErrEmptyStack, got %T", err)
+	// encoding only reads the stack, so it may run concurrently with other reads
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if concurrent, err := json.Marshal(stack); err != nil {
+				t.Errorf("unexpected error: %s", err)
+			} else if !bytes.Equal(concurrent, data) {
+				t.Error("concurrent encoding does not match the original")
+			}
+		}()
 	}
+	wg.Wait()
+}
 
-	for i := 0; i < 1000; i++ {
-		stack.Push(i)
+func TestStackUnmarshalJSON(t *testing.T) {
+	stack := linkedstack.New[int]()
+	if err := json.Unmarshal([]byte("[0,1,2]"), stack); err != nil {
+		t.Fatalf("unexpected error: %s", err)
 	}
-	for i := 999; i > -1; i-- {
-		switch element, err := stack.Pop(); {
-		case err != nil:
+	stack.Push(3)
+	for i := 3; i > -1; i-- {
+		if element, err := stack.Pop(); err != nil {
 			t.Fatalf("unexpected error: %s", err)
-		case element != i:
-			t.Fatalf("expected element with value %d, got %d", i, element)
-		case stack.Size() != i:
-			t.Fatalf("expected Stack size %d, got %d", i, stack.Size())
+		} else if element != i {
+			t.Fatalf("expected element with value %d but got %d", i, element)
 		}
 	}
+	for i := 0; i < 4; i++ {
+		stack.Push(i)
+	}
+
+	if err := json.Unmarshal([]byte(`{"a":1}`), stack); err == nil {
+		t.Fatal("expected error from decoding an object")
+	} else if stack.Size() != 4 {
+		t.Fatalf("expected failed decode to leave size %d but got %d", 4, stack.Size())
+	}
+
+	payload := struct {
+		Items collections.Stack[int]
+	}{
+		Items: linkedstack.New[int](),
+	}
+	payload.Items.Push(99)
+	if err := json.Unmarshal([]byte(`{"Items":[5,6]}`), &payload); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if payload.Items.Size() != 2 {
+		t.Fatalf("expected decoding to replace contents leaving size %d but got %d", 2, payload.Items.Size())
+	}
+	if data, err := json.Marshal(payload); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if string(data) != `{"Items":[5,6]}` {
+		t.Fatalf("expected round trip to produce %s but got %s", `{"Items":[5,6]}`, data)
+	}
 }
 
-func TestStackPush(t *testing.T) {
+func TestStackBinaryEncoding(t *testing.T) {
 	stack := linkedstack.New[int]()
 	for i := 0; i < 1000; i++ {
-		stack.Push(rand.Int())
+		stack.Push(i)
+	}
+	expected := slices.Collect(stack.Values())
+
+	data, err := stack.(encoding.BinaryMarshaler).MarshalBinary()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	decoded := linkedstack.New[int]()
+	if err := decoded.(encoding.BinaryUnmarshaler).UnmarshalBinary(data); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if actual := slices.Collect(decoded.Values()); !slices.Equal(actual, expected) {
+		t.Fatal("decoded stack does not match the original")
+	}
+	decoded.Push(1000)
+	other := slicestack.New[int]()
+	if err := other.(encoding.BinaryUnmarshaler).UnmarshalBinary(data); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if actual := slices.Collect(other.Values()); !slices.Equal(actual, expected) {
+		t.Fatal("stack decoded by slicestack does not match the original")
+	}
+
+	if err := decoded.(encoding.BinaryUnmarshaler).UnmarshalBinary([]byte("not a stack")); err == nil || !errors.Is(err, collections.ErrInvalidEncoding) {
+		t.Fatalf("expected ErrInvalidEncoding from invalid data but got: %v", err)
 	}
 }
 
-func TestStackSize(t *testing.T) {
-	stack := linkedstack.New[int]()
-	if stack.Size() != 0 {
-		t.Fatalf("expected new Stack to have size %d, got %d", 0, stack.Size())
+func TestStackGob(t *testing.T) {
+	stack := linkedstack.New[string]()
+	for _, element := range []string{"a", "b", "c"} {
+		stack.Push(element)
+	}
+
+	var buffer bytes.Buffer
+	if err := gob.NewEncoder(&buffer).Encode(stack); err != nil {
+		t.Fatalf("unexpected error: %s", err)
 	}
-	for i := 1; i < 1001; i++ {
+	decoded := linkedstack.New[string]()
+	if err := gob.NewDecoder(&buffer).Decode(decoded); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if !slices.Equal(slices.Collect(decoded.Values()), slices.Collect(stack.Values())) {
+		t.Fatal("decoded stack does not match the original")
+	}
+}
+
+func TestStackWriteTo(t *testing.T) {
+	stack := linkedstack.New[int]()
+	for i := 0; i < 1000; i++ {
 		stack.Push(i)
-		if stack.Size() != i {
-			t.Fatalf("expected size %d, got %d", i, stack.Size())
+	}
+
+	var buffer bytes.Buffer
+	written, err := stack.(io.WriterTo).WriteTo(&buffer)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if written != int64(buffer.Len()) {
+		t.Fatalf("expected WriteTo to report %d bytes but got %d", buffer.Len(), written)
+	}
+
+	decoded := linkedstack.New[int]()
+	decoded.Push(-1)
+	if read, err := decoded.(io.ReaderFrom).ReadFrom(&buffer); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if read != written {
+		t.Fatalf("expected ReadFrom to report %d bytes but got %d", written, read)
+	} else if !slices.Equal(slices.Collect(decoded.Values()), slices.Collect(stack.Values())) {
+		t.Fatal("decoded stack does not match the original")
+	}
+
+	// the stack is left as it is while being written, and after a failed write
+	reading := writerFunc(func(p []byte) (int, error) {
+		if err := stack.(collections.Validator).Validate(); err != nil {
+			t.Fatalf("unexpected error while writing: %s", err)
+		} else if top, _ := stack.Peek(); top != 999 {
+			t.Fatalf("expected top element %d while writing but got %d", 999, top)
 		}
+		return len(p), nil
+	})
+	if _, err := stack.(io.WriterTo).WriteTo(reading); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if _, err := stack.(io.WriterTo).WriteTo(failingWriter{}); err == nil || !errors.Is(err, io.ErrClosedPipe) {
+		t.Fatalf("expected error from failing writer but got: %v", err)
+	} else if err := stack.(collections.Validator).Validate(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if top, _ := stack.Peek(); top != 999 || !slices.Equal(slices.Collect(decoded.Values()), slices.Collect(stack.Values())) {
+		t.Fatal("stack does not match its contents before the failed write")
 	}
 }
@@ -0,0 +1,43 @@
+// ©2022 Brandon Moller
+
+package linkedstack
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bmoller/collections"
+)
+
+func TestStackValidateCorrupted(t *testing.T) {
+	tests := []struct {
+		name    string
+		corrupt func(*stack[int])
+		index   int
+		problem string
+	}{
+		{"NegativeSize", func(s *stack[int]) { s.size = -1 }, -1, "size -1 is negative"},
+		{"SizeTooSmall", func(s *stack[int]) { s.size = 3 }, 3, "more nodes than the size 3"},
+		{"SizeTooLarge", func(s *stack[int]) { s.size = 7 }, -1, "5 nodes but the size is 7"},
+		{"Previous", func(s *stack[int]) { s.top.previous.previous = nil }, -1, "2 nodes but the size is 5"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			stack := new(stack[int])
+			for i := 0; i < 5; i++ {
+				stack.Push(i)
+			}
+			if err := stack.Validate(); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			test.corrupt(stack)
+			var corrupted collections.ErrCorrupted
+			if err := stack.Validate(); err == nil || !errors.As(err, &corrupted) {
+				t.Fatalf("expected error %T but got %v", corrupted, err)
+			} else if corrupted.Index != test.index || corrupted.Problem != test.problem {
+				t.Fatalf("expected corruption at index %d (%q) but got index %d (%q)", test.index, test.problem, corrupted.Index, corrupted.Problem)
+			}
+		})
+	}
+}
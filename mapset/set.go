@@ -5,13 +5,67 @@ Package mapset is a map-backed implementation of [collections.Set].
 
 All index and capacity operations are handled by the backing map, so performance should match the performance of a map of the same size.
 No order of elements is guaranteed, even between successive calls to Pop.
+
+A Set is encoded to JSON as an array of its elements.
+The order of the array is unspecified unless the Set was created with NewWithSortedJSON.
+The binary encoding never sorts the elements.
+
+Sets implement [collections.RemovingIterable].
+
+Union, Intersection, Difference and IsSubset read their arguments only through Iterator and Contains, so they accept any Set.
+If an argument is modified while its elements are being read, so that its Iterator fails with ErrConcurrentModification, they start over; any other error from an Iterator causes a panic, as it does for [collections.ToSeq].
 */
 package mapset
 
-import "github.com/bmoller/collections"
+import (
+	"cmp"
+	"encoding/json"
+	"errors"
+	"io"
+	"iter"
+	"slices"
+
+	"github.com/bmoller/collections"
+	"github.com/bmoller/collections/internal/wire"
+)
+
+type iterator[T comparable] struct {
+	elements      []T
+	index         int
+	modifications int
+	removable     bool
+	set           *set[T]
+}
+
+func (i *iterator[T]) Next() (element T, err error) {
+	if i.set.modifications != i.modifications {
+		return element, collections.ErrConcurrentModification
+	} else if i.index == len(i.elements) {
+		return element, collections.ErrNoMoreItems
+	}
+	element = i.elements[i.index]
+	i.index++
+	i.removable = true
+
+	return element, nil
+}
+
+func (i *iterator[T]) Remove() error {
+	if i.set.modifications != i.modifications {
+		return collections.ErrConcurrentModification
+	} else if !i.removable {
+		return collections.ErrNothingToRemove
+	}
+	i.set.Remove(i.elements[i.index-1])
+	i.modifications, i.removable = i.set.modifications, false
+
+	return nil
+}
 
 type set[T comparable] struct {
-	data map[T]bool
+	compare       func(a, b T) int
+	data          map[T]bool
+	modifications int
 }
 
 func New[T comparable]() collections.Set[T] {
@@ -20,8 +74,40 @@ func New[T comparable]() collections.Set[T] {
 	}
 }
 
+/*
+NewWithSortedJSON creates a Set that encodes its elements to JSON in ascending order, so that equal sets always produce the same output.
+Other operations are unaffected and still have no guaranteed order.
+*/
+func NewWithSortedJSON[T cmp.Ordered]() collections.Set[T] {
+	return &set[T]{
+		compare: cmp.Compare[T],
+		data:    make(map[T]bool),
+	}
+}
+
+// reset starts a new map ahead of decoding.
+func (s *set[T]) reset(hint int) {
+	s.data = make(map[T]bool, hint)
+	s.modifications++
+}
+
 func (s *set[T]) Add(item T) {
-	s.data[item] = true
+	if !s.data[item] {
+		s.data[item] = true
+		s.modifications++
+	}
+}
+
+func (s *set[T]) All() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		i := 0
+		for element := range s.data {
+			if !yield(i, element) {
+				return
+			}
+			i++
+		}
+	}
 }
 
 func (s *set[T]) Contains(item T) bool {
@@ -32,30 +118,32 @@ func (s *set[T]) Empty() bool {
 	return len(s.data) == 0
 }
 
+func (s *set[T]) GobDecode(data []byte) error {
+	return s.UnmarshalBinary(data)
+}
+
+func (s *set[T]) GobEncode() ([]byte, error) {
+	return s.MarshalBinary()
+}
+
 func (s *set[T]) Iterator() collections.Iterator[T] {
-	var (
-		err  error
-		next T
-		i    int
-		set  []T
-		size int = len(s.data)
-	)
-	set = make([]T, size)
+	return s.RemovingIterator().Next
+}
+
+func (s *set[T]) MarshalBinary() ([]byte, error) {
+	return wire.Marshal(wire.Set, len(s.data), s.Values())
+}
+
+func (s *set[T]) MarshalJSON() ([]byte, error) {
+	elements := make([]T, 0, len(s.data))
 	for element := range s.data {
-		set[i] = element
-		i++
+		elements = append(elements, element)
 	}
-	i = 0
-
-	return func() (T, error) {
-		if i == size {
-			err = collections.ErrNoMoreItems
-		} else {
-			next = set[i]
-			i++
-		}
-		return next, err
+	if s.compare != nil {
+		slices.SortFunc(elements, s.compare)
 	}
+
+	return json.Marshal(elements)
 }
 
 func (s *set[T]) Pop() (element T, err error) {
@@ -65,6 +153,7 @@ func (s *set[T]) Pop() (element T, err error) {
 		for key := range s.data {
 			element = key
 			delete(s.data, key)
+			s.modifications++
 			break
 		}
 	}
@@ -72,35 +161,102 @@ func (s *set[T]) Pop() (element T, err error) {
 	return
 }
 
+func (s *set[T]) ReadFrom(r io.Reader) (int64, error) {
+	return wire.Read(r, wire.Set, s.reset, s.Add)
+}
+
 func (s *set[T]) Remove(item T) {
-	delete(s.data, item)
+	if s.data[item] {
+		delete(s.data, item)
+		s.modifications++
+	}
+}
+
+func (s *set[T]) RemovingIterator() collections.RemovingIterator[T] {
+	// a map cannot be resumed part way through, so the elements are copied up front
+	elements := make([]T, 0, len(s.data))
+	for element := range s.data {
+		elements = append(elements, element)
+	}
+
+	return &iterator[T]{
+		elements:      elements,
+		modifications: s.modifications,
+		set:           s,
+	}
 }
 
 func (s *set[T]) Size() int {
 	return len(s.data)
 }
 
+func (s *set[T]) UnmarshalBinary(data []byte) error {
+	return wire.Unmarshal(data, wire.Set, s.reset, s.Add)
+}
+
+func (s *set[T]) UnmarshalJSON(data []byte) error {
+	var elements []T
+	if err := json.Unmarshal(data, &elements); err != nil {
+		return err
+	}
+
+	s.reset(len(elements))
+	for _, element := range elements {
+		s.data[element] = true
+	}
+
+	return nil
+}
+
+func (s *set[T]) Values() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for element := range s.data {
+			if !yield(element) {
+				return
+			}
+		}
+	}
+}
+
+func (s *set[T]) WriteTo(w io.Writer) (int64, error) {
+	return wire.Write(w, wire.Set, len(s.data), s.Values())
+}
+
+// visit calls f with each element of s until f returns false.
+// It returns false if s was modified before all of its elements were visited, in which case the caller should start over, and panics with any other error from the Iterator.
+func visit[T comparable](s collections.Set[T], f func(T) bool) bool {
+	itr := s.Iterator()
+	for {
+		element, err := itr()
+		if errors.Is(err, collections.ErrNoMoreItems) {
+			return true
+		} else if errors.Is(err, collections.ErrConcurrentModification) {
+			return false
+		} else if err != nil {
+			panic(err)
+		}
+		if !f(element) {
+			return true
+		}
+	}
+}
+
 /*
 Union returns the result of a set union between a and b, as a new Set.
 A union includes all elements from both parent sets.
 */
 func Union[T comparable](a, b collections.Set[T]) collections.Set[T] {
-	result := make(map[T]bool)
-	itr := a.Iterator()
-	element, err := itr()
-	for err == nil {
-		result[element] = true
-		element, err = itr()
-	}
-	itr = b.Iterator()
-	element, err = itr()
-	for err == nil {
-		result[element] = true
-		element, err = itr()
-	}
-
-	return &set[T]{
-		data: result,
+	for {
+		result := make(map[T]bool)
+		add := func(element T) bool {
+			result[element] = true
+			return true
+		}
+		if visit(a, add) && visit(b, add) {
+			return &set[T]{
+				data: result,
+			}
+		}
 	}
 }
 
@@ -109,18 +265,18 @@ Intersection returns the set intersection of a and b, as a new Set.
 An intersection includes only those items which are common to both parent sets.
 */
 func Intersection[T comparable](a, b collections.Set[T]) collections.Set[T] {
-	result := make(map[T]bool)
-	itr := a.Iterator()
-	element, err := itr()
-	for err == nil {
-		if b.Contains(element) {
-			result[element] = true
+	for {
+		result := make(map[T]bool)
+		if visit(a, func(element T) bool {
+			if b.Contains(element) {
+				result[element] = true
+			}
+			return true
+		}) {
+			return &set[T]{
+				data: result,
+			}
 		}
-		element, err = itr()
-	}
-
-	return &set[T]{
-		data: result,
 	}
 }
 
@@ -129,18 +285,18 @@ Difference determines the set difference between a and b, and returns it as a ne
 Difference includes only those elements which are unique to either parent Set.
 */
 func Difference[T comparable](a, b collections.Set[T]) collections.Set[T] {
-	result := make(map[T]bool)
-	itr := a.Iterator()
-	element, err := itr()
-	for err == nil {
-		if !b.Contains(element) {
-			result[element] = true
+	for {
+		result := make(map[T]bool)
+		if visit(a, func(element T) bool {
+			if !b.Contains(element) {
+				result[element] = true
+			}
+			return true
+		}) {
+			return &set[T]{
+				data: result,
+			}
 		}
-		element, err = itr()
-	}
-
-	return &set[T]{
-		data: result,
 	}
 }
 
@@ -149,14 +305,13 @@ IsSubset checks if Set a is a subset of Set b.
 The Set a is a subset if all of its elements are also in Set b.
 */
 func IsSubset[T comparable](a, b collections.Set[T]) bool {
-	itr := a.Iterator()
-	element, err := itr()
-	for err == nil {
-		if !b.Contains(element) {
-			return false
+	for {
+		subset := true
+		if visit(a, func(element T) bool {
+			subset = b.Contains(element)
+			return subset
+		}) {
+			return subset
 		}
-		element, err = itr()
 	}
-
-	return true
 }
@@ -3,119 +3,59 @@
 package mapset_test
 
 import (
+	"bytes"
+	"encoding"
+	"encoding/gob"
+	"encoding/json"
 	"errors"
+	"io"
 	"math/rand"
 	"testing"
 	"time"
 
 	"github.com/bmoller/collections"
+	"github.com/bmoller/collections/collectionstest"
 	"github.com/bmoller/collections/mapset"
 )
 
-func TestSetAdd1000(t *testing.T) {
-	testSet := mapset.New[int]()
-	for i := 0; i < 1000; i++ {
-		testSet.Add(i)
-	}
-
-	if testSet.Size() != 1000 {
-		t.Fatalf("expected size %d but got %d", 1000, testSet.Size())
-	}
+// modifiedSet is a Set whose first Iterators fail with ErrConcurrentModification after one element, as if it were modified during iteration.
+type modifiedSet struct {
+	collections.Set[int]
+	failures int
 }
 
-func TestSetAdd1000000(t *testing.T) {
-	testSet := mapset.New[int]()
-	for i := 0; i < 1000000; i++ {
-		testSet.Add(i)
-	}
-	if testSet.Size() != 1000000 {
-		t.Fatalf("expected size %d but got %d", 1000000, testSet.Size())
+func (s *modifiedSet) Iterator() collections.Iterator[int] {
+	itr := s.Set.Iterator()
+	if s.failures == 0 {
+		return itr
 	}
-}
+	s.failures--
+	returned := false
 
-func TestSetContains1000(t *testing.T) {
-	testSet := mapset.New[int]()
-	for i := 0; i < 1000; i++ {
-		testSet.Add(i)
-	}
-	for i := 0; i < 1000; i++ {
-		if !testSet.Contains(i) {
-			t.Fatal("set does not contain an added element")
+	return func() (int, error) {
+		if returned {
+			return 0, collections.ErrConcurrentModification
 		}
+		returned = true
+		return itr()
 	}
 }
 
-func TestSetEmpty(t *testing.T) {
-	testSet := mapset.New[int]()
-	if !testSet.Empty() {
-		t.Fatal("new mapset does not report as empty")
-	}
-	testSet.Add(0)
-	if testSet.Empty() {
-		t.Fatal("set reports as empty after adding an element")
-	}
-	for i := 1; i < 1000; i++ {
-		testSet.Add(i)
-	}
-	for i := 0; i < 1000; i++ {
-		testSet.Pop()
-	}
-	if !testSet.Empty() {
-		t.Fatal("set does not report as empty after popping all elements")
-	}
-}
-
-func TestSetIterator(t *testing.T) {
-	testSet := mapset.New[int]()
-	for i := 0; i < 1000; i++ {
-		testSet.Add(i)
-	}
-	itr := testSet.Iterator()
-	for i := 0; i < 1000; i++ {
-		if _, err := itr(); err != nil {
-			t.Fatal("expected an element but received error")
-		}
-	}
-	if _, err := itr(); err == nil || !errors.Is(err, collections.ErrNoMoreItems) {
-		t.Fatalf("exhausted iterator should return ErrNoMoreItems but got %v", err)
-	}
+func FuzzSet(f *testing.F) {
+	collectionstest.FuzzSet(f, mapset.New[int])
 }
 
-func TestSetPop(t *testing.T) {
-	testSet := mapset.New[int]()
-	for i := 0; i < 1000; i++ {
-		testSet.Add(i)
-	}
-	for i := 0; i < 1000; i++ {
-		if _, err := testSet.Pop(); err != nil {
-			t.Fatalf("failed to pop element from mapset on call %d", i)
-		}
-	}
-	if _, err := testSet.Pop(); err == nil {
-		t.Fatal("shouldn't be able to pop from an empty mapset")
-	}
+func TestSet(t *testing.T) {
+	collectionstest.TestSet(t, mapset.New[int])
 }
 
-func TestSetRemove(t *testing.T) {
+func TestSetAdd1000000(t *testing.T) {
 	testSet := mapset.New[int]()
-	for i := 0; i < 1000; i++ {
+	for i := 0; i < 1000000; i++ {
 		testSet.Add(i)
 	}
-	for i := 0; i < 1000; i++ {
-		testSet.Remove(i)
-	}
-	if testSet.Size() != 0 {
-		t.Fatal("expected empty set after removing all elements")
-	}
-}
-
-func TestSetSize(t *testing.T) {
-	testSet := mapset.New[int]()
-	for i := 1; i < 1000; i++ {
-		testSet.Add(i)
-		if testSet.Size() != i {
-			t.Fatalf("expected size %d but got size %d", i, testSet.Size())
-		}
+	if testSet.Size() != 1000000 {
+		t.Fatalf("expected size %d but got %d", 1000000, testSet.Size())
 	}
 }
 
@@ -215,6 +155,188 @@ func TestSetIsSubset(t *testing.T) {
 	}
 }
 
+func TestSetOperationsModified(t *testing.T) {
+	a, b := mapset.New[int](), mapset.New[int]()
+	for i := 0; i < 10; i++ {
+		a.Add(i)
+		b.Add(i + 5)
+	}
+	modified := func(s collections.Set[int]) collections.Set[int] {
+		return &modifiedSet{
+			Set:      s,
+			failures: 2,
+		}
+	}
+
+	if union := mapset.Union(modified(a), modified(b)); union.Size() != 15 {
+		t.Fatalf("expected union size %d but got %d", 15, union.Size())
+	}
+	if intersection := mapset.Intersection(modified(a), b); intersection.Size() != 5 {
+		t.Fatalf("expected intersection size %d but got %d", 5, intersection.Size())
+	}
+	if difference := mapset.Difference(modified(a), b); difference.Size() != 5 {
+		t.Fatalf("expected difference size %d but got %d", 5, difference.Size())
+	}
+	if mapset.IsSubset(modified(a), b) {
+		t.Fatal("expected a to not be subset of b")
+	}
+	if !mapset.IsSubset(modified(a), a) {
+		t.Fatal("expected a to be subset of itself")
+	}
+}
+
+func TestSetMarshalJSON(t *testing.T) {
+	testSet := mapset.New[int]()
+	if data, err := json.Marshal(testSet); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if string(data) != "[]" {
+		t.Fatalf("expected empty set to encode as %s but got %s", "[]", data)
+	}
+
+	for i := 0; i < 1000; i++ {
+		testSet.Add(i)
+	}
+	data, err := json.Marshal(testSet)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	var elements []int
+	if err := json.Unmarshal(data, &elements); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	seen := make(map[int]bool)
+	for _, element := range elements {
+		if !testSet.Contains(element) || seen[element] {
+			t.Fatalf("unexpected element %d in encoded set", element)
+		}
+		seen[element] = true
+	}
+	if len(seen) != 1000 {
+		t.Fatalf("expected %d encoded elements but got %d", 1000, len(seen))
+	}
+}
+
+func TestSetMarshalJSONSorted(t *testing.T) {
+	testSet := mapset.NewWithSortedJSON[string]()
+	for _, element := range []string{"pear", "apple", "orange", "banana"} {
+		testSet.Add(element)
+	}
+	for i := 0; i < 10; i++ {
+		if data, err := json.Marshal(testSet); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if string(data) != `["apple","banana","orange","pear"]` {
+			t.Fatalf("expected sorted encoding but got %s", data)
+		}
+	}
+
+	if err := json.Unmarshal([]byte(`["b","a"]`), testSet); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if data, _ := json.Marshal(testSet); string(data) != `["a","b"]` {
+		t.Fatalf("expected decoding to keep sorted encoding but got %s", data)
+	}
+}
+
+func TestSetUnmarshalJSON(t *testing.T) {
+	testSet := mapset.New[int]()
+	if err := json.Unmarshal([]byte("[0,1,2,2]"), testSet); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	testSet.Add(3)
+	if testSet.Size() != 4 {
+		t.Fatalf("expected size %d but got %d", 4, testSet.Size())
+	}
+	for i := 0; i < 4; i++ {
+		if !testSet.Contains(i) {
+			t.Fatalf("set does not contain decoded element %d", i)
+		}
+	}
+
+	if err := json.Unmarshal([]byte(`{"a":1}`), testSet); err == nil {
+		t.Fatal("expected error from decoding an object")
+	} else if testSet.Size() != 4 {
+		t.Fatalf("expected failed decode to leave size %d but got %d", 4, testSet.Size())
+	}
+
+	payload := struct {
+		Items collections.Set[int]
+	}{
+		Items: mapset.New[int](),
+	}
+	payload.Items.Add(99)
+	if err := json.Unmarshal([]byte(`{"Items":[5]}`), &payload); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if payload.Items.Size() != 1 || !payload.Items.Contains(5) {
+		t.Fatal("expected decoding to replace the contents of the set")
+	}
+}
+
+func TestSetBinaryEncoding(t *testing.T) {
+	testSet := mapset.NewWithSortedJSON[int]()
+	for i := 0; i < 1000; i++ {
+		testSet.Add(i)
+	}
+
+	data, err := testSet.(encoding.BinaryMarshaler).MarshalBinary()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	decoded := mapset.New[int]()
+	if err := decoded.(encoding.BinaryUnmarshaler).UnmarshalBinary(data); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if decoded.Size() != 1000 || !mapset.IsSubset(testSet, decoded) {
+		t.Fatal("decoded set does not match the original")
+	}
+	decoded.Add(1000)
+
+	if err := decoded.(encoding.BinaryUnmarshaler).UnmarshalBinary([]byte("not a set")); err == nil || !errors.Is(err, collections.ErrInvalidEncoding) {
+		t.Fatalf("expected ErrInvalidEncoding from invalid data but got: %v", err)
+	}
+}
+
+func TestSetGob(t *testing.T) {
+	testSet := mapset.New[string]()
+	for _, element := range []string{"a", "b", "c"} {
+		testSet.Add(element)
+	}
+
+	var buffer bytes.Buffer
+	if err := gob.NewEncoder(&buffer).Encode(testSet); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	decoded := mapset.New[string]()
+	if err := gob.NewDecoder(&buffer).Decode(decoded); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if decoded.Size() != 3 || !mapset.IsSubset(testSet, decoded) {
+		t.Fatal("decoded set does not match the original")
+	}
+}
+
+func TestSetWriteTo(t *testing.T) {
+	testSet := mapset.New[int]()
+	for i := 0; i < 1000; i++ {
+		testSet.Add(i)
+	}
+
+	var buffer bytes.Buffer
+	written, err := testSet.(io.WriterTo).WriteTo(&buffer)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if written != int64(buffer.Len()) {
+		t.Fatalf("expected WriteTo to report %d bytes but got %d", buffer.Len(), written)
+	}
+
+	decoded := mapset.New[int]()
+	decoded.Add(-1)
+	if read, err := decoded.(io.ReaderFrom).ReadFrom(&buffer); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if read != written {
+		t.Fatalf("expected ReadFrom to report %d bytes but got %d", written, read)
+	} else if decoded.Size() != 1000 || !mapset.IsSubset(testSet, decoded) {
+		t.Fatal("decoded set does not match the original")
+	}
+}
+
 // benchmarks
 
 func BenchmarkAddRandInt1000(b *testing.B) {
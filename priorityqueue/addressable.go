@@ -0,0 +1,248 @@
+// ©2022 Brandon Moller
+
+package priorityqueue
+
+import (
+	"iter"
+	"slices"
+
+	"github.com/bmoller/collections"
+)
+
+type addressableIterator[T comparable] struct {
+	last          *handle[T]
+	modifications int
+	queue         *addressableQueue[T]
+	snapshot      *queue[*handle[T]]
+}
+
+func (i *addressableIterator[T]) Next() (element T, err error) {
+	if i.queue.modifications != i.modifications {
+		return element, collections.ErrConcurrentModification
+	} else if i.snapshot.Empty() {
+		return element, collections.ErrNoMoreItems
+	}
+	i.last, _ = i.snapshot.Pop()
+
+	return i.last.value, nil
+}
+
+func (i *addressableIterator[T]) Remove() error {
+	q := i.queue
+	if q.modifications != i.modifications {
+		return collections.ErrConcurrentModification
+	} else if i.last == nil {
+		return collections.ErrNothingToRemove
+	}
+	q.remove(i.last.index)
+	i.last, i.modifications = nil, q.modifications
+
+	return nil
+}
+
+type handle[T comparable] struct {
+	elementOf *addressableQueue[T]
+	index     int
+	value     T
+}
+
+func (h *handle[T]) Value() T {
+	return h.value
+}
+
+type addressableQueue[T comparable] struct {
+	data          []*handle[T]
+	less          func(a, b T) bool
+	modifications int
+}
+
+/*
+NewAddressable creates an empty [collections.AddressableQueue] ordered by less.
+The less function should report whether a has a higher priority than b.
+
+Push, Pop, Remove and Update all run in O(log n) time; Contains and Peek run in O(1).
+A handle is invalidated when its element leaves the queue, after which methods called with it return [collections.ErrHandleIsNotElement].
+*/
+func NewAddressable[T comparable](less func(a, b T) bool) collections.AddressableQueue[T] {
+	return &addressableQueue[T]{
+		less: less,
+	}
+}
+
+func (q *addressableQueue[T]) down(i int) bool {
+	n, start := len(q.data), i
+	for {
+		first := i
+		if left := 2*i + 1; left < n && q.less(q.data[left].value, q.data[first].value) {
+			first = left
+		}
+		if right := 2*i + 2; right < n && q.less(q.data[right].value, q.data[first].value) {
+			first = right
+		}
+		if first == i {
+			return i != start
+		}
+		q.swap(i, first)
+		i = first
+	}
+}
+
+// member checks that h belongs to q, returning its concrete type if so.
+func (q *addressableQueue[T]) member(h collections.QueueHandle[T]) (*handle[T], error) {
+	typedHandle, ok := h.(*handle[T])
+	if !ok {
+		return nil, collections.ErrWrongHandleType
+	} else if typedHandle.elementOf != q {
+		return nil, collections.ErrHandleIsNotElement
+	}
+
+	return typedHandle, nil
+}
+
+// remove takes the element at index i out of the heap and invalidates its handle.
+func (q *addressableQueue[T]) remove(i int) *handle[T] {
+	last := len(q.data) - 1
+	removed := q.data[i]
+	if i != last {
+		q.swap(i, last)
+	}
+	q.data[last] = nil
+	q.data = q.data[:last]
+	if i != last && !q.down(i) {
+		q.up(i)
+	}
+	removed.elementOf = nil
+	removed.index = -1
+	q.modifications++
+
+	return removed
+}
+
+// snapshot returns a plain queue of the current values to be drained by iterators.
+// The backing slice is already in heap order, so no further arrangement is needed.
+func (q *addressableQueue[T]) snapshot() *queue[T] {
+	data := make([]T, len(q.data))
+	for i, h := range q.data {
+		data[i] = h.value
+	}
+
+	return &queue[T]{
+		data: data,
+		less: q.less,
+	}
+}
+
+func (q *addressableQueue[T]) swap(i, j int) {
+	q.data[i], q.data[j] = q.data[j], q.data[i]
+	q.data[i].index = i
+	q.data[j].index = j
+}
+
+func (q *addressableQueue[T]) up(i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !q.less(q.data[i].value, q.data[parent].value) {
+			return
+		}
+		q.swap(i, parent)
+		i = parent
+	}
+}
+
+func (q *addressableQueue[T]) All() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		q.snapshot().All()(yield)
+	}
+}
+
+func (q *addressableQueue[T]) Contains(h collections.QueueHandle[T]) bool {
+	_, err := q.member(h)
+	return err == nil
+}
+
+func (q *addressableQueue[T]) Empty() bool {
+	return len(q.data) == 0
+}
+
+func (q *addressableQueue[T]) Iterator() collections.Iterator[T] {
+	return q.RemovingIterator().Next
+}
+
+func (q *addressableQueue[T]) Peek() (element T, err error) {
+	if len(q.data) == 0 {
+		return element, collections.ErrEmptyQueue
+	}
+
+	return q.data[0].value, nil
+}
+
+func (q *addressableQueue[T]) Pop() (element T, err error) {
+	if len(q.data) == 0 {
+		return element, collections.ErrEmptyQueue
+	}
+
+	return q.remove(0).value, nil
+}
+
+func (q *addressableQueue[T]) Push(item T) collections.QueueHandle[T] {
+	h := &handle[T]{
+		elementOf: q,
+		index:     len(q.data),
+		value:     item,
+	}
+	q.data = append(q.data, h)
+	q.up(h.index)
+	q.modifications++
+
+	return h
+}
+
+func (q *addressableQueue[T]) Remove(h collections.QueueHandle[T]) (element T, err error) {
+	typedHandle, err := q.member(h)
+	if err != nil {
+		return element, err
+	}
+
+	return q.remove(typedHandle.index).value, nil
+}
+
+func (q *addressableQueue[T]) RemovingIterator() collections.RemovingIterator[T] {
+	// the handles are drained from a copy of the heap, so that removing one finds its place in the queue directly
+	handles := &queue[*handle[T]]{
+		data: slices.Clone(q.data),
+		less: func(a, b *handle[T]) bool {
+			return q.less(a.value, b.value)
+		},
+	}
+
+	return &addressableIterator[T]{
+		modifications: q.modifications,
+		queue:         q,
+		snapshot:      handles,
+	}
+}
+
+func (q *addressableQueue[T]) Size() int {
+	return len(q.data)
+}
+
+func (q *addressableQueue[T]) Update(h collections.QueueHandle[T], item T) error {
+	typedHandle, err := q.member(h)
+	if err != nil {
+		return err
+	}
+
+	typedHandle.value = item
+	if !q.down(typedHandle.index) {
+		q.up(typedHandle.index)
+	}
+	q.modifications++
+
+	return nil
+}
+
+func (q *addressableQueue[T]) Values() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		q.snapshot().Values()(yield)
+	}
+}
@@ -0,0 +1,307 @@
+// ©2022 Brandon Moller
+
+package priorityqueue_test
+
+import (
+	"errors"
+	"math/rand"
+	"slices"
+	"testing"
+
+	"github.com/bmoller/collections"
+	"github.com/bmoller/collections/collectionstest"
+	"github.com/bmoller/collections/priorityqueue"
+)
+
+type badHandle[T comparable] struct {
+	value T
+}
+
+func (b *badHandle[T]) Value() T {
+	return b.value
+}
+
+func FuzzAddressable(f *testing.F) {
+	collectionstest.FuzzAddressableQueue(f, func() collections.AddressableQueue[int] { return priorityqueue.NewAddressable(less) })
+}
+
+func TestAddressableAll(t *testing.T) {
+	queue := priorityqueue.NewAddressable(less)
+	for _, value := range rand.Perm(1000) {
+		queue.Push(value)
+	}
+
+	expected := 0
+	for i, element := range queue.All() {
+		if i != expected {
+			t.Fatalf("expected index %d but got %d", expected, i)
+		} else if element != i {
+			t.Fatalf("expected element with value %d but got %d", i, element)
+		}
+		expected++
+	}
+	if expected != 1000 {
+		t.Fatalf("expected %d elements from All but got %d", 1000, expected)
+	}
+	if queue.Size() != 1000 {
+		t.Fatalf("expected iteration to leave queue size %d but got %d", 1000, queue.Size())
+	}
+}
+
+func TestAddressableContains(t *testing.T) {
+	queue := priorityqueue.NewAddressable(less)
+	handles := make([]collections.QueueHandle[int], 100)
+	for i := range handles {
+		handles[i] = queue.Push(i)
+	}
+	for _, h := range handles {
+		if !queue.Contains(h) {
+			t.Fatalf("expected queue to contain handle with value %d", h.Value())
+		}
+	}
+
+	queue.Pop()
+	if queue.Contains(handles[0]) {
+		t.Fatal("expected popped handle not to be contained")
+	}
+	if _, err := queue.Remove(handles[50]); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if queue.Contains(handles[50]) {
+		t.Fatal("expected removed handle not to be contained")
+	}
+
+	other := priorityqueue.NewAddressable(less)
+	if queue.Contains(other.Push(0)) {
+		t.Fatal("expected handle from another queue not to be contained")
+	}
+	if queue.Contains(&badHandle[int]{}) {
+		t.Fatal("expected handle of wrong type not to be contained")
+	}
+}
+
+func TestAddressableEmpty(t *testing.T) {
+	queue := priorityqueue.NewAddressable(less)
+	if !queue.Empty() {
+		t.Fatal("expected new queue to be empty")
+	}
+	queue.Push(1)
+	if queue.Empty() {
+		t.Fatal("expected queue to not be empty after pushing an item")
+	}
+}
+
+func TestAddressableIterator(t *testing.T) {
+	queue := priorityqueue.NewAddressable(less)
+	for _, value := range rand.Perm(1000) {
+		queue.Push(value)
+	}
+
+	itr := queue.Iterator()
+	for i := 0; i < 1000; i++ {
+		if element, err := itr(); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if element != i {
+			t.Fatalf("expected element with value %d but got %d", i, element)
+		}
+	}
+	if _, err := itr(); err == nil || !errors.Is(err, collections.ErrNoMoreItems) {
+		t.Fatalf("exhausted iterator should return ErrNoMoreItems but got %v", err)
+	}
+
+	handle, updated := queue.Push(1000), queue.Push(2000)
+	itr = queue.Iterator()
+	queue.Contains(handle)
+	queue.Peek()
+	queue.Remove(&badHandle[int]{})
+	queue.Update(&badHandle[int]{}, 0)
+	if element, err := itr(); err != nil {
+		t.Fatalf("unexpected error after calls that do not modify the queue: %s", err)
+	} else if element != 0 {
+		t.Fatalf("expected element with value %d but got %d", 0, element)
+	}
+	for method, modify := range map[string]func(){
+		"Pop":    func() { queue.Pop() },
+		"Push":   func() { handle = queue.Push(1000) },
+		"Remove": func() { queue.Remove(handle) },
+		"Update": func() { queue.Update(updated, 1001) },
+	} {
+		itr = queue.Iterator()
+		modify()
+		if _, err := itr(); err == nil || !errors.Is(err, collections.ErrConcurrentModification) {
+			t.Fatalf("expected ErrConcurrentModification from Iterator after %s but got: %v", method, err)
+		}
+	}
+}
+
+func TestAddressablePeek(t *testing.T) {
+	queue := priorityqueue.NewAddressable(less)
+	if _, err := queue.Peek(); err == nil || !errors.Is(err, collections.ErrEmptyQueue) {
+		t.Fatalf("expected ErrEmptyQueue from Peek on new queue but got: %v", err)
+	}
+
+	for i := 999; i > -1; i-- {
+		queue.Push(i)
+		if element, err := queue.Peek(); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if element != i {
+			t.Fatalf("expected element with value %d but got %d", i, element)
+		}
+	}
+}
+
+func TestAddressablePop(t *testing.T) {
+	queue := priorityqueue.NewAddressable(less)
+	for _, value := range rand.Perm(1000) {
+		queue.Push(value)
+	}
+	for i := 0; i < 1000; i++ {
+		if element, err := queue.Pop(); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if element != i {
+			t.Fatalf("expected element with value %d but got %d", i, element)
+		}
+	}
+	if _, err := queue.Pop(); err == nil || !errors.Is(err, collections.ErrEmptyQueue) {
+		t.Fatalf("expected ErrEmptyQueue from Pop on empty queue but got: %v", err)
+	}
+}
+
+func TestAddressableRemove(t *testing.T) {
+	queue := priorityqueue.NewAddressable(less)
+	handles := make([]collections.QueueHandle[int], 1000)
+	for i, value := range rand.Perm(1000) {
+		handles[i] = queue.Push(value)
+	}
+
+	removed := make(map[int]bool)
+	for _, i := range rand.Perm(1000)[:500] {
+		if element, err := queue.Remove(handles[i]); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if element != handles[i].Value() {
+			t.Fatalf("expected element with value %d but got %d", handles[i].Value(), element)
+		}
+		removed[handles[i].Value()] = true
+	}
+	if queue.Size() != 500 {
+		t.Fatalf("expected queue size %d but got %d", 500, queue.Size())
+	}
+
+	previous := -1
+	for !queue.Empty() {
+		element, _ := queue.Pop()
+		if removed[element] {
+			t.Fatalf("removed element %d was returned by Pop", element)
+		} else if element <= previous {
+			t.Fatalf("expected element greater than %d but got %d", previous, element)
+		}
+		previous = element
+	}
+
+	if _, err := queue.Remove(handles[0]); err == nil || !errors.Is(err, collections.ErrHandleIsNotElement) {
+		t.Fatalf("expected ErrHandleIsNotElement from Remove with stale handle but got: %v", err)
+	}
+	other := priorityqueue.NewAddressable(less)
+	if _, err := queue.Remove(other.Push(0)); err == nil || !errors.Is(err, collections.ErrHandleIsNotElement) {
+		t.Fatalf("expected ErrHandleIsNotElement from Remove with foreign handle but got: %v", err)
+	}
+	if _, err := queue.Remove(&badHandle[int]{}); err == nil || !errors.Is(err, collections.ErrWrongHandleType) {
+		t.Fatalf("expected ErrWrongHandleType from Remove with bad handle type but got: %v", err)
+	}
+}
+
+func TestAddressableRemovingIterator(t *testing.T) {
+	queue := priorityqueue.NewAddressable(less)
+	handles := make([]collections.QueueHandle[int], 1000)
+	for _, value := range rand.Perm(1000) {
+		handles[value] = queue.Push(value)
+	}
+
+	itr := queue.(collections.RemovingIterable[int]).RemovingIterator()
+	for element, err := itr.Next(); err == nil; element, err = itr.Next() {
+		if element%2 == 0 {
+			continue
+		}
+		if err := itr.Remove(); err != nil {
+			t.Fatalf("unexpected error removing %d: %s", element, err)
+		}
+	}
+	if queue.Size() != 500 {
+		t.Fatalf("expected queue size %d but got %d", 500, queue.Size())
+	}
+	for i, h := range handles {
+		if contained := queue.Contains(h); contained != (i%2 == 0) {
+			t.Fatalf("expected Contains to report %t for handle with value %d but got %t", i%2 == 0, i, contained)
+		}
+	}
+	for i := 0; i < 1000; i += 2 {
+		if element, err := queue.Pop(); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if element != i {
+			t.Fatalf("expected element with value %d but got %d", i, element)
+		}
+	}
+}
+
+func TestAddressableSize(t *testing.T) {
+	queue := priorityqueue.NewAddressable(less)
+	for i := 1; i < 1001; i++ {
+		queue.Push(rand.Int())
+		if queue.Size() != i {
+			t.Fatalf("expected queue size %d but got %d", i, queue.Size())
+		}
+	}
+}
+
+func TestAddressableUpdate(t *testing.T) {
+	queue := priorityqueue.NewAddressable(less)
+	handles := make([]collections.QueueHandle[int], 1000)
+	values := make([]int, 1000)
+	for i := range handles {
+		values[i] = rand.Intn(10000)
+		handles[i] = queue.Push(values[i])
+	}
+	for i := 0; i < 1000; i++ {
+		j := rand.Intn(1000)
+		values[j] = rand.Intn(10000)
+		if err := queue.Update(handles[j], values[j]); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if handles[j].Value() != values[j] {
+			t.Fatalf("expected handle with value %d but got %d", values[j], handles[j].Value())
+		}
+	}
+
+	slices.Sort(values)
+	for _, value := range values {
+		if element, err := queue.Pop(); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if element != value {
+			t.Fatalf("expected element with value %d but got %d", value, element)
+		}
+	}
+
+	if err := queue.Update(handles[0], 0); err == nil || !errors.Is(err, collections.ErrHandleIsNotElement) {
+		t.Fatalf("expected ErrHandleIsNotElement from Update with stale handle but got: %v", err)
+	}
+	if err := queue.Update(&badHandle[int]{}, 0); err == nil || !errors.Is(err, collections.ErrWrongHandleType) {
+		t.Fatalf("expected ErrWrongHandleType from Update with bad handle type but got: %v", err)
+	}
+}
+
+func TestAddressableValues(t *testing.T) {
+	queue := priorityqueue.NewAddressable(less)
+	for _, value := range rand.Perm(1000) {
+		queue.Push(value)
+	}
+
+	expected := 0
+	for element := range queue.Values() {
+		if element != expected {
+			t.Fatalf("expected element with value %d but got %d", expected, element)
+		}
+		expected++
+	}
+	if expected != 1000 {
+		t.Fatalf("expected %d elements from Values but got %d", 1000, expected)
+	}
+}
@@ -0,0 +1,215 @@
+// ©2022 Brandon Moller
+
+/*
+Package priorityqueue provides an implementation of [collections.Queue] that returns elements in priority order rather than insertion order.
+
+Priority is determined by a less function supplied when the queue is created; the element for which less reports true against every other element is returned first.
+Elements are stored in a slice-backed binary heap, so Push and Pop run in O(log n) time and Peek runs in O(1).
+Elements of equal priority are not guaranteed to be returned in the order in which they were added.
+
+NewAddressable creates a [collections.AddressableQueue] on the same kind of heap, returning a handle from each Push that can later be used to update or remove its element.
+
+Iterators produce elements in the order in which Pop would return them, without modifying the queue.
+Each step of an iteration costs O(log n) against a private copy of the heap, taken when iteration begins.
+
+Queues implement [collections.RemovingIterable].
+A Queue from New or NewFromItems finds the element to remove by searching the heap in O(n) time, while one from NewAddressable removes it through its handle in O(log n) time.
+*/
+package priorityqueue
+
+import (
+	"iter"
+	"slices"
+
+	"github.com/bmoller/collections"
+)
+
+type iterator[T comparable] struct {
+	last          T
+	modifications int
+	queue         *queue[T]
+	removable     bool
+	snapshot      *queue[T]
+}
+
+func (i *iterator[T]) Next() (element T, err error) {
+	if i.queue.modifications != i.modifications {
+		return element, collections.ErrConcurrentModification
+	} else if i.snapshot.Empty() {
+		return element, collections.ErrNoMoreItems
+	}
+	i.last, _ = i.snapshot.Pop()
+	i.removable = true
+
+	return i.last, nil
+}
+
+func (i *iterator[T]) Remove() error {
+	q := i.queue
+	if q.modifications != i.modifications {
+		return collections.ErrConcurrentModification
+	} else if !i.removable {
+		return collections.ErrNothingToRemove
+	}
+	// any element equal to the last is as good as another, so the first found is removed
+	q.remove(slices.Index(q.data, i.last))
+	i.modifications, i.removable = q.modifications, false
+
+	return nil
+}
+
+type queue[T comparable] struct {
+	data          []T
+	less          func(a, b T) bool
+	modifications int
+}
+
+/*
+New creates an empty Queue ordered by less.
+The less function should report whether a has a higher priority than b.
+*/
+func New[T comparable](less func(a, b T) bool) collections.Queue[T] {
+	return &queue[T]{
+		less: less,
+	}
+}
+
+/*
+NewFromItems creates a new Queue ordered by less, with all elements of items as its contents.
+The elements are copied and arranged into a heap in O(n) time; items itself is not modified.
+*/
+func NewFromItems[T comparable](items []T, less func(a, b T) bool) collections.Queue[T] {
+	q := &queue[T]{
+		data: make([]T, len(items)),
+		less: less,
+	}
+	copy(q.data, items)
+	for i := len(q.data)/2 - 1; i >= 0; i-- {
+		q.down(i)
+	}
+
+	return q
+}
+
+func (q *queue[T]) down(i int) {
+	n := len(q.data)
+	for {
+		first := i
+		if left := 2*i + 1; left < n && q.less(q.data[left], q.data[first]) {
+			first = left
+		}
+		if right := 2*i + 2; right < n && q.less(q.data[right], q.data[first]) {
+			first = right
+		}
+		if first == i {
+			return
+		}
+		q.data[i], q.data[first] = q.data[first], q.data[i]
+		i = first
+	}
+}
+
+func (q *queue[T]) up(i int) {
+	for i > 0 {
+		parent := (i - 1) / 2
+		if !q.less(q.data[i], q.data[parent]) {
+			return
+		}
+		q.data[i], q.data[parent] = q.data[parent], q.data[i]
+		i = parent
+	}
+}
+
+// remove takes the element at index i out of the heap.
+func (q *queue[T]) remove(i int) {
+	var zero T
+	last := len(q.data) - 1
+	q.data[i] = q.data[last]
+	q.data[last] = zero
+	q.data = q.data[:last]
+	if i != last {
+		q.down(i)
+		q.up(i)
+	}
+	q.modifications++
+}
+
+// snapshot returns a copy of the queue to be drained by iterators.
+func (q *queue[T]) snapshot() *queue[T] {
+	data := make([]T, len(q.data))
+	copy(data, q.data)
+
+	return &queue[T]{
+		data: data,
+		less: q.less,
+	}
+}
+
+func (q *queue[T]) All() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		s := q.snapshot()
+		for i := 0; !s.Empty(); i++ {
+			element, _ := s.Pop()
+			if !yield(i, element) {
+				return
+			}
+		}
+	}
+}
+
+func (q *queue[T]) Empty() bool {
+	return len(q.data) == 0
+}
+
+func (q *queue[T]) Iterator() collections.Iterator[T] {
+	return q.RemovingIterator().Next
+}
+
+func (q *queue[T]) Peek() (element T, err error) {
+	if len(q.data) == 0 {
+		return element, collections.ErrEmptyQueue
+	}
+
+	return q.data[0], nil
+}
+
+func (q *queue[T]) Pop() (element T, err error) {
+	if len(q.data) == 0 {
+		return element, collections.ErrEmptyQueue
+	}
+
+	element = q.data[0]
+	q.remove(0)
+
+	return element, nil
+}
+
+func (q *queue[T]) Push(item T) {
+	q.data = append(q.data, item)
+	q.up(len(q.data) - 1)
+	q.modifications++
+}
+
+func (q *queue[T]) RemovingIterator() collections.RemovingIterator[T] {
+	return &iterator[T]{
+		modifications: q.modifications,
+		queue:         q,
+		snapshot:      q.snapshot(),
+	}
+}
+
+func (q *queue[T]) Size() int {
+	return len(q.data)
+}
+
+func (q *queue[T]) Values() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		s := q.snapshot()
+		for !s.Empty() {
+			element, _ := s.Pop()
+			if !yield(element) {
+				return
+			}
+		}
+	}
+}
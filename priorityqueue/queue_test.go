@@ -0,0 +1,189 @@
+// ©2022 Brandon Moller
+
+package priorityqueue_test
+
+import (
+	"errors"
+	"math/rand"
+	"os"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/bmoller/collections"
+	"github.com/bmoller/collections/collectionstest"
+	"github.com/bmoller/collections/priorityqueue"
+)
+
+func less(a, b int) bool {
+	return a < b
+}
+
+func FuzzQueue(f *testing.F) {
+	collectionstest.FuzzPriorityQueue(f, func() collections.Queue[int] { return priorityqueue.New(less) })
+}
+
+func TestMain(m *testing.M) {
+	rand.Seed(time.Now().UTC().UnixNano())
+	os.Exit(m.Run())
+}
+
+// the conformance tests push elements in ascending order, so a queue favouring lower values returns them first in, first out
+func TestQueue(t *testing.T) {
+	collectionstest.TestQueue(t, func() collections.Queue[int] { return priorityqueue.New(less) })
+}
+
+func TestQueueNewFromItems(t *testing.T) {
+	input := rand.Perm(1000)
+	original := slices.Clone(input)
+
+	queue := priorityqueue.NewFromItems(input, less)
+	if queue.Size() != 1000 {
+		t.Fatalf("expected queue size %d but got %d", 1000, queue.Size())
+	}
+	if !slices.Equal(input, original) {
+		t.Fatal("expected NewFromItems not to modify its input")
+	}
+	for i := 0; i < 1000; i++ {
+		if element, err := queue.Pop(); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if element != i {
+			t.Fatalf("expected element with value %d but got %d", i, element)
+		}
+	}
+
+	queue = priorityqueue.NewFromItems([]int{}, less)
+	if !queue.Empty() {
+		t.Fatal("expected queue from no items to be empty")
+	}
+}
+
+func TestQueueAll(t *testing.T) {
+	queue := priorityqueue.New(less)
+	for _, value := range rand.Perm(1000) {
+		queue.Push(value)
+	}
+
+	expected := 0
+	for i, element := range queue.All() {
+		if i != expected {
+			t.Fatalf("expected index %d but got %d", expected, i)
+		} else if element != i {
+			t.Fatalf("expected element with value %d but got %d", i, element)
+		}
+		expected++
+	}
+	if expected != 1000 {
+		t.Fatalf("expected %d elements from All but got %d", 1000, expected)
+	}
+	if queue.Size() != 1000 {
+		t.Fatalf("expected iteration to leave queue size %d but got %d", 1000, queue.Size())
+	}
+
+	for i := range queue.All() {
+		if i == 10 {
+			break
+		}
+	}
+}
+
+func TestQueueIterator(t *testing.T) {
+	queue := priorityqueue.New(less)
+	if _, err := queue.Iterator()(); err == nil || !errors.Is(err, collections.ErrNoMoreItems) {
+		t.Fatalf("expected ErrNoMoreItems from Iterator on empty queue but got: %v", err)
+	}
+
+	for _, value := range rand.Perm(1000) {
+		queue.Push(value)
+	}
+	itr := queue.Iterator()
+	for i := 0; i < 1000; i++ {
+		if element, err := itr(); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if element != i {
+			t.Fatalf("expected element with value %d but got %d", i, element)
+		}
+	}
+	if _, err := itr(); err == nil || !errors.Is(err, collections.ErrNoMoreItems) {
+		t.Fatalf("exhausted iterator should return ErrNoMoreItems but got %v", err)
+	}
+}
+
+func TestQueuePeek(t *testing.T) {
+	queue := priorityqueue.New(less)
+	if _, err := queue.Peek(); err == nil {
+		t.Fatal("expected error from Peek on new queue")
+	} else if !errors.Is(err, collections.ErrEmptyQueue) {
+		t.Fatalf("expected ErrEmptyQueue but got: %s", err)
+	}
+
+	for i := 999; i > -1; i-- {
+		queue.Push(i)
+		if element, err := queue.Peek(); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if element != i {
+			t.Fatalf("expected element with value %d but got %d", i, element)
+		}
+	}
+}
+
+func TestQueuePop(t *testing.T) {
+	queue := priorityqueue.New(func(a, b int) bool { return a > b })
+	values := make([]int, 1000)
+	for i := range values {
+		values[i] = rand.Intn(100)
+		queue.Push(values[i])
+	}
+	slices.Sort(values)
+	slices.Reverse(values)
+
+	for _, value := range values {
+		if element, err := queue.Pop(); err != nil {
+			t.Fatalf("expected element from pop but received error: %s", err)
+		} else if element != value {
+			t.Fatalf("expected element %d but got %d", value, element)
+		}
+	}
+	if _, err := queue.Pop(); err == nil || !errors.Is(err, collections.ErrEmptyQueue) {
+		t.Fatalf("expected ErrEmptyQueue from Pop on empty queue but got: %s", err)
+	}
+}
+
+func TestQueueValues(t *testing.T) {
+	queue := priorityqueue.New(less)
+	for _, value := range rand.Perm(1000) {
+		queue.Push(value)
+	}
+
+	expected := 0
+	for element := range queue.Values() {
+		if element != expected {
+			t.Fatalf("expected element with value %d but got %d", expected, element)
+		}
+		expected++
+	}
+	if expected != 1000 {
+		t.Fatalf("expected %d elements from Values but got %d", 1000, expected)
+	}
+
+	for element := range queue.Values() {
+		if element == 10 {
+			break
+		}
+	}
+}
+
+// benchmarks
+
+func BenchmarkPushPop1000(b *testing.B) {
+	queue := priorityqueue.New(less)
+
+	for i := 0; i < b.N; i++ {
+		for j := 0; j < 1000; j++ {
+			queue.Push(rand.Int())
+		}
+		for j := 0; j < 1000; j++ {
+			queue.Pop()
+		}
+	}
+}
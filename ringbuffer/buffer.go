@@ -0,0 +1,271 @@
+// ©2022 Brandon Moller
+
+/*
+Package ringbuffer provides a fixed-capacity circular buffer that satisfies [collections.Queue].
+
+Elements are stored in a slice of the requested capacity, which is never reallocated.
+Pop and Peek return the oldest element, Get indexes from the oldest element, and iterators and sequences run from the oldest element to the newest.
+What happens when an element is pushed into a full Buffer is decided by its Policy.
+
+A Buffer is safe for concurrent use.
+Iterators and sequences work on a snapshot of the elements taken when they start.
+Buffers implement [collections.RemovingIterable]; each removal through a RemovingIterator shifts the newer elements towards the oldest by one.
+*/
+package ringbuffer
+
+import (
+	"iter"
+	"sync"
+	"sync/atomic"
+
+	"github.com/bmoller/collections"
+)
+
+/*
+A Policy decides what a full Buffer does with a new element.
+*/
+type Policy int
+
+const (
+	// Overwrite discards the oldest element to make room for the new one; it is used for any unknown Policy.
+	Overwrite Policy = iota
+	// Reject discards the new element; TryPush reports this with ErrQueueFull.
+	Reject
+	// Block waits until another goroutine removes an element.
+	Block
+)
+
+/*
+A Buffer is a Queue with a fixed capacity.
+Get returns the element at the given position counting from the oldest element, which is at index 0.
+TryPush behaves as Push does, but also reports whether the element was added.
+*/
+type Buffer[T comparable] interface {
+	collections.Queue[T]
+
+	Cap() int
+	Full() bool
+	Get(int) (T, error)
+	TryPush(T) error
+}
+
+type iterator[T comparable] struct {
+	buffer        *buffer[T]
+	elements      []T
+	index         int
+	modifications uint64
+	removable     bool
+	removed       int // the number of elements before index that have been removed from the buffer
+}
+
+func (i *iterator[T]) Next() (element T, err error) {
+	if i.buffer.modifications.Load() != i.modifications {
+		return element, collections.ErrConcurrentModification
+	} else if i.index == len(i.elements) {
+		return element, collections.ErrNoMoreItems
+	}
+	element = i.elements[i.index]
+	i.index++
+	i.removable = true
+
+	return element, nil
+}
+
+func (i *iterator[T]) Remove() error {
+	b := i.buffer
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.modifications.Load() != i.modifications {
+		return collections.ErrConcurrentModification
+	} else if !i.removable {
+		return collections.ErrNothingToRemove
+	}
+	for j := i.index - 1 - i.removed; j < b.size-1; j++ {
+		b.data[b.index(j)] = b.data[b.index(j+1)]
+	}
+	var zero T
+	b.data[b.index(b.size-1)] = zero
+	b.size--
+	b.notFull.Signal()
+	i.modifications, i.removable = b.modifications.Add(1), false
+	i.removed++
+
+	return nil
+}
+
+type buffer[T comparable] struct {
+	data          []T
+	head          int
+	lock          sync.Mutex
+	modifications atomic.Uint64 // only changed with lock held, but read without it
+	notFull       *sync.Cond
+	policy        Policy
+	size          int
+}
+
+/*
+New creates a Buffer that holds at most capacity elements and handles overflow according to policy.
+The capacity is at least 1.
+*/
+func New[T comparable](capacity int, policy Policy) Buffer[T] {
+	if capacity < 1 {
+		capacity = 1
+	}
+
+	b := &buffer[T]{
+		data:   make([]T, capacity),
+		policy: policy,
+	}
+	b.notFull = sync.NewCond(&b.lock)
+
+	return b
+}
+
+// index translates a position relative to the oldest element into an index of the backing slice.
+func (b *buffer[T]) index(i int) int {
+	return (b.head + i) % len(b.data)
+}
+
+func (b *buffer[T]) snapshot() []T {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	elements := make([]T, b.size)
+	for i := range elements {
+		elements[i] = b.data[b.index(i)]
+	}
+
+	return elements
+}
+
+func (b *buffer[T]) All() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for i, element := range b.snapshot() {
+			if !yield(i, element) {
+				return
+			}
+		}
+	}
+}
+
+func (b *buffer[T]) Cap() int {
+	return len(b.data)
+}
+
+func (b *buffer[T]) Empty() bool {
+	return b.Size() == 0
+}
+
+func (b *buffer[T]) Full() bool {
+	return b.Size() == len(b.data)
+}
+
+func (b *buffer[T]) Get(index int) (element T, err error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.size == 0 {
+		return element, collections.ErrEmptyQueue
+	} else if index < 0 || index >= b.size {
+		return element, collections.ErrIndexOutOfRange{
+			Index: index,
+			Size:  b.size,
+		}
+	}
+
+	return b.data[b.index(index)], nil
+}
+
+func (b *buffer[T]) Iterator() collections.Iterator[T] {
+	return b.RemovingIterator().Next
+}
+
+func (b *buffer[T]) Peek() (element T, err error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.size == 0 {
+		return element, collections.ErrEmptyQueue
+	}
+
+	return b.data[b.head], nil
+}
+
+func (b *buffer[T]) Pop() (element T, err error) {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.size == 0 {
+		return element, collections.ErrEmptyQueue
+	}
+
+	var zero T
+	element = b.data[b.head]
+	b.data[b.head] = zero
+	b.head = b.index(1)
+	b.modifications.Add(1)
+	b.size--
+	b.notFull.Signal()
+
+	return element, nil
+}
+
+func (b *buffer[T]) Push(item T) {
+	b.TryPush(item)
+}
+
+func (b *buffer[T]) RemovingIterator() collections.RemovingIterator[T] {
+	// a sequence always finishes its snapshot, but an Iterator stops once anything is pushed, overwritten or popped
+	modifications := b.modifications.Load()
+
+	return &iterator[T]{
+		buffer:        b,
+		elements:      b.snapshot(),
+		modifications: modifications,
+	}
+}
+
+func (b *buffer[T]) Size() int {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	return b.size
+}
+
+func (b *buffer[T]) TryPush(item T) error {
+	b.lock.Lock()
+	defer b.lock.Unlock()
+
+	if b.size == len(b.data) {
+		switch b.policy {
+		case Reject:
+			return collections.ErrQueueFull
+		case Block:
+			for b.size == len(b.data) {
+				b.notFull.Wait()
+			}
+		default:
+			b.data[b.head] = item
+			b.head = b.index(1)
+			b.modifications.Add(1)
+			return nil
+		}
+	}
+
+	b.data[b.index(b.size)] = item
+	b.modifications.Add(1)
+	b.size++
+
+	return nil
+}
+
+func (b *buffer[T]) Values() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, element := range b.snapshot() {
+			if !yield(element) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,282 @@
+// ©2022 Brandon Moller
+
+package ringbuffer_test
+
+import (
+	"errors"
+	"runtime"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bmoller/collections"
+	"github.com/bmoller/collections/collectionstest"
+	"github.com/bmoller/collections/ringbuffer"
+)
+
+func FuzzBuffer(f *testing.F) {
+	collectionstest.FuzzQueue(f, func() collections.Queue[int] { return ringbuffer.New[int](1000, ringbuffer.Overwrite) })
+}
+
+func TestBuffer(t *testing.T) {
+	collectionstest.TestQueue(t, func() collections.Queue[int] { return ringbuffer.New[int](1000, ringbuffer.Overwrite) })
+}
+
+func TestBufferAll(t *testing.T) {
+	buffer := ringbuffer.New[int](100, ringbuffer.Overwrite)
+	for i := 0; i < 1000; i++ {
+		buffer.Push(i)
+	}
+
+	expected := 0
+	for i, element := range buffer.All() {
+		if i != expected {
+			t.Fatalf("expected index %d but got %d", expected, i)
+		} else if element != 900+i {
+			t.Fatalf("expected element with value %d but got %d", 900+i, element)
+		}
+		buffer.Pop()
+		expected++
+	}
+	if expected != 100 {
+		t.Fatalf("expected %d elements from All but got %d", 100, expected)
+	}
+
+	buffer.Push(0)
+	for range buffer.All() {
+		break
+	}
+}
+
+func TestBufferBlock(t *testing.T) {
+	buffer := ringbuffer.New[int](10, ringbuffer.Block)
+	for i := 0; i < 10; i++ {
+		buffer.Push(i)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		buffer.Push(10)
+	}()
+	select {
+	case <-done:
+		t.Fatal("expected Push to block while the buffer is full")
+	case <-time.After(10 * time.Millisecond):
+	}
+
+	if element, _ := buffer.Pop(); element != 0 {
+		t.Fatalf("expected element with value %d but got %d", 0, element)
+	}
+	<-done
+	for i := 1; i < 11; i++ {
+		if element, err := buffer.Pop(); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if element != i {
+			t.Fatalf("expected element with value %d but got %d", i, element)
+		}
+	}
+}
+
+func TestBufferCap(t *testing.T) {
+	if buffer := ringbuffer.New[int](10, ringbuffer.Overwrite); buffer.Cap() != 10 {
+		t.Fatalf("expected capacity %d but got %d", 10, buffer.Cap())
+	}
+	if buffer := ringbuffer.New[int](0, ringbuffer.Overwrite); buffer.Cap() != 1 {
+		t.Fatalf("expected minimum capacity %d but got %d", 1, buffer.Cap())
+	}
+}
+
+func TestBufferFull(t *testing.T) {
+	buffer := ringbuffer.New[int](10, ringbuffer.Overwrite)
+	for i := 0; i < 10; i++ {
+		if buffer.Full() {
+			t.Fatalf("expected buffer with %d elements not to be full", i)
+		}
+		buffer.Push(i)
+	}
+	if !buffer.Full() {
+		t.Fatal("expected buffer to be full")
+	}
+}
+
+func TestBufferGet(t *testing.T) {
+	buffer := ringbuffer.New[int](100, ringbuffer.Overwrite)
+	if _, err := buffer.Get(0); err == nil || !errors.Is(err, collections.ErrEmptyQueue) {
+		t.Fatalf("expected ErrEmptyQueue from Get on empty buffer but got: %v", err)
+	}
+
+	for i := 0; i < 1000; i++ {
+		buffer.Push(i)
+	}
+	for i := 0; i < 100; i++ {
+		if element, err := buffer.Get(i); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if element != 900+i {
+			t.Fatalf("expected element with value %d but got %d", 900+i, element)
+		}
+	}
+	for _, index := range []int{-1, 100} {
+		if _, err := buffer.Get(index); err == nil || !errors.As(err, &collections.ErrIndexOutOfRange{}) {
+			t.Fatalf("expected ErrIndexOutOfRange from Get(%d) but got: %v", index, err)
+		}
+	}
+}
+
+func TestBufferIterator(t *testing.T) {
+	buffer := ringbuffer.New[int](100, ringbuffer.Overwrite)
+	if _, err := buffer.Iterator()(); err == nil || !errors.Is(err, collections.ErrNoMoreItems) {
+		t.Fatalf("expected ErrNoMoreItems from Iterator on empty buffer but got: %v", err)
+	}
+
+	for i := 0; i < 1000; i++ {
+		buffer.Push(i)
+	}
+	itr := buffer.Iterator()
+	for i := 900; i < 1000; i++ {
+		if element, err := itr(); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if element != i {
+			t.Fatalf("expected element with value %d but got %d", i, element)
+		}
+	}
+	if _, err := itr(); err == nil || !errors.Is(err, collections.ErrNoMoreItems) {
+		t.Fatalf("exhausted iterator should return ErrNoMoreItems but got %v", err)
+	}
+
+	// overwriting the oldest element of a full buffer is a modification
+	itr = buffer.Iterator()
+	buffer.Push(1000)
+	if _, err := itr(); err == nil || !errors.Is(err, collections.ErrConcurrentModification) {
+		t.Fatalf("expected ErrConcurrentModification after overwriting but got: %v", err)
+	}
+
+	// as is nothing from a rejecting buffer that is full
+	buffer = ringbuffer.New[int](1, ringbuffer.Reject)
+	buffer.Push(0)
+	itr = buffer.Iterator()
+	buffer.Push(1)
+	if element, err := itr(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if element != 0 {
+		t.Fatalf("expected element with value %d but got %d", 0, element)
+	}
+}
+
+func TestBufferPeek(t *testing.T) {
+	buffer := ringbuffer.New[int](100, ringbuffer.Overwrite)
+	if _, err := buffer.Peek(); err == nil || !errors.Is(err, collections.ErrEmptyQueue) {
+		t.Fatalf("expected ErrEmptyQueue from Peek on new buffer but got: %v", err)
+	}
+
+	for i := 0; i < 1000; i++ {
+		buffer.Push(i)
+		if element, err := buffer.Peek(); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if element != max(0, i-99) {
+			t.Fatalf("expected element with value %d but got %d", max(0, i-99), element)
+		}
+	}
+}
+
+func TestBufferPop(t *testing.T) {
+	buffer := ringbuffer.New[int](100, ringbuffer.Overwrite)
+	for i := 0; i < 1000; i++ {
+		buffer.Push(i)
+	}
+	for i := 900; i < 1000; i++ {
+		if element, err := buffer.Pop(); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if element != i {
+			t.Fatalf("expected element %d but got %d", i, element)
+		}
+	}
+	if _, err := buffer.Pop(); err == nil || !errors.Is(err, collections.ErrEmptyQueue) {
+		t.Fatalf("expected ErrEmptyQueue from Pop on empty buffer but got: %v", err)
+	}
+}
+
+func TestBufferReject(t *testing.T) {
+	buffer := ringbuffer.New[int](100, ringbuffer.Reject)
+	for i := 0; i < 100; i++ {
+		if err := buffer.TryPush(i); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	if err := buffer.TryPush(100); err == nil || !errors.Is(err, collections.ErrQueueFull) {
+		t.Fatalf("expected ErrQueueFull from TryPush on full buffer but got: %v", err)
+	}
+	buffer.Push(101)
+	for i := 0; i < 100; i++ {
+		if element, _ := buffer.Get(i); element != i {
+			t.Fatalf("expected element with value %d but got %d", i, element)
+		}
+	}
+}
+
+func TestBufferSize(t *testing.T) {
+	buffer := ringbuffer.New[int](100, ringbuffer.Overwrite)
+	for i := 1; i < 1001; i++ {
+		buffer.Push(i)
+		if buffer.Size() != min(i, 100) {
+			t.Fatalf("expected buffer size %d but got %d", min(i, 100), buffer.Size())
+		}
+	}
+}
+
+func TestBufferValues(t *testing.T) {
+	buffer := ringbuffer.New[int](100, ringbuffer.Overwrite)
+	for i := 0; i < 1000; i++ {
+		buffer.Push(i)
+	}
+
+	expected := 900
+	for element := range buffer.Values() {
+		if element != expected {
+			t.Fatalf("expected element with value %d but got %d", expected, element)
+		}
+		expected++
+	}
+	if expected != 1000 {
+		t.Fatalf("expected %d elements from Values but got %d", 100, expected-900)
+	}
+
+	for range buffer.Values() {
+		break
+	}
+}
+
+func TestBufferConcurrent(t *testing.T) {
+	const (
+		producers   = 8
+		perProducer = 1000
+	)
+	buffer := ringbuffer.New[int](10, ringbuffer.Block)
+
+	var wg sync.WaitGroup
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perProducer; i++ {
+				buffer.Push(p*perProducer + i)
+			}
+		}()
+	}
+
+	var next [producers]int
+	for received := 0; received < producers*perProducer; {
+		element, err := buffer.Pop()
+		if err != nil {
+			runtime.Gosched()
+			continue
+		}
+		producer := element / perProducer
+		if element%perProducer != next[producer] {
+			t.Fatalf("expected element %d from producer %d but got %d", next[producer], producer, element%perProducer)
+		}
+		next[producer]++
+		received++
+	}
+	wg.Wait()
+}
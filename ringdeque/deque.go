@@ -0,0 +1,254 @@
+// ©2022 Brandon Moller
+
+/*
+Package ringdeque provides a slice-backed implementation of [collections.Deque].
+
+Elements are stored in a ring buffer, so adding or removing at either end never shifts the other elements.
+Whenever the Deque grows beyond the bounds of its current backing storage a new slice is created and all elements are copied.
+
+Deques, and the Stacks returned by AsStack, implement [collections.RemovingIterable]; each removal through a RemovingIterator shifts the elements behind it forward by one.
+*/
+package ringdeque
+
+import (
+	"iter"
+
+	"github.com/bmoller/collections"
+)
+
+const (
+	growthFactor int = 2
+	initialSize  int = 100
+)
+
+type iterator[T comparable] struct {
+	deque         *deque[T]
+	index         int
+	modifications int
+	removable     bool
+}
+
+func (i *iterator[T]) Next() (element T, err error) {
+	d := i.deque
+	if d.modifications != i.modifications {
+		return element, collections.ErrConcurrentModification
+	} else if i.index >= d.size {
+		return element, collections.ErrNoMoreItems
+	}
+	element = d.data[d.index(i.index)]
+	i.index++
+	i.removable = true
+
+	return element, nil
+}
+
+func (i *iterator[T]) Remove() error {
+	d := i.deque
+	if d.modifications != i.modifications {
+		return collections.ErrConcurrentModification
+	} else if !i.removable {
+		return collections.ErrNothingToRemove
+	}
+	i.index--
+	for j := i.index; j < d.size-1; j++ {
+		d.data[d.index(j)] = d.data[d.index(j+1)]
+	}
+	var zero T
+	d.data[d.index(d.size-1)] = zero
+	d.modifications++
+	d.size--
+	i.modifications, i.removable = d.modifications, false
+
+	return nil
+}
+
+type deque[T comparable] struct {
+	data          []T
+	head          int
+	modifications int
+	size          int
+}
+
+func New[T comparable]() collections.Deque[T] {
+	return &deque[T]{
+		data: make([]T, initialSize),
+	}
+}
+
+/*
+NewWithSize allows the user control over the initial size of the backing slice.
+A new Deque is created and returned with size as its capacity.
+*/
+func NewWithSize[T comparable](size int) collections.Deque[T] {
+	if size < 1 {
+		size = 1
+	}
+
+	return &deque[T]{
+		data: make([]T, size),
+	}
+}
+
+// index translates a position relative to the front of the deque into an index of the backing slice.
+func (d *deque[T]) index(i int) int {
+	return (d.head + i) % len(d.data)
+}
+
+func (d *deque[T]) grow() {
+	newData := make([]T, len(d.data)*growthFactor)
+	for i := 0; i < d.size; i++ {
+		newData[i] = d.data[d.index(i)]
+	}
+	d.data = newData
+	d.head = 0
+}
+
+func (d *deque[T]) All() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for i := 0; i < d.size; i++ {
+			if !yield(i, d.data[d.index(i)]) {
+				return
+			}
+		}
+	}
+}
+
+func (d *deque[T]) AsStack() collections.Stack[T] {
+	return stack[T]{d}
+}
+
+func (d *deque[T]) Backward() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for i := d.size - 1; i >= 0; i-- {
+			if i >= d.size {
+				continue
+			}
+			if !yield(i, d.data[d.index(i)]) {
+				return
+			}
+		}
+	}
+}
+
+func (d *deque[T]) Empty() bool {
+	return d.size == 0
+}
+
+func (d *deque[T]) Iterator() collections.Iterator[T] {
+	return d.RemovingIterator().Next
+}
+
+func (d *deque[T]) Peek() (T, error) {
+	return d.PeekFront()
+}
+
+func (d *deque[T]) PeekBack() (element T, err error) {
+	if d.size == 0 {
+		return element, collections.ErrEmptyDeque
+	}
+
+	return d.data[d.index(d.size-1)], nil
+}
+
+func (d *deque[T]) PeekFront() (element T, err error) {
+	if d.size == 0 {
+		return element, collections.ErrEmptyDeque
+	}
+
+	return d.data[d.head], nil
+}
+
+func (d *deque[T]) Pop() (T, error) {
+	return d.PopFront()
+}
+
+func (d *deque[T]) PopBack() (element T, err error) {
+	if d.size == 0 {
+		return element, collections.ErrEmptyDeque
+	}
+
+	var zero T
+	i := d.index(d.size - 1)
+	element = d.data[i]
+	d.data[i] = zero
+	d.modifications++
+	d.size--
+
+	return element, nil
+}
+
+func (d *deque[T]) PopFront() (element T, err error) {
+	if d.size == 0 {
+		return element, collections.ErrEmptyDeque
+	}
+
+	var zero T
+	element = d.data[d.head]
+	d.data[d.head] = zero
+	d.head = d.index(1)
+	d.modifications++
+	d.size--
+
+	return element, nil
+}
+
+func (d *deque[T]) Push(item T) {
+	d.PushBack(item)
+}
+
+func (d *deque[T]) PushBack(item T) {
+	if d.size == len(d.data) {
+		d.grow()
+	}
+	d.data[d.index(d.size)] = item
+	d.modifications++
+	d.size++
+}
+
+func (d *deque[T]) PushFront(item T) {
+	if d.size == len(d.data) {
+		d.grow()
+	}
+	d.head = d.index(len(d.data) - 1)
+	d.data[d.head] = item
+	d.modifications++
+	d.size++
+}
+
+func (d *deque[T]) RemovingIterator() collections.RemovingIterator[T] {
+	return &iterator[T]{
+		deque:         d,
+		modifications: d.modifications,
+	}
+}
+
+func (d *deque[T]) Size() int {
+	return d.size
+}
+
+func (d *deque[T]) Values() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for i := 0; i < d.size; i++ {
+			if !yield(d.data[d.index(i)]) {
+				return
+			}
+		}
+	}
+}
+
+// stack presents the front of a deque as the top of a Stack.
+type stack[T comparable] struct {
+	*deque[T]
+}
+
+func (s stack[T]) Peek() (T, error) {
+	return s.PeekFront()
+}
+
+func (s stack[T]) Pop() (T, error) {
+	return s.PopFront()
+}
+
+func (s stack[T]) Push(item T) {
+	s.PushFront(item)
+}
@@ -4,19 +4,71 @@
 Package slicelist provides an array/slice-backed implementation of [collections.List].
 
 Whenever the List grows beyond the bounds of its current backing storage a new slice is created and all elements are copied.
+
+Sort uses an in-place pattern-defeating quicksort, while SortStable uses an in-place stable sort; neither allocates a new backing slice.
+
+A List is encoded to JSON as an array of its elements in order, and decoding an array replaces the contents of the List.
+The binary and gob encodings, and WriteTo and ReadFrom, use the shared List format, so they can be exchanged with linkedlist.
+
+Lists implement [collections.RemovingIterable]; each removal through a RemovingIterator shifts the elements after it, as Remove does.
+
+Lists implement [collections.Validator], which checks that the size is within the bounds of the backing slice.
 */
 package slicelist
 
-import "github.com/bmoller/collections"
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"slices"
+
+	"github.com/bmoller/collections"
+	"github.com/bmoller/collections/internal/wire"
+)
 
 const (
 	growthFactor int = 2
 	initialSize  int = 100
 )
 
+type iterator[T comparable] struct {
+	index         int
+	list          *list[T]
+	modifications int
+	removable     bool
+}
+
+func (i *iterator[T]) Next() (element T, err error) {
+	if i.list.modifications != i.modifications {
+		return element, collections.ErrConcurrentModification
+	} else if i.index >= i.list.size {
+		return element, collections.ErrNoMoreItems
+	}
+	element = i.list.data[i.index]
+	i.index++
+	i.removable = true
+
+	return element, nil
+}
+
+func (i *iterator[T]) Remove() error {
+	if i.list.modifications != i.modifications {
+		return collections.ErrConcurrentModification
+	} else if !i.removable {
+		return collections.ErrNothingToRemove
+	}
+	i.index--
+	i.list.Remove(i.index)
+	i.modifications, i.removable = i.list.modifications, false
+
+	return nil
+}
+
 type list[T comparable] struct {
-	data []T
-	size int
+	data          []T
+	modifications int
+	size          int
 }
 
 func New[T comparable]() collections.List[T] {
@@ -46,8 +98,29 @@ func NewWithSize[T comparable](size int) collections.List[T] {
 	}
 }
 
+// compareFunc adapts a less function to the three-way comparison expected by the slices package.
+func compareFunc[T comparable](less func(a, b T) bool) func(a, b T) int {
+	return func(a, b T) int {
+		switch {
+		case less(a, b):
+			return -1
+		case less(b, a):
+			return 1
+		default:
+			return 0
+		}
+	}
+}
+
+// reset discards the contents ahead of decoding, sizing the backing slice for hint elements.
+func (l *list[T]) reset(hint int) {
+	l.data = make([]T, max(hint*growthFactor, initialSize))
+	l.modifications++
+	l.size = 0
+}
+
 func (l *list[T]) Add(item T) {
-	if l.size+1 == cap(l.data) {
+	if l.size == len(l.data) {
 		newData := make([]T, (l.size+1)*growthFactor)
 		for i := 0; i < l.size; i++ {
 			newData[i] = l.data[i]
@@ -55,13 +128,42 @@ func (l *list[T]) Add(item T) {
 		l.data = newData
 	}
 	l.data[l.size] = item
+	l.modifications++
 	l.size++
 }
 
+func (l *list[T]) All() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for i := 0; i < l.size; i++ {
+			if !yield(i, l.data[i]) {
+				return
+			}
+		}
+	}
+}
+
+func (l *list[T]) Backward() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for i := l.size - 1; i >= 0; i-- {
+			if i >= l.size {
+				continue
+			}
+			if !yield(i, l.data[i]) {
+				return
+			}
+		}
+	}
+}
+
 func (l *list[T]) Clear() {
+	l.modifications++
 	l.size = 0
 }
 
+func (l *list[T]) Contains(item T) bool {
+	return l.IndexOf(item) != -1
+}
+
 func (l *list[T]) Empty() bool {
 	return l.size == 0
 }
@@ -70,7 +172,7 @@ func (l *list[T]) Get(index int) (item T, err error) {
 	switch {
 	case l.size == 0:
 		err = collections.ErrEmptyList
-	case index >= l.size:
+	case index >= l.size || index < 0:
 		err = collections.ErrIndexOutOfRange{
 			Index: index,
 			Size:  l.size,
@@ -82,6 +184,34 @@ func (l *list[T]) Get(index int) (item T, err error) {
 	return item, err
 }
 
+func (l *list[T]) GobDecode(data []byte) error {
+	return l.UnmarshalBinary(data)
+}
+
+func (l *list[T]) GobEncode() ([]byte, error) {
+	return l.MarshalBinary()
+}
+
+func (l *list[T]) IndexFunc(f func(T) bool) int {
+	for i := 0; i < l.size; i++ {
+		if f(l.data[i]) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func (l *list[T]) IndexOf(item T) int {
+	for i := 0; i < l.size; i++ {
+		if l.data[i] == item {
+			return i
+		}
+	}
+
+	return -1
+}
+
 func (l *list[T]) Insert(index int, item T) error {
 	if index < 0 || index > l.size {
 		return collections.ErrIndexOutOfRange{
@@ -90,8 +220,8 @@ func (l *list[T]) Insert(index int, item T) error {
 		}
 	}
 
-	if newSize := l.size + 1; newSize == cap(l.data) {
-		newData := make([]T, newSize*growthFactor)
+	if l.size == len(l.data) {
+		newData := make([]T, (l.size+1)*growthFactor)
 		for i := 0; i < index; i++ {
 			newData[i] = l.data[i]
 		}
@@ -99,21 +229,54 @@ func (l *list[T]) Insert(index int, item T) error {
 		for i := index; i < l.size; i++ {
 			newData[i+1] = l.data[i]
 		}
+		l.data = newData
 	} else {
 		for i := l.size; i > index; i-- {
 			l.data[i] = l.data[i-1]
 		}
 		l.data[index] = item
 	}
+	l.modifications++
 	l.size++
 
 	return nil
 }
 
+func (l *list[T]) Iterator() collections.Iterator[T] {
+	return l.RemovingIterator().Next
+}
+
+func (l *list[T]) LastIndexOf(item T) int {
+	for i := l.size - 1; i >= 0; i-- {
+		if l.data[i] == item {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func (l *list[T]) MarshalBinary() ([]byte, error) {
+	return wire.Marshal(wire.List, l.size, l.Values())
+}
+
+func (l *list[T]) MarshalJSON() ([]byte, error) {
+	elements := l.data[:l.size]
+	if elements == nil {
+		elements = []T{}
+	}
+
+	return json.Marshal(elements)
+}
+
+func (l *list[T]) ReadFrom(r io.Reader) (int64, error) {
+	return wire.Read(r, wire.List, l.reset, l.Add)
+}
+
 func (l *list[T]) Remove(index int) (element T, err error) {
 	if l.size == 0 {
 		return element, collections.ErrEmptyList
-	} else if index > l.size-1 {
+	} else if index >= l.size || index < 0 {
 		return element, collections.ErrIndexOutOfRange{
 			Index: index,
 			Size:  l.size,
@@ -124,15 +287,50 @@ func (l *list[T]) Remove(index int) (element T, err error) {
 	for i := index; i < l.size-1; i++ {
 		l.data[i] = l.data[i+1]
 	}
+	l.modifications++
 	l.size--
 
 	return element, err
 }
 
+func (l *list[T]) RemovingIterator() collections.RemovingIterator[T] {
+	return &iterator[T]{
+		list:          l,
+		modifications: l.modifications,
+	}
+}
+
+func (l *list[T]) Set(index int, item T) (element T, err error) {
+	switch {
+	case l.size == 0:
+		err = collections.ErrEmptyList
+	case index >= l.size || index < 0:
+		err = collections.ErrIndexOutOfRange{
+			Index: index,
+			Size:  l.size,
+		}
+	default:
+		element, l.data[index] = l.data[index], item
+		l.modifications++
+	}
+
+	return element, err
+}
+
 func (l *list[T]) Size() int {
 	return l.size
 }
 
+func (l *list[T]) Sort(less func(a, b T) bool) {
+	l.modifications++
+	slices.SortFunc(l.data[:l.size], compareFunc(less))
+}
+
+func (l *list[T]) SortStable(less func(a, b T) bool) {
+	l.modifications++
+	slices.SortStableFunc(l.data[:l.size], compareFunc(less))
+}
+
 func (l *list[T]) SubList(start, end int) (collections.List[T], error) {
 	switch {
 	case l.size == 0:
@@ -164,3 +362,44 @@ func (l *list[T]) SubList(start, end int) (collections.List[T], error) {
 		size: size,
 	}, nil
 }
+
+func (l *list[T]) UnmarshalBinary(data []byte) error {
+	return wire.Unmarshal(data, wire.List, l.reset, l.Add)
+}
+
+func (l *list[T]) UnmarshalJSON(data []byte) error {
+	var elements []T
+	if err := json.Unmarshal(data, &elements); err != nil {
+		return err
+	}
+
+	l.reset(len(elements))
+	l.size = copy(l.data, elements)
+
+	return nil
+}
+
+func (l *list[T]) Validate() error {
+	if l.size < 0 || l.size > len(l.data) {
+		return collections.ErrCorrupted{
+			Index:   -1,
+			Problem: fmt.Sprintf("size %d is outside the backing slice of length %d", l.size, len(l.data)),
+		}
+	}
+
+	return nil
+}
+
+func (l *list[T]) Values() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for i := 0; i < l.size; i++ {
+			if !yield(l.data[i]) {
+				return
+			}
+		}
+	}
+}
+
+func (l *list[T]) WriteTo(w io.Writer) (int64, error) {
+	return wire.Write(w, wire.List, l.size, l.Values())
+}
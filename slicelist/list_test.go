@@ -3,19 +3,39 @@
 package slicelist_test
 
 import (
+	"bytes"
+	"encoding"
+	"encoding/gob"
+	"encoding/json"
 	"errors"
-	"math/rand"
-	"os"
+	"io"
+	"slices"
 	"testing"
-	"time"
 
 	"github.com/bmoller/collections"
+	"github.com/bmoller/collections/collectionstest"
+	"github.com/bmoller/collections/linkedlist"
 	"github.com/bmoller/collections/slicelist"
 )
 
-func TestMain(m *testing.M) {
-	rand.Seed(time.Now().UTC().UnixNano())
-	os.Exit(m.Run())
+func FuzzList(f *testing.F) {
+	collectionstest.FuzzList(f, slicelist.New[int])
+}
+
+func FuzzListNewWithSize(f *testing.F) {
+	collectionstest.FuzzList(f, func() collections.List[int] { return slicelist.NewWithSize[int](0) })
+}
+
+func TestList(t *testing.T) {
+	t.Run("New", func(t *testing.T) {
+		collectionstest.TestList(t, slicelist.New[int])
+	})
+	t.Run("NewFromItems", func(t *testing.T) {
+		collectionstest.TestList(t, func() collections.List[int] { return slicelist.NewFromItems([]int{}) })
+	})
+	t.Run("NewWithSize", func(t *testing.T) {
+		collectionstest.TestList(t, func() collections.List[int] { return slicelist.NewWithSize[int](0) })
+	})
 }
 
 func TestListNewFromItems(t *testing.T) {
@@ -28,7 +48,8 @@ func TestListNewFromItems(t *testing.T) {
 	if list.Size() != 1000 {
 		t.Fatalf("expected list size %d but got %d", 1000, list.Size())
 	}
-	for i := 0; i < 1000; i++ {
+	list.Add(1000)
+	for i := 0; i < 1001; i++ {
 		if element, err := list.Get(i); err != nil {
 			t.Fatalf("unexpected error: %s", err)
 		} else if element != i {
@@ -58,239 +79,170 @@ func TestListNewWithSize(t *testing.T) {
 	}
 }
 
-func TestListAdd(t *testing.T) {
-	for i := 1; i < 1001; i++ {
-		list := slicelist.New[int]()
-		for j := 0; j < i; j++ {
-			list.Add(j)
-		}
+func TestListBackward(t *testing.T) {
+	list := slicelist.New[int]()
+	for i := 0; i < 1000; i++ {
+		list.Add(i)
 	}
-}
 
-func TestListClear(t *testing.T) {
-	for i := 1; i < 1001; i++ {
-		list := slicelist.New[int]()
-		for j := 0; j < i; j++ {
-			list.Add(j)
-		}
-		list.Clear()
-		if list.Size() != 0 || !list.Empty() {
-			t.Fatal("expected list to be empty after Clear")
+	// elements removed ahead of the sequence are skipped
+	for i := range list.Backward() {
+		list.Remove(i)
+		if i > 0 {
+			list.Remove(i - 1)
 		}
 	}
-}
-
-func TestListEmpty(t *testing.T) {
-	list := slicelist.New[int]()
 	if !list.Empty() {
-		t.Fatal("expected new list to be empty")
-	}
-	list.Add(0)
-	if list.Empty() {
-		t.Fatal("expected list to not be empty after adding element")
+		t.Fatal("expected list to be empty after removing during Backward")
 	}
 }
 
-func TestListGet(t *testing.T) {
+func TestListMarshalJSON(t *testing.T) {
 	list := slicelist.New[int]()
-	if _, err := list.Get(0); err == nil {
-		t.Fatal("expected error from Get on an empty list")
-	} else if !errors.Is(err, collections.ErrEmptyList) {
-		t.Fatalf("expected ErrEmptyList but got: %s", err)
+	if data, err := json.Marshal(list); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if string(data) != "[]" {
+		t.Fatalf("expected empty list to encode as %s but got %s", "[]", data)
 	}
 
 	for i := 0; i < 1000; i++ {
 		list.Add(i)
 	}
-	for i := 0; i < 1000; i++ {
-		if element, err := list.Get(i); err != nil {
-			t.Fatalf("unexpected error: %s", err)
-		} else if element != i {
-			t.Fatalf("expected element with value %d but got %d", i, element)
-		}
-	}
-
-	indexError := new(collections.ErrIndexOutOfRange)
-	if _, err := list.Get(1000); err == nil {
-		t.Fatal("expected error from Get for element out of range")
-	} else if !errors.As(err, indexError) {
-		t.Fatalf("expected ErrIndexOutOfRange but got: %s", err)
+	data, err := json.Marshal(list)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
 	}
-
-	list.Clear()
-	if _, err := list.Get(0); err == nil {
-		t.Fatal("expected error from Get after Clear on list")
-	} else if !errors.Is(err, collections.ErrEmptyList) {
-		t.Fatalf("expected ErrEmptyList but received: %s", err)
+	var elements []int
+	if err := json.Unmarshal(data, &elements); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if len(elements) != 1000 {
+		t.Fatalf("expected %d encoded elements but got %d", 1000, len(elements))
 	}
-
-	list.Add(0)
-	if _, err := list.Get(500); err == nil {
-		t.Fatal("expected error from Get for element out of range")
-	} else if !errors.As(err, indexError) {
-		t.Fatalf("expected ErrIndexOutOfRange but got: %s", err)
+	for i, element := range elements {
+		if element != i {
+			t.Fatalf("expected element with value %d at index %d but got %d", i, i, element)
+		}
 	}
 }
 
-func TestListInsert(t *testing.T) {
-	input := make([]int, 1000)
-	for i := 0; i < 1000; i++ {
-		input[i] = i
-	}
-	expectedOutput := make([]int, 1004)
-	copy(expectedOutput, input[:200])
-	expectedOutput[200] = 0
-	copy(expectedOutput[201:], input[200:400])
-	expectedOutput[401] = 0
-	copy(expectedOutput[402:], input[400:600])
-	expectedOutput[602] = 0
-	copy(expectedOutput[603:], input[600:800])
-	expectedOutput[803] = 0
-	copy(expectedOutput[804:], input[800:])
-
+func TestListUnmarshalJSON(t *testing.T) {
 	list := slicelist.New[int]()
-	for _, value := range input {
-		list.Add(value)
-	}
-
-	if err := list.Insert(-1, 0); err == nil {
-		t.Fatal("expected error on Insert at negative index")
+	if err := json.Unmarshal([]byte("[0,1,2]"), list); err != nil {
+		t.Fatalf("unexpected error: %s", err)
 	}
-	if err := list.Insert(9999, 0); err == nil {
-		t.Fatal("expected error on Insert outside of range")
+	list.Add(3)
+	for i := 0; i < 4; i++ {
+		if element, err := list.Get(i); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if element != i {
+			t.Fatalf("expected element with value %d but got %d", i, element)
+		}
 	}
 
-	if err := list.Insert(200, 0); err != nil {
-		t.Fatalf("unexpected error: %s", err)
+	if err := json.Unmarshal([]byte(`{"a":1}`), list); err == nil {
+		t.Fatal("expected error from decoding an object")
+	} else if list.Size() != 4 {
+		t.Fatalf("expected failed decode to leave size %d but got %d", 4, list.Size())
 	}
-	if err := list.Insert(401, 0); err != nil {
-		t.Fatalf("unexpected error: %s", err)
+
+	payload := struct {
+		Items collections.List[int]
+	}{
+		Items: slicelist.New[int](),
 	}
-	if err := list.Insert(602, 0); err != nil {
+	payload.Items.Add(99)
+	if err := json.Unmarshal([]byte(`{"Items":[5,6]}`), &payload); err != nil {
 		t.Fatalf("unexpected error: %s", err)
+	} else if payload.Items.Size() != 2 {
+		t.Fatalf("expected decoding to replace contents leaving size %d but got %d", 2, payload.Items.Size())
 	}
-	if err := list.Insert(803, 0); err != nil {
+	if data, err := json.Marshal(payload); err != nil {
 		t.Fatalf("unexpected error: %s", err)
+	} else if string(data) != `{"Items":[5,6]}` {
+		t.Fatalf("expected round trip to produce %s but got %s", `{"Items":[5,6]}`, data)
 	}
 
-	if list.Size() != 1004 {
-		t.Fatalf("expected list size %d but got %d", 1004, list.Size())
+	// encoding/json cannot choose an implementation for a nil field
+	var unset struct {
+		Items collections.List[int]
 	}
-	for i := 0; i < 1004; i++ {
-		if element, err := list.Get(i); err != nil {
-			t.Fatalf("unexpected error: %s", err)
-		} else if element != expectedOutput[i] {
-			t.Fatalf("expected element with value %d but got %d", expectedOutput[i], element)
-		}
+	var typeErr *json.UnmarshalTypeError
+	if err := json.Unmarshal([]byte(`{"Items":[5,6]}`), &unset); err == nil || !errors.As(err, &typeErr) {
+		t.Fatalf("expected error %T but got %v", typeErr, err)
+	} else if unset.Items != nil {
+		t.Fatal("expected nil field to be left unset")
 	}
 }
 
-func TestListRemove(t *testing.T) {
+func TestListBinaryEncoding(t *testing.T) {
 	list := slicelist.New[int]()
-
-	if _, err := list.Remove(0); err == nil {
-		t.Fatal("expected error on call to Remove on empty list")
-	} else if !errors.Is(err, collections.ErrEmptyList) {
-		t.Fatalf("expected ErrEmptyList but got: %s", err)
-	}
-
 	for i := 0; i < 1000; i++ {
 		list.Add(i)
 	}
+	expected := slices.Collect(list.Values())
 
-	indexErr := new(collections.ErrIndexOutOfRange)
-	if _, err := list.Remove(9999); err == nil {
-		t.Fatal("expected error on call to Remove index out of range")
-	} else if !errors.As(err, indexErr) {
-		t.Fatalf("expected ErrIndexOutOfRange but got: %s", err)
+	data, err := list.(encoding.BinaryMarshaler).MarshalBinary()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
 	}
-
-	for i := 0; i < 1000; i++ {
-		if element, err := list.Remove(0); err != nil {
-			t.Fatalf("unexpected error: %s", err)
-		} else if element != i {
-			t.Fatalf("expected element with value %d but got %d", i, element)
-		}
+	decoded := slicelist.New[int]()
+	if err := decoded.(encoding.BinaryUnmarshaler).UnmarshalBinary(data); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if actual := slices.Collect(decoded.Values()); !slices.Equal(actual, expected) {
+		t.Fatal("decoded list does not match the original")
 	}
-
-	if _, err := list.Remove(0); err == nil {
-		t.Fatal("expected error on call to Remove on empty list")
-	} else if !errors.Is(err, collections.ErrEmptyList) {
-		t.Fatalf("expected ErrEmptyList but got: %s", err)
+	decoded.Add(1000)
+	other := linkedlist.New[int]()
+	if err := other.(encoding.BinaryUnmarshaler).UnmarshalBinary(data); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if actual := slices.Collect(other.Values()); !slices.Equal(actual, expected) {
+		t.Fatal("list decoded by linkedlist does not match the original")
 	}
 
-	for i := 0; i < 1000; i++ {
-		list.Add(i)
+	if err := decoded.(encoding.BinaryUnmarshaler).UnmarshalBinary([]byte("not a list")); err == nil || !errors.Is(err, collections.ErrInvalidEncoding) {
+		t.Fatalf("expected ErrInvalidEncoding from invalid data but got: %v", err)
 	}
+}
 
-	for i := 999; i > 0; i-- {
-		if _, err := list.Remove(rand.Intn(i)); err != nil {
-			t.Fatalf("unexpected error: %s", err)
-		}
+func TestListGob(t *testing.T) {
+	list := slicelist.New[string]()
+	for _, element := range []string{"a", "b", "c"} {
+		list.Add(element)
 	}
-	if _, err := list.Remove(0); err != nil {
+
+	var buffer bytes.Buffer
+	if err := gob.NewEncoder(&buffer).Encode(list); err != nil {
 		t.Fatalf("unexpected error: %s", err)
 	}
-}
-
-func TestListSize(t *testing.T) {
-	for i := 1; i < 1001; i++ {
-		list := slicelist.New[int]()
-		for j := 0; j < i; j++ {
-			list.Add(j)
-		}
-		if list.Size() != i {
-			t.Fatalf("expected list size %d but got %d", i, list.Size())
-		}
+	decoded := slicelist.New[string]()
+	if err := gob.NewDecoder(&buffer).Decode(decoded); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if !slices.Equal(slices.Collect(decoded.Values()), slices.Collect(list.Values())) {
+		t.Fatal("decoded list does not match the original")
 	}
 }
 
-func TestListSubList(t *testing.T) {
-	l1 := slicelist.New[int]()
-	if _, err := l1.SubList(0, 0); err == nil {
-		t.Fatal("expected error from SubList on new List")
-	} else if !errors.Is(err, collections.ErrEmptyList) {
-		t.Fatalf("expected ErrEmptyList, got %T", err)
-	}
-
+func TestListWriteTo(t *testing.T) {
+	list := slicelist.New[int]()
 	for i := 0; i < 1000; i++ {
-		l1.Add(i)
+		list.Add(i)
 	}
-	l2, err := l1.SubList(250, 750)
+
+	var buffer bytes.Buffer
+	written, err := list.(io.WriterTo).WriteTo(&buffer)
 	if err != nil {
 		t.Fatalf("unexpected error: %s", err)
-	} else if l2.Size() != 500 {
-		t.Fatalf("expected sublist size of %d but got %d", 500, l2.Size())
-	}
-	for i := 250; i < 750; i++ {
-		if element, err := l2.Get(i - 250); err != nil {
-			t.Fatalf("unexpected error: %s", err)
-		} else if element != i {
-			t.Fatalf("expected element %d but not %d", i, element)
-		}
+	} else if written != int64(buffer.Len()) {
+		t.Fatalf("expected WriteTo to report %d bytes but got %d", buffer.Len(), written)
 	}
 
-	rangeErr := new(collections.ErrInvalidRange)
-	if _, err := l1.SubList(-1, 10); err == nil {
-		t.Fatal("expected error from SubList with start less than 0")
-	} else if !errors.As(err, rangeErr) {
-		t.Fatalf("expected ErrInvalidRange, got %T", err)
-	}
-	if _, err := l1.SubList(10, 0); err == nil {
-		t.Fatal("expected error from SubList with end less than start")
-	} else if !errors.As(err, rangeErr) {
-		t.Fatalf("expected ErrInvalidRange, got %T", err)
-	}
-
-	indexErr := new(collections.ErrIndexOutOfRange)
-	if _, err := l1.SubList(1000, 1005); err == nil {
-		t.Fatal("expected error from SubList with start == size")
-	} else if !errors.As(err, indexErr) {
-		t.Fatalf("expected ErrIndexOutOfRange, got %T", err)
-	}
-	if _, err := l1.SubList(0, 1005); err == nil {
-		t.Fatal("expected error from SubList with end > size")
-	} else if !errors.As(err, indexErr) {
-		t.Fatalf("expected ErrIndexOutOfRange, got %T", err)
+	decoded := slicelist.New[int]()
+	decoded.Add(-1)
+	if read, err := decoded.(io.ReaderFrom).ReadFrom(&buffer); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if read != written {
+		t.Fatalf("expected ReadFrom to report %d bytes but got %d", written, read)
+	} else if !slices.Equal(slices.Collect(decoded.Values()), slices.Collect(list.Values())) {
+		t.Fatal("decoded list does not match the original")
 	}
 }
@@ -0,0 +1,42 @@
+// ©2022 Brandon Moller
+
+package slicelist
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bmoller/collections"
+)
+
+func TestListValidateCorrupted(t *testing.T) {
+	tests := []struct {
+		name    string
+		corrupt func(*list[int])
+		problem string
+	}{
+		{"NegativeSize", func(l *list[int]) { l.size = -1 }, "size -1 is outside the backing slice of length 5"},
+		{"SizeBeyondSlice", func(l *list[int]) { l.size = 6 }, "size 6 is outside the backing slice of length 5"},
+		{"TruncatedSlice", func(l *list[int]) { l.data = l.data[:2] }, "size 5 is outside the backing slice of length 2"},
+		{"NilSlice", func(l *list[int]) { l.data = nil }, "size 5 is outside the backing slice of length 0"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			list := &list[int]{data: make([]int, 5)}
+			for i := 0; i < 5; i++ {
+				list.Add(i)
+			}
+			if err := list.Validate(); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			test.corrupt(list)
+			var corrupted collections.ErrCorrupted
+			if err := list.Validate(); err == nil || !errors.As(err, &corrupted) {
+				t.Fatalf("expected error %T but got %v", corrupted, err)
+			} else if corrupted.Index != -1 || corrupted.Problem != test.problem {
+				t.Fatalf("expected corruption at index %d (%q) but got index %d (%q)", -1, test.problem, corrupted.Index, corrupted.Problem)
+			}
+		})
+	}
+}
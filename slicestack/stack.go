@@ -3,20 +3,74 @@
 /*
 The slicestack package provides a Stack implementation backed by a slice.
 
-The Stack inserts and removes items into and from the slice and tracks the top via an internal pointer
+The Stack inserts and removes items into and from the slice and tracks the top via an internal pointer.
+
+In JSON a Stack is an array ordered from the bottom of the Stack to the top, which is also the order in which the elements would have to be pushed to rebuild it.
+Binary and gob encodings, and WriteTo, follow the same order.
+
+Stacks implement [collections.RemovingIterable]; each removal through a RemovingIterator shifts the elements above it down by one.
+
+Stacks implement [collections.Validator], which checks that the top is within the bounds of the backing slice.
 */
 package slicestack
 
-import "github.com/bmoller/collections"
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"iter"
+	"slices"
+
+	"github.com/bmoller/collections"
+	"github.com/bmoller/collections/internal/wire"
+)
 
 const (
 	stackGrowthFactor int = 2   // Length of the backing array is multiplied by this when a replacement is allocated
 	stackInitialSize  int = 100 // The initial size of the backing array and slice
 )
 
+type iterator[T comparable] struct {
+	index         int // counts down from the top, as the Stack is iterated from the end of the slice
+	modifications int
+	removable     bool
+	stack         *stack[T]
+}
+
+func (i *iterator[T]) Next() (element T, err error) {
+	if i.stack.modifications != i.modifications {
+		return element, collections.ErrConcurrentModification
+	} else if i.index < 0 {
+		return element, collections.ErrNoMoreItems
+	}
+	element = i.stack.data[i.index]
+	i.index--
+	i.removable = true
+
+	return element, nil
+}
+
+func (i *iterator[T]) Remove() error {
+	s := i.stack
+	if s.modifications != i.modifications {
+		return collections.ErrConcurrentModification
+	} else if !i.removable {
+		return collections.ErrNothingToRemove
+	}
+	copy(s.data[i.index+1:], s.data[i.index+2:s.size])
+	var zero T
+	s.data[s.size-1] = zero
+	s.modifications++
+	s.size--
+	i.modifications, i.removable = s.modifications, false
+
+	return nil
+}
+
 type stack[T comparable] struct {
-	data []T
-	size int
+	data          []T
+	modifications int
+	size          int
 }
 
 func New[T comparable]() collections.Stack[T] {
@@ -35,10 +89,55 @@ func NewWithSize[T comparable](size int) collections.Stack[T] {
 	}
 }
 
+// reset replaces the backing slice ahead of decoding, with room for hint elements.
+func (s *stack[T]) reset(hint int) {
+	s.data = make([]T, max(hint*stackGrowthFactor, stackInitialSize))
+	s.modifications++
+	s.size = 0
+}
+
+func (s *stack[T]) All() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for i := s.size - 1; i >= 0; i-- {
+			if i >= s.size {
+				continue
+			}
+			if !yield(s.size-1-i, s.data[i]) {
+				return
+			}
+		}
+	}
+}
+
 func (s *stack[T]) Empty() bool {
 	return s.size == 0
 }
 
+func (s *stack[T]) GobDecode(data []byte) error {
+	return s.UnmarshalBinary(data)
+}
+
+func (s *stack[T]) GobEncode() ([]byte, error) {
+	return s.MarshalBinary()
+}
+
+func (s *stack[T]) Iterator() collections.Iterator[T] {
+	return s.RemovingIterator().Next
+}
+
+func (s *stack[T]) MarshalBinary() ([]byte, error) {
+	return wire.Marshal(wire.Stack, s.size, slices.Values(s.data[:s.size]))
+}
+
+func (s *stack[T]) MarshalJSON() ([]byte, error) {
+	elements := s.data[:s.size]
+	if elements == nil {
+		elements = []T{}
+	}
+
+	return json.Marshal(elements)
+}
+
 func (s *stack[T]) Peek() (item T, err error) {
 	if s.size == 0 {
 		err = collections.ErrEmptyStack
@@ -54,6 +153,7 @@ func (s *stack[T]) Pop() (item T, err error) {
 		err = collections.ErrEmptyStack
 	} else {
 		item = s.data[s.size-1]
+		s.modifications++
 		s.size--
 	}
 
@@ -61,17 +161,74 @@ func (s *stack[T]) Pop() (item T, err error) {
 }
 
 func (s *stack[T]) Push(item T) {
-	s.data[s.size] = item
-	s.size++
-	if s.size == cap(s.data) {
-		newData := make([]T, len(s.data)*stackGrowthFactor)
+	if s.size == len(s.data) {
+		newData := make([]T, (s.size+1)*stackGrowthFactor)
 		for i := 0; i < s.size; i++ {
 			newData[i] = s.data[i]
 		}
 		s.data = newData
 	}
+	s.data[s.size] = item
+	s.modifications++
+	s.size++
+}
+
+func (s *stack[T]) ReadFrom(r io.Reader) (int64, error) {
+	return wire.Read(r, wire.Stack, s.reset, s.Push)
+}
+
+func (s *stack[T]) RemovingIterator() collections.RemovingIterator[T] {
+	return &iterator[T]{
+		index:         s.size - 1,
+		modifications: s.modifications,
+		stack:         s,
+	}
 }
 
 func (s *stack[T]) Size() int {
 	return s.size
 }
+
+func (s *stack[T]) UnmarshalBinary(data []byte) error {
+	return wire.Unmarshal(data, wire.Stack, s.reset, s.Push)
+}
+
+func (s *stack[T]) UnmarshalJSON(data []byte) error {
+	var elements []T
+	if err := json.Unmarshal(data, &elements); err != nil {
+		return err
+	}
+
+	s.reset(len(elements))
+	s.size = copy(s.data, elements)
+
+	return nil
+}
+
+func (s *stack[T]) Validate() error {
+	if s.size < 0 || s.size > len(s.data) {
+		return collections.ErrCorrupted{
+			Index:   -1,
+			Problem: fmt.Sprintf("size %d is outside the backing slice of length %d", s.size, len(s.data)),
+		}
+	}
+
+	return nil
+}
+
+func (s *stack[T]) Values() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for i := s.size - 1; i >= 0; i-- {
+			if i >= s.size {
+				continue
+			}
+			if !yield(s.data[i]) {
+				return
+			}
+		}
+	}
+}
+
+func (s *stack[T]) WriteTo(w io.Writer) (int64, error) {
+	return wire.Write(w, wire.Stack, s.size, slices.Values(s.data[:s.size]))
+}
@@ -3,11 +3,38 @@
 package slicestack_test
 
 import (
+	"bytes"
+	"encoding"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"io"
+	"slices"
 	"testing"
 
+	"github.com/bmoller/collections"
+	"github.com/bmoller/collections/collectionstest"
+	"github.com/bmoller/collections/linkedstack"
 	"github.com/bmoller/collections/slicestack"
 )
 
+func FuzzStack(f *testing.F) {
+	collectionstest.FuzzStack(f, slicestack.New[int])
+}
+
+func FuzzStackNewWithSize(f *testing.F) {
+	collectionstest.FuzzStack(f, func() collections.Stack[int] { return slicestack.NewWithSize[int](0) })
+}
+
+func TestStack(t *testing.T) {
+	t.Run("New", func(t *testing.T) {
+		collectionstest.TestStack(t, slicestack.New[int])
+	})
+	t.Run("NewWithSize", func(t *testing.T) {
+		collectionstest.TestStack(t, func() collections.Stack[int] { return slicestack.NewWithSize[int](0) })
+	})
+}
+
 func TestStackNewWithSize(t *testing.T) {
 	stack := slicestack.NewWithSize[int](1000)
 
@@ -37,71 +64,166 @@ func TestStackNewWithSize(t *testing.T) {
 	}
 }
 
-func TestStackEmpty(t *testing.T) {
+func TestStackValues(t *testing.T) {
 	stack := slicestack.New[int]()
-	if !stack.Empty() {
-		t.Fatal("expected new Stack to be empty")
+	for i := 0; i < 1000; i++ {
+		stack.Push(i)
 	}
-	stack.Push(0)
-	if stack.Empty() {
-		t.Fatal("expected Stack not to be empty after Push")
+
+	for range stack.Values() {
+		stack.Pop()
+		stack.Pop()
+	}
+	for i := 0; i < 1000; i++ {
+		stack.Push(i)
+	}
+	for range stack.All() {
+		stack.Pop()
+		stack.Pop()
+	}
+	if !stack.Empty() {
+		t.Fatal("expected Stack to be empty after popping during iteration")
 	}
 }
 
-func TestStackPeek(t *testing.T) {
+func TestStackMarshalJSON(t *testing.T) {
 	stack := slicestack.New[int]()
+	if data, err := json.Marshal(stack); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if string(data) != "[]" {
+		t.Fatalf("expected empty stack to encode as %s but got %s", "[]", data)
+	}
 
-	for i := 0; i < 100; i++ {
+	for i := 0; i < 1000; i++ {
 		stack.Push(i)
 	}
-	for i := 99; i > -1; i-- {
-		if element, err := stack.Peek(); err != nil {
-			t.Fatalf("expected element on Peek call %d but received error: %s", i, err)
-		} else {
-			if element != i {
-				t.Fatalf("expected element %d but got %d", i, element)
-			}
-		}
-		stack.Pop()
+	data, err := json.Marshal(stack)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
 	}
-	if _, err := stack.Peek(); err == nil {
-		t.Fatal("Peek on an empty stack should return an error")
+	var elements []int
+	if err := json.Unmarshal(data, &elements); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if len(elements) != 1000 {
+		t.Fatalf("expected %d encoded elements but got %d", 1000, len(elements))
+	}
+	for i, element := range elements {
+		if element != i {
+			t.Fatalf("expected element with value %d at index %d but got %d", i, i, element)
+		}
 	}
 }
 
-func TestStackPop(t *testing.T) {
+func TestStackUnmarshalJSON(t *testing.T) {
 	stack := slicestack.New[int]()
-
-	for i := 0; i < 100; i++ {
-		stack.Push(i)
+	if err := json.Unmarshal([]byte("[0,1,2]"), stack); err != nil {
+		t.Fatalf("unexpected error: %s", err)
 	}
-	for i := 99; i > -1; i-- {
+	stack.Push(3)
+	for i := 3; i > -1; i-- {
 		if element, err := stack.Pop(); err != nil {
-			t.Fatalf("expected element on Pop call %d but received error: %s", i, err)
+			t.Fatalf("unexpected error: %s", err)
 		} else if element != i {
-			t.Fatalf("expected element %d but got %d", i, element)
-		} else if stack.Size() != i {
-			t.Fatalf("expected size %d but got %d", i, stack.Size())
+			t.Fatalf("expected element with value %d but got %d", i, element)
 		}
 	}
-	if _, err := stack.Pop(); err == nil {
-		t.Fatal("Pop on an empty stack should return an error")
+	for i := 0; i < 4; i++ {
+		stack.Push(i)
+	}
+
+	if err := json.Unmarshal([]byte(`{"a":1}`), stack); err == nil {
+		t.Fatal("expected error from decoding an object")
+	} else if stack.Size() != 4 {
+		t.Fatalf("expected failed decode to leave size %d but got %d", 4, stack.Size())
+	}
+
+	payload := struct {
+		Items collections.Stack[int]
+	}{
+		Items: slicestack.New[int](),
+	}
+	payload.Items.Push(99)
+	if err := json.Unmarshal([]byte(`{"Items":[5,6]}`), &payload); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if payload.Items.Size() != 2 {
+		t.Fatalf("expected decoding to replace contents leaving size %d but got %d", 2, payload.Items.Size())
+	}
+	if data, err := json.Marshal(payload); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if string(data) != `{"Items":[5,6]}` {
+		t.Fatalf("expected round trip to produce %s but got %s", `{"Items":[5,6]}`, data)
 	}
 }
 
-func TestStackPush(t *testing.T) {
+func TestStackBinaryEncoding(t *testing.T) {
 	stack := slicestack.New[int]()
 	for i := 0; i < 1000; i++ {
 		stack.Push(i)
 	}
+	expected := slices.Collect(stack.Values())
+
+	data, err := stack.(encoding.BinaryMarshaler).MarshalBinary()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	decoded := slicestack.New[int]()
+	if err := decoded.(encoding.BinaryUnmarshaler).UnmarshalBinary(data); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if actual := slices.Collect(decoded.Values()); !slices.Equal(actual, expected) {
+		t.Fatal("decoded stack does not match the original")
+	}
+	decoded.Push(1000)
+	other := linkedstack.New[int]()
+	if err := other.(encoding.BinaryUnmarshaler).UnmarshalBinary(data); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if actual := slices.Collect(other.Values()); !slices.Equal(actual, expected) {
+		t.Fatal("stack decoded by linkedstack does not match the original")
+	}
+
+	if err := decoded.(encoding.BinaryUnmarshaler).UnmarshalBinary([]byte("not a stack")); err == nil || !errors.Is(err, collections.ErrInvalidEncoding) {
+		t.Fatalf("expected ErrInvalidEncoding from invalid data but got: %v", err)
+	}
 }
 
-func TestStackSize(t *testing.T) {
+func TestStackGob(t *testing.T) {
+	stack := slicestack.New[string]()
+	for _, element := range []string{"a", "b", "c"} {
+		stack.Push(element)
+	}
+
+	var buffer bytes.Buffer
+	if err := gob.NewEncoder(&buffer).Encode(stack); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	decoded := slicestack.New[string]()
+	if err := gob.NewDecoder(&buffer).Decode(decoded); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if !slices.Equal(slices.Collect(decoded.Values()), slices.Collect(stack.Values())) {
+		t.Fatal("decoded stack does not match the original")
+	}
+}
+
+func TestStackWriteTo(t *testing.T) {
 	stack := slicestack.New[int]()
-	for i := 1; i < 1000; i++ {
+	for i := 0; i < 1000; i++ {
 		stack.Push(i)
-		if stack.Size() != i {
-			t.Fatalf("expected stack size %d but got %d", i, stack.Size())
-		}
+	}
+
+	var buffer bytes.Buffer
+	written, err := stack.(io.WriterTo).WriteTo(&buffer)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if written != int64(buffer.Len()) {
+		t.Fatalf("expected WriteTo to report %d bytes but got %d", buffer.Len(), written)
+	}
+
+	decoded := slicestack.New[int]()
+	decoded.Push(-1)
+	if read, err := decoded.(io.ReaderFrom).ReadFrom(&buffer); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if read != written {
+		t.Fatalf("expected ReadFrom to report %d bytes but got %d", written, read)
+	} else if !slices.Equal(slices.Collect(decoded.Values()), slices.Collect(stack.Values())) {
+		t.Fatal("decoded stack does not match the original")
 	}
 }
@@ -0,0 +1,42 @@
+// ©2022 Brandon Moller
+
+package slicestack
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/bmoller/collections"
+)
+
+func TestStackValidateCorrupted(t *testing.T) {
+	tests := []struct {
+		name    string
+		corrupt func(*stack[int])
+		problem string
+	}{
+		{"NegativeSize", func(s *stack[int]) { s.size = -1 }, "size -1 is outside the backing slice of length 5"},
+		{"SizeBeyondSlice", func(s *stack[int]) { s.size = 6 }, "size 6 is outside the backing slice of length 5"},
+		{"TruncatedSlice", func(s *stack[int]) { s.data = s.data[:2] }, "size 5 is outside the backing slice of length 2"},
+		{"NilSlice", func(s *stack[int]) { s.data = nil }, "size 5 is outside the backing slice of length 0"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			stack := &stack[int]{data: make([]int, 5)}
+			for i := 0; i < 5; i++ {
+				stack.Push(i)
+			}
+			if err := stack.Validate(); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+
+			test.corrupt(stack)
+			var corrupted collections.ErrCorrupted
+			if err := stack.Validate(); err == nil || !errors.As(err, &corrupted) {
+				t.Fatalf("expected error %T but got %v", corrupted, err)
+			} else if corrupted.Index != -1 || corrupted.Problem != test.problem {
+				t.Fatalf("expected corruption at index %d (%q) but got index %d (%q)", -1, test.problem, corrupted.Index, corrupted.Problem)
+			}
+		})
+	}
+}
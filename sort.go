@@ -0,0 +1,45 @@
+// ©2022 Brandon Moller
+
+package collections
+
+/*
+IsSorted reports whether the elements of l are in ascending order according to less.
+*/
+func IsSorted[T comparable](l List[T], less func(a, b T) bool) bool {
+	var (
+		previous T
+		started  bool
+	)
+	for element := range l.Values() {
+		if started && less(element, previous) {
+			return false
+		}
+		previous, started = element, true
+	}
+
+	return true
+}
+
+/*
+BinarySearch searches for target in l, which must be sorted in ascending order according to less.
+It returns the index at which target was found, or the index at which it would be inserted, and reports whether it was found.
+
+The search makes O(log n) calls to Get, so it is only efficient for Lists with constant time index access, such as those from slicelist.
+*/
+func BinarySearch[T comparable](l List[T], target T, less func(a, b T) bool) (int, bool) {
+	low, high := 0, l.Size()
+	for low < high {
+		middle := int(uint(low+high) >> 1)
+		if element, _ := l.Get(middle); less(element, target) {
+			low = middle + 1
+		} else {
+			high = middle
+		}
+	}
+	if low < l.Size() {
+		element, _ := l.Get(low)
+		return low, !less(target, element)
+	}
+
+	return low, false
+}
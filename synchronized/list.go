@@ -0,0 +1,185 @@
+// ©2022 Brandon Moller
+
+package synchronized
+
+import (
+	"iter"
+	"slices"
+	"sync"
+
+	"github.com/bmoller/collections"
+)
+
+/*
+A LockedList is a [collections.List] that is safe for concurrent use.
+WithLock calls f with the write lock held, passing the wrapped List.
+*/
+type LockedList[T comparable] interface {
+	collections.List[T]
+
+	WithLock(f func(inner collections.List[T]))
+}
+
+type list[T comparable] struct {
+	inner collections.List[T]
+	lock  sync.RWMutex
+}
+
+/*
+List wraps l so that it can be used from multiple goroutines.
+*/
+func List[T comparable](l collections.List[T]) LockedList[T] {
+	return &list[T]{
+		inner: l,
+	}
+}
+
+func (l *list[T]) snapshot() []T {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+
+	return slices.Collect(l.inner.Values())
+}
+
+func (l *list[T]) Add(item T) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.inner.Add(item)
+}
+
+func (l *list[T]) All() iter.Seq2[int, T] {
+	return all(l.snapshot)
+}
+
+func (l *list[T]) Backward() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		elements := l.snapshot()
+		for i := len(elements) - 1; i >= 0; i-- {
+			if !yield(i, elements[i]) {
+				return
+			}
+		}
+	}
+}
+
+func (l *list[T]) Clear() {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.inner.Clear()
+}
+
+func (l *list[T]) Contains(item T) bool {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+
+	return l.inner.Contains(item)
+}
+
+func (l *list[T]) Empty() bool {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+
+	return l.inner.Empty()
+}
+
+func (l *list[T]) Get(index int) (T, error) {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+
+	return l.inner.Get(index)
+}
+
+func (l *list[T]) IndexFunc(f func(T) bool) int {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+
+	return l.inner.IndexFunc(f)
+}
+
+func (l *list[T]) IndexOf(item T) int {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+
+	return l.inner.IndexOf(item)
+}
+
+func (l *list[T]) Insert(index int, item T) error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	return l.inner.Insert(index, item)
+}
+
+func (l *list[T]) Iterator() collections.Iterator[T] {
+	return iterator(&l.lock, l.inner)
+}
+
+func (l *list[T]) LastIndexOf(item T) int {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+
+	return l.inner.LastIndexOf(item)
+}
+
+func (l *list[T]) Remove(index int) (T, error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	return l.inner.Remove(index)
+}
+
+func (l *list[T]) RemovingIterator() collections.RemovingIterator[T] {
+	return removing(&l.lock, l.inner)
+}
+
+func (l *list[T]) Set(index int, item T) (T, error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	return l.inner.Set(index, item)
+}
+
+func (l *list[T]) Size() int {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+
+	return l.inner.Size()
+}
+
+func (l *list[T]) Sort(less func(a, b T) bool) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.inner.Sort(less)
+}
+
+func (l *list[T]) SortStable(less func(a, b T) bool) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	l.inner.SortStable(less)
+}
+
+/*
+SubList returns a copy of the range from the wrapped List.
+The copy is not shared with any other goroutine, so it is returned without a wrapper.
+*/
+func (l *list[T]) SubList(start, end int) (collections.List[T], error) {
+	l.lock.RLock()
+	defer l.lock.RUnlock()
+
+	return l.inner.SubList(start, end)
+}
+
+func (l *list[T]) Values() iter.Seq[T] {
+	return values(l.snapshot)
+}
+
+func (l *list[T]) WithLock(f func(inner collections.List[T])) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	f(l.inner)
+}
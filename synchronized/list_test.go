@@ -0,0 +1,144 @@
+// ©2022 Brandon Moller
+
+package synchronized_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/bmoller/collections"
+	"github.com/bmoller/collections/collectionstest"
+	"github.com/bmoller/collections/linkedlist"
+	"github.com/bmoller/collections/slicelist"
+	"github.com/bmoller/collections/synchronized"
+)
+
+func FuzzList(f *testing.F) {
+	collectionstest.FuzzList(f, func() collections.List[int] { return synchronized.List(slicelist.New[int]()) })
+}
+
+func TestList(t *testing.T) {
+	t.Run("LinkedList", func(t *testing.T) {
+		collectionstest.TestList(t, func() collections.List[int] { return synchronized.List(linkedlist.New[int]()) })
+	})
+	t.Run("SliceList", func(t *testing.T) {
+		collectionstest.TestList(t, func() collections.List[int] { return synchronized.List(slicelist.New[int]()) })
+	})
+}
+
+func TestListConcurrent(t *testing.T) {
+	list := synchronized.List(slicelist.New[int]())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				list.Add(j)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				list.Contains(j)
+				list.Size()
+				for range list.Values() {
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if list.Size() != 10000 {
+		t.Fatalf("expected list size %d but got %d", 10000, list.Size())
+	}
+}
+
+func TestListSnapshot(t *testing.T) {
+	list := synchronized.List(slicelist.New[int]())
+	for i := 0; i < 100; i++ {
+		list.Add(i)
+	}
+
+	itr := list.Iterator()
+	expected := 0
+	for i, element := range list.All() {
+		if i != expected || element != expected {
+			t.Fatalf("expected index and element %d but got %d and %d", expected, i, element)
+		}
+		list.Add(i)
+		expected++
+	}
+	if expected != 100 {
+		t.Fatalf("expected All to produce the %d elements present when it started but got %d", 100, expected)
+	}
+
+	expected = 0
+	for element := range list.Values() {
+		if element != expected%100 {
+			t.Fatalf("expected element %d but got %d", expected%100, element)
+		}
+		list.Clear()
+		expected++
+	}
+	if expected != 200 {
+		t.Fatalf("expected Values to produce %d elements but got %d", 200, expected)
+	}
+
+	// unlike the sequences, the iterator created before the modifications cannot continue
+	if _, err := itr(); err == nil || !errors.Is(err, collections.ErrConcurrentModification) {
+		t.Fatalf("expected ErrConcurrentModification from Iterator but got: %v", err)
+	}
+
+	for i := 0; i < 100; i++ {
+		list.Add(i)
+	}
+	expected = 99
+	for i, element := range list.Backward() {
+		if i != expected || element != expected {
+			t.Fatalf("expected index and element %d but got %d and %d", expected, i, element)
+		}
+		expected--
+	}
+	for range list.All() {
+		break
+	}
+	for range list.Backward() {
+		break
+	}
+	for range list.Values() {
+		break
+	}
+}
+
+func TestListWithLock(t *testing.T) {
+	list := synchronized.List(slicelist.New[int]())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			list.WithLock(func(inner collections.List[int]) {
+				if !inner.Contains(0) {
+					inner.Add(0)
+				}
+			})
+		}()
+	}
+	wg.Wait()
+
+	if list.Size() != 1 {
+		t.Fatalf("expected exactly %d element after check-then-act but got %d", 1, list.Size())
+	}
+
+	itr := list.Iterator()
+	list.WithLock(func(inner collections.List[int]) {
+		inner.Add(1)
+	})
+	if _, err := itr(); err == nil || !errors.Is(err, collections.ErrConcurrentModification) {
+		t.Fatalf("expected ErrConcurrentModification after WithLock but got: %v", err)
+	}
+}
@@ -0,0 +1,100 @@
+// ©2022 Brandon Moller
+
+package synchronized
+
+import (
+	"iter"
+	"slices"
+	"sync"
+
+	"github.com/bmoller/collections"
+)
+
+/*
+A LockedQueue is a [collections.Queue] that is safe for concurrent use.
+WithLock calls f with the write lock held, passing the wrapped Queue.
+*/
+type LockedQueue[T comparable] interface {
+	collections.Queue[T]
+
+	WithLock(f func(inner collections.Queue[T]))
+}
+
+type queue[T comparable] struct {
+	inner collections.Queue[T]
+	lock  sync.RWMutex
+}
+
+/*
+Queue wraps q so that it can be used from multiple goroutines.
+*/
+func Queue[T comparable](q collections.Queue[T]) LockedQueue[T] {
+	return &queue[T]{
+		inner: q,
+	}
+}
+
+func (q *queue[T]) snapshot() []T {
+	q.lock.RLock()
+	defer q.lock.RUnlock()
+
+	return slices.Collect(q.inner.Values())
+}
+
+func (q *queue[T]) All() iter.Seq2[int, T] {
+	return all(q.snapshot)
+}
+
+func (q *queue[T]) Empty() bool {
+	q.lock.RLock()
+	defer q.lock.RUnlock()
+
+	return q.inner.Empty()
+}
+
+func (q *queue[T]) Iterator() collections.Iterator[T] {
+	return iterator(&q.lock, q.inner)
+}
+
+func (q *queue[T]) Peek() (T, error) {
+	q.lock.RLock()
+	defer q.lock.RUnlock()
+
+	return q.inner.Peek()
+}
+
+func (q *queue[T]) Pop() (T, error) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	return q.inner.Pop()
+}
+
+func (q *queue[T]) Push(item T) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	q.inner.Push(item)
+}
+
+func (q *queue[T]) RemovingIterator() collections.RemovingIterator[T] {
+	return removing(&q.lock, q.inner)
+}
+
+func (q *queue[T]) Size() int {
+	q.lock.RLock()
+	defer q.lock.RUnlock()
+
+	return q.inner.Size()
+}
+
+func (q *queue[T]) Values() iter.Seq[T] {
+	return values(q.snapshot)
+}
+
+func (q *queue[T]) WithLock(f func(inner collections.Queue[T])) {
+	q.lock.Lock()
+	defer q.lock.Unlock()
+
+	f(q.inner)
+}
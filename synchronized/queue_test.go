@@ -0,0 +1,162 @@
+// ©2022 Brandon Moller
+
+package synchronized_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bmoller/collections"
+	"github.com/bmoller/collections/blockingqueue"
+	"github.com/bmoller/collections/collectionstest"
+	"github.com/bmoller/collections/linkedqueue"
+	"github.com/bmoller/collections/synchronized"
+)
+
+func FuzzQueue(f *testing.F) {
+	collectionstest.FuzzQueue(f, func() collections.Queue[int] { return synchronized.Queue(linkedqueue.New[int]()) })
+}
+
+func TestQueue(t *testing.T) {
+	collectionstest.TestQueue(t, func() collections.Queue[int] { return synchronized.Queue(linkedqueue.New[int]()) })
+}
+
+func TestQueueConcurrent(t *testing.T) {
+	queue := synchronized.Queue(linkedqueue.New[int]())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				queue.Push(j)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				queue.Peek()
+				queue.Empty()
+				for range queue.Values() {
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if queue.Size() != 10000 {
+		t.Fatalf("expected size %d but got %d", 10000, queue.Size())
+	}
+	for i := 0; i < 10000; i++ {
+		if _, err := queue.Pop(); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	if _, err := queue.Pop(); !errors.Is(err, collections.ErrEmptyQueue) {
+		t.Fatalf("expected ErrEmptyQueue from Pop but got: %v", err)
+	}
+}
+
+func TestQueueFullBlockingQueue(t *testing.T) {
+	inner := blockingqueue.New[int](1)
+	queue := synchronized.Queue[int](inner)
+	queue.Push(0)
+
+	// Push holds the wrapper's lock, so it must not wait for a Pop that needs the same lock
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		queue.Push(1)
+		if element, err := queue.Pop(); err != nil {
+			t.Errorf("unexpected error: %s", err)
+		} else if element != 0 {
+			t.Errorf("expected element with value %d but got %d", 0, element)
+		}
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected Push and Pop on a full wrapped BlockingQueue to return")
+	}
+	if inner.Dropped() != 1 {
+		t.Fatalf("expected %d dropped element but got %d", 1, inner.Dropped())
+	}
+}
+
+func TestQueueRemovingIteratorUnsupported(t *testing.T) {
+	// embedding the interface hides the RemovingIterator of the linkedqueue
+	queue := synchronized.Queue[int](struct{ collections.Queue[int] }{linkedqueue.New[int]()})
+	queue.Push(0)
+
+	itr := queue.(collections.RemovingIterable[int]).RemovingIterator()
+	if element, err := itr.Next(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if element != 0 {
+		t.Fatalf("expected element with value %d but got %d", 0, element)
+	}
+	if err := itr.Remove(); err == nil || !errors.Is(err, errors.ErrUnsupported) {
+		t.Fatalf("expected ErrUnsupported from Remove on a wrapped Queue without RemovingIterator but got: %v", err)
+	} else if queue.Size() != 1 {
+		t.Fatalf("expected queue size %d but got %d", 1, queue.Size())
+	}
+}
+
+func TestQueueSnapshot(t *testing.T) {
+	queue := synchronized.Queue(linkedqueue.New[int]())
+	for i := 0; i < 100; i++ {
+		queue.Push(i)
+	}
+
+	itr := queue.Iterator()
+	count := 0
+	for i, element := range queue.All() {
+		if i != count {
+			t.Fatalf("expected index %d but got %d", count, i)
+		} else if element != i {
+			t.Fatalf("expected element %d but got %d", i, element)
+		}
+		queue.Pop()
+		count++
+	}
+	if count != 100 || !queue.Empty() {
+		t.Fatalf("expected All to produce the %d elements present when it started but got %d", 100, count)
+	}
+
+	// unlike the sequences, the iterator created before the modifications cannot continue
+	if _, err := itr(); err == nil || !errors.Is(err, collections.ErrConcurrentModification) {
+		t.Fatalf("expected ErrConcurrentModification from Iterator but got: %v", err)
+	}
+
+	queue.Push(0)
+	for range queue.All() {
+		break
+	}
+	for range queue.Values() {
+		break
+	}
+}
+
+func TestQueueWithLock(t *testing.T) {
+	queue := synchronized.Queue(linkedqueue.New[int]())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			queue.WithLock(func(inner collections.Queue[int]) {
+				if inner.Empty() {
+					inner.Push(0)
+				}
+			})
+		}()
+	}
+	wg.Wait()
+
+	if queue.Size() != 1 {
+		t.Fatalf("expected exactly %d element after check-then-act but got %d", 1, queue.Size())
+	}
+}
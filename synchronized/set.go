@@ -0,0 +1,107 @@
+// ©2022 Brandon Moller
+
+package synchronized
+
+import (
+	"iter"
+	"slices"
+	"sync"
+
+	"github.com/bmoller/collections"
+)
+
+/*
+A LockedSet is a [collections.Set] that is safe for concurrent use.
+WithLock calls f with the write lock held, passing the wrapped Set.
+*/
+type LockedSet[T comparable] interface {
+	collections.Set[T]
+
+	WithLock(f func(inner collections.Set[T]))
+}
+
+type set[T comparable] struct {
+	inner collections.Set[T]
+	lock  sync.RWMutex
+}
+
+/*
+Set wraps s so that it can be used from multiple goroutines.
+*/
+func Set[T comparable](s collections.Set[T]) LockedSet[T] {
+	return &set[T]{
+		inner: s,
+	}
+}
+
+func (s *set[T]) snapshot() []T {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return slices.Collect(s.inner.Values())
+}
+
+func (s *set[T]) Add(item T) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.inner.Add(item)
+}
+
+func (s *set[T]) All() iter.Seq2[int, T] {
+	return all(s.snapshot)
+}
+
+func (s *set[T]) Contains(item T) bool {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.inner.Contains(item)
+}
+
+func (s *set[T]) Empty() bool {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.inner.Empty()
+}
+
+func (s *set[T]) Iterator() collections.Iterator[T] {
+	return iterator(&s.lock, s.inner)
+}
+
+func (s *set[T]) Pop() (T, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.inner.Pop()
+}
+
+func (s *set[T]) Remove(item T) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.inner.Remove(item)
+}
+
+func (s *set[T]) RemovingIterator() collections.RemovingIterator[T] {
+	return removing(&s.lock, s.inner)
+}
+
+func (s *set[T]) Size() int {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.inner.Size()
+}
+
+func (s *set[T]) Values() iter.Seq[T] {
+	return values(s.snapshot)
+}
+
+func (s *set[T]) WithLock(f func(inner collections.Set[T])) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	f(s.inner)
+}
@@ -0,0 +1,117 @@
+// ©2022 Brandon Moller
+
+package synchronized_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/bmoller/collections"
+	"github.com/bmoller/collections/collectionstest"
+	"github.com/bmoller/collections/mapset"
+	"github.com/bmoller/collections/synchronized"
+)
+
+func FuzzSet(f *testing.F) {
+	collectionstest.FuzzSet(f, func() collections.Set[int] { return synchronized.Set(mapset.New[int]()) })
+}
+
+func TestSet(t *testing.T) {
+	collectionstest.TestSet(t, func() collections.Set[int] { return synchronized.Set(mapset.New[int]()) })
+}
+
+func TestSetConcurrent(t *testing.T) {
+	set := synchronized.Set(mapset.New[int]())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				set.Add(j)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				set.Contains(j)
+				set.Empty()
+				for range set.Values() {
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if set.Size() != 1000 {
+		t.Fatalf("expected size %d but got %d", 1000, set.Size())
+	}
+	for i := 0; i < 500; i++ {
+		set.Remove(i)
+	}
+	for i := 0; i < 500; i++ {
+		if _, err := set.Pop(); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	if _, err := set.Pop(); !errors.Is(err, collections.ErrEmptySet) {
+		t.Fatalf("expected ErrEmptySet from Pop but got: %v", err)
+	}
+}
+
+func TestSetSnapshot(t *testing.T) {
+	set := synchronized.Set(mapset.New[int]())
+	for i := 0; i < 100; i++ {
+		set.Add(i)
+	}
+
+	itr := set.Iterator()
+	seen := make(map[int]bool)
+	for i, element := range set.All() {
+		if i != len(seen) {
+			t.Fatalf("expected index %d but got %d", len(seen), i)
+		}
+		seen[element] = true
+		set.Remove(element)
+		set.Add(element + 100)
+	}
+	if len(seen) != 100 {
+		t.Fatalf("expected All to produce the %d elements present when it started but got %d", 100, len(seen))
+	}
+
+	// unlike the sequences, the iterator created before the modifications cannot continue
+	if _, err := itr(); err == nil || !errors.Is(err, collections.ErrConcurrentModification) {
+		t.Fatalf("expected ErrConcurrentModification from Iterator but got: %v", err)
+	}
+
+	for range set.All() {
+		break
+	}
+	for range set.Values() {
+		break
+	}
+}
+
+func TestSetWithLock(t *testing.T) {
+	set := synchronized.Set(mapset.New[int]())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			set.WithLock(func(inner collections.Set[int]) {
+				inner.Add(inner.Size())
+			})
+		}()
+	}
+	wg.Wait()
+
+	for i := 0; i < 100; i++ {
+		if !set.Contains(i) {
+			t.Fatalf("expected set to contain %d", i)
+		}
+	}
+}
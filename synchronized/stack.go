@@ -0,0 +1,100 @@
+// ©2022 Brandon Moller
+
+package synchronized
+
+import (
+	"iter"
+	"slices"
+	"sync"
+
+	"github.com/bmoller/collections"
+)
+
+/*
+A LockedStack is a [collections.Stack] that is safe for concurrent use.
+WithLock calls f with the write lock held, passing the wrapped Stack.
+*/
+type LockedStack[T comparable] interface {
+	collections.Stack[T]
+
+	WithLock(f func(inner collections.Stack[T]))
+}
+
+type stack[T comparable] struct {
+	inner collections.Stack[T]
+	lock  sync.RWMutex
+}
+
+/*
+Stack wraps s so that it can be used from multiple goroutines.
+*/
+func Stack[T comparable](s collections.Stack[T]) LockedStack[T] {
+	return &stack[T]{
+		inner: s,
+	}
+}
+
+func (s *stack[T]) snapshot() []T {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return slices.Collect(s.inner.Values())
+}
+
+func (s *stack[T]) All() iter.Seq2[int, T] {
+	return all(s.snapshot)
+}
+
+func (s *stack[T]) Empty() bool {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.inner.Empty()
+}
+
+func (s *stack[T]) Iterator() collections.Iterator[T] {
+	return iterator(&s.lock, s.inner)
+}
+
+func (s *stack[T]) Peek() (T, error) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.inner.Peek()
+}
+
+func (s *stack[T]) Pop() (T, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	return s.inner.Pop()
+}
+
+func (s *stack[T]) Push(item T) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.inner.Push(item)
+}
+
+func (s *stack[T]) RemovingIterator() collections.RemovingIterator[T] {
+	return removing(&s.lock, s.inner)
+}
+
+func (s *stack[T]) Size() int {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	return s.inner.Size()
+}
+
+func (s *stack[T]) Values() iter.Seq[T] {
+	return values(s.snapshot)
+}
+
+func (s *stack[T]) WithLock(f func(inner collections.Stack[T])) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	f(s.inner)
+}
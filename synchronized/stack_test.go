@@ -0,0 +1,116 @@
+// ©2022 Brandon Moller
+
+package synchronized_test
+
+import (
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/bmoller/collections"
+	"github.com/bmoller/collections/collectionstest"
+	"github.com/bmoller/collections/linkedstack"
+	"github.com/bmoller/collections/synchronized"
+)
+
+func FuzzStack(f *testing.F) {
+	collectionstest.FuzzStack(f, func() collections.Stack[int] { return synchronized.Stack(linkedstack.New[int]()) })
+}
+
+func TestStack(t *testing.T) {
+	collectionstest.TestStack(t, func() collections.Stack[int] { return synchronized.Stack(linkedstack.New[int]()) })
+}
+
+func TestStackConcurrent(t *testing.T) {
+	stack := synchronized.Stack(linkedstack.New[int]())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				stack.Push(j)
+			}
+		}()
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				stack.Peek()
+				stack.Empty()
+				for range stack.Values() {
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	if stack.Size() != 10000 {
+		t.Fatalf("expected size %d but got %d", 10000, stack.Size())
+	}
+	for i := 0; i < 10000; i++ {
+		if _, err := stack.Pop(); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		}
+	}
+	if _, err := stack.Pop(); !errors.Is(err, collections.ErrEmptyStack) {
+		t.Fatalf("expected ErrEmptyStack from Pop but got: %v", err)
+	}
+}
+
+func TestStackSnapshot(t *testing.T) {
+	stack := synchronized.Stack(linkedstack.New[int]())
+	for i := 0; i < 100; i++ {
+		stack.Push(i)
+	}
+
+	itr := stack.Iterator()
+	count := 0
+	for i, element := range stack.All() {
+		if i != count {
+			t.Fatalf("expected index %d but got %d", count, i)
+		} else if element != 99-i {
+			t.Fatalf("expected element %d but got %d", 99-i, element)
+		}
+		stack.Pop()
+		count++
+	}
+	if count != 100 || !stack.Empty() {
+		t.Fatalf("expected All to produce the %d elements present when it started but got %d", 100, count)
+	}
+
+	// unlike the sequences, the iterator created before the modifications cannot continue
+	if _, err := itr(); err == nil || !errors.Is(err, collections.ErrConcurrentModification) {
+		t.Fatalf("expected ErrConcurrentModification from Iterator but got: %v", err)
+	}
+
+	stack.Push(0)
+	for range stack.All() {
+		break
+	}
+	for range stack.Values() {
+		break
+	}
+}
+
+func TestStackWithLock(t *testing.T) {
+	stack := synchronized.Stack(linkedstack.New[int]())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 100; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			stack.WithLock(func(inner collections.Stack[int]) {
+				if inner.Empty() {
+					inner.Push(0)
+				}
+			})
+		}()
+	}
+	wg.Wait()
+
+	if stack.Size() != 1 {
+		t.Fatalf("expected exactly %d element after check-then-act but got %d", 1, stack.Size())
+	}
+}
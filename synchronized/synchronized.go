@@ -0,0 +1,115 @@
+// ©2022 Brandon Moller
+
+/*
+Package synchronized provides wrappers that make any implementation of the collections interfaces safe for concurrent use.
+
+Each wrapper guards every method of the collection it wraps with a [sync.RWMutex].
+Methods that only read from the collection, such as Get, Contains, Peek and Size, hold the read lock and can run in parallel; all other methods hold the write lock.
+Sequences work on a snapshot of the elements taken under the read lock, so they never observe a partial modification and never hold the lock while the caller's loop body runs.
+Iterators instead hold the read lock for each call to an Iterator of the wrapped collection.
+They fail with ErrConcurrentModification whenever that Iterator would, including after changes made through WithLock, so a wrapper behaves as the collection it wraps.
+
+Wrappers implement [collections.RemovingIterable] by forwarding to the wrapped collection.
+A RemovingIterator holds the read lock for each call to Next and the write lock for each call to Remove.
+If the wrapped collection is not a RemovingIterable, Remove returns [errors.ErrUnsupported] and removes nothing.
+
+A sequence of calls is not atomic, even though each call is.
+WithLock runs a function with the write lock held and direct access to the wrapped collection, for compound operations such as check-then-act.
+The function must not call methods of the wrapper itself, which would deadlock, and must not retain the wrapped collection after it returns.
+
+Once wrapped, a collection should only be accessed through its wrapper.
+*/
+package synchronized
+
+import (
+	"errors"
+	"iter"
+	"sync"
+
+	"github.com/bmoller/collections"
+)
+
+type removingIterator[T comparable] struct {
+	inner collections.RemovingIterator[T]
+	lock  *sync.RWMutex
+}
+
+func (i *removingIterator[T]) Next() (T, error) {
+	i.lock.RLock()
+	defer i.lock.RUnlock()
+
+	return i.inner.Next()
+}
+
+func (i *removingIterator[T]) Remove() error {
+	i.lock.Lock()
+	defer i.lock.Unlock()
+
+	return i.inner.Remove()
+}
+
+// unsupported stands in for the RemovingIterator of a collection that has none, producing the elements of its Iterator.
+type unsupported[T comparable] struct {
+	next collections.Iterator[T]
+}
+
+func (u unsupported[T]) Next() (T, error) {
+	return u.next()
+}
+
+func (u unsupported[T]) Remove() error {
+	return errors.ErrUnsupported
+}
+
+// all ranges over a snapshot, which is taken when the sequence starts rather than when it is created.
+func all[T comparable](snapshot func() []T) iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		for i, element := range snapshot() {
+			if !yield(i, element) {
+				return
+			}
+		}
+	}
+}
+
+// iterator returns an Iterator of inner that holds the read lock for each call, including the one that creates it.
+func iterator[T comparable](lock *sync.RWMutex, inner collections.Iterable[T]) collections.Iterator[T] {
+	lock.RLock()
+	itr := inner.Iterator()
+	lock.RUnlock()
+
+	return func() (T, error) {
+		lock.RLock()
+		defer lock.RUnlock()
+
+		return itr()
+	}
+}
+
+// removing returns a RemovingIterator of inner that holds the read lock for each call to Next, and the write lock for each call to Remove.
+func removing[T comparable](lock *sync.RWMutex, inner collections.Iterable[T]) collections.RemovingIterator[T] {
+	lock.RLock()
+	defer lock.RUnlock()
+
+	itr := &removingIterator[T]{
+		lock: lock,
+	}
+	if iterable, ok := inner.(collections.RemovingIterable[T]); ok {
+		itr.inner = iterable.RemovingIterator()
+	} else {
+		itr.inner = unsupported[T]{inner.Iterator()}
+	}
+
+	return itr
+}
+
+// values ranges over a snapshot, which is taken when the sequence starts rather than when it is created.
+func values[T comparable](snapshot func() []T) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, element := range snapshot() {
+			if !yield(element) {
+				return
+			}
+		}
+	}
+}
@@ -0,0 +1,133 @@
+// ©2022 Brandon Moller
+
+/*
+Package treemap is an implementation of [collections.SortedMap] backed by a balanced binary search tree.
+
+Keys are ordered by a compare function supplied when the map is created.
+The function must return a negative number when a sorts before b, a positive number when a sorts after b, and zero when they are equal, as [cmp.Compare] does.
+The tree is kept AVL-balanced, so Put, Get, Delete and the nearest-key queries all run in O(log n) time.
+*/
+package treemap
+
+import (
+	"iter"
+
+	"github.com/bmoller/collections"
+	"github.com/bmoller/collections/internal/avl"
+)
+
+type treeMap[K comparable, V any] struct {
+	tree *avl.Tree[K, V]
+}
+
+/*
+New creates an empty SortedMap with keys ordered by compare.
+*/
+func New[K comparable, V any](compare func(a, b K) int) collections.SortedMap[K, V] {
+	return &treeMap[K, V]{
+		tree: avl.New[K, V](compare),
+	}
+}
+
+// nearest finds the entry with the closest key below (or above) key, returning key's own entry if inclusive is true and it is present.
+func (m *treeMap[K, V]) nearest(key K, inclusive, below bool) (K, V, error) {
+	found, value, ok := m.tree.Nearest(key, inclusive, below)
+	if !ok {
+		return found, value, collections.ErrNoSuchElement
+	}
+
+	return found, value, nil
+}
+
+func (m *treeMap[K, V]) Backward() iter.Seq2[K, V] {
+	return m.tree.Descend
+}
+
+func (m *treeMap[K, V]) Ceiling(key K) (K, V, error) {
+	return m.nearest(key, true, false)
+}
+
+func (m *treeMap[K, V]) Clear() {
+	m.tree.Clear()
+}
+
+func (m *treeMap[K, V]) ContainsKey(key K) bool {
+	_, ok := m.tree.Get(key)
+
+	return ok
+}
+
+func (m *treeMap[K, V]) Delete(key K) {
+	m.tree.Delete(key)
+}
+
+func (m *treeMap[K, V]) Empty() bool {
+	return m.tree.Size() == 0
+}
+
+func (m *treeMap[K, V]) Entries() iter.Seq2[K, V] {
+	return m.tree.Ascend
+}
+
+func (m *treeMap[K, V]) Floor(key K) (K, V, error) {
+	return m.nearest(key, true, true)
+}
+
+func (m *treeMap[K, V]) Get(key K) (V, error) {
+	value, ok := m.tree.Get(key)
+	if !ok {
+		return value, collections.ErrKeyNotFound
+	}
+
+	return value, nil
+}
+
+func (m *treeMap[K, V]) Higher(key K) (K, V, error) {
+	return m.nearest(key, false, false)
+}
+
+func (m *treeMap[K, V]) Keys() iter.Seq[K] {
+	return func(yield func(K) bool) {
+		m.tree.Ascend(func(key K, _ V) bool {
+			return yield(key)
+		})
+	}
+}
+
+func (m *treeMap[K, V]) Lower(key K) (K, V, error) {
+	return m.nearest(key, false, true)
+}
+
+func (m *treeMap[K, V]) Max() (K, V, error) {
+	key, value, ok := m.tree.Max()
+	if !ok {
+		return key, value, collections.ErrEmptyMap
+	}
+
+	return key, value, nil
+}
+
+func (m *treeMap[K, V]) Min() (K, V, error) {
+	key, value, ok := m.tree.Min()
+	if !ok {
+		return key, value, collections.ErrEmptyMap
+	}
+
+	return key, value, nil
+}
+
+func (m *treeMap[K, V]) Put(key K, value V) {
+	m.tree.Put(key, value)
+}
+
+func (m *treeMap[K, V]) Size() int {
+	return m.tree.Size()
+}
+
+func (m *treeMap[K, V]) Values() iter.Seq[V] {
+	return func(yield func(V) bool) {
+		m.tree.Ascend(func(_ K, value V) bool {
+			return yield(value)
+		})
+	}
+}
@@ -0,0 +1,315 @@
+// ©2022 Brandon Moller
+
+package treemap_test
+
+import (
+	"cmp"
+	"errors"
+	"math/rand"
+	"strconv"
+	"testing"
+
+	"github.com/bmoller/collections"
+	"github.com/bmoller/collections/collectionstest"
+	"github.com/bmoller/collections/treemap"
+)
+
+func FuzzMap(f *testing.F) {
+	collectionstest.FuzzSortedMap(f, func() collections.SortedMap[int, int] { return treemap.New[int, int](cmp.Compare[int]) })
+}
+
+func TestMapClear(t *testing.T) {
+	testMap := treemap.New[int, string](cmp.Compare[int])
+	for i := 0; i < 1000; i++ {
+		testMap.Put(i, strconv.Itoa(i))
+	}
+	testMap.Clear()
+	if testMap.Size() != 0 || !testMap.Empty() {
+		t.Fatal("expected map to be empty after Clear")
+	}
+	for range testMap.Entries() {
+		t.Fatal("expected no entries after Clear")
+	}
+	testMap.Put(0, "0")
+	if testMap.Size() != 1 {
+		t.Fatalf("expected map size %d but got %d", 1, testMap.Size())
+	}
+}
+
+func TestMapContainsKey(t *testing.T) {
+	testMap := treemap.New[int, string](cmp.Compare[int])
+	for i := 0; i < 1000; i += 2 {
+		testMap.Put(i, strconv.Itoa(i))
+	}
+	for i := 0; i < 1000; i++ {
+		if testMap.ContainsKey(i) != (i%2 == 0) {
+			t.Fatalf("unexpected result from ContainsKey for %d", i)
+		}
+	}
+}
+
+func TestMapDelete(t *testing.T) {
+	testMap := treemap.New[int, string](cmp.Compare[int])
+	for i := 0; i < 1000; i++ {
+		testMap.Put(i, strconv.Itoa(i))
+	}
+	testMap.Delete(-1)
+	if testMap.Size() != 1000 {
+		t.Fatalf("expected map size %d after deleting missing key but got %d", 1000, testMap.Size())
+	}
+	for i := 0; i < 1000; i += 2 {
+		testMap.Delete(i)
+	}
+	if testMap.Size() != 500 {
+		t.Fatalf("expected map size %d but got %d", 500, testMap.Size())
+	}
+	for i := 0; i < 1000; i++ {
+		if testMap.ContainsKey(i) != (i%2 == 1) {
+			t.Fatalf("unexpected result from ContainsKey for %d", i)
+		}
+	}
+	for key, value := range testMap.Entries() {
+		if key%2 == 0 || value != strconv.Itoa(key) {
+			t.Fatalf("unexpected entry %d: %s", key, value)
+		}
+	}
+}
+
+func TestMapEmpty(t *testing.T) {
+	testMap := treemap.New[int, string](cmp.Compare[int])
+	if !testMap.Empty() {
+		t.Fatal("expected new map to be empty")
+	}
+	testMap.Put(0, "0")
+	if testMap.Empty() {
+		t.Fatal("expected map to not be empty after Put")
+	}
+}
+
+func TestMapGet(t *testing.T) {
+	testMap := treemap.New[int, string](cmp.Compare[int])
+	if _, err := testMap.Get(0); err == nil || !errors.Is(err, collections.ErrKeyNotFound) {
+		t.Fatalf("expected ErrKeyNotFound from Get on empty map but got: %v", err)
+	}
+
+	for i := 0; i < 1000; i++ {
+		testMap.Put(i, strconv.Itoa(i))
+	}
+	for i := 0; i < 1000; i++ {
+		if value, err := testMap.Get(i); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if value != strconv.Itoa(i) {
+			t.Fatalf("expected value %q but got %q", strconv.Itoa(i), value)
+		}
+	}
+	if _, err := testMap.Get(1000); err == nil || !errors.Is(err, collections.ErrKeyNotFound) {
+		t.Fatalf("expected ErrKeyNotFound from Get with missing key but got: %v", err)
+	}
+}
+
+func TestMapPut(t *testing.T) {
+	testMap := treemap.New[int, string](cmp.Compare[int])
+	for i := 0; i < 1000; i++ {
+		testMap.Put(i%100, strconv.Itoa(i))
+	}
+	if testMap.Size() != 100 {
+		t.Fatalf("expected map size %d but got %d", 100, testMap.Size())
+	}
+	for i := 0; i < 100; i++ {
+		if value, _ := testMap.Get(i); value != strconv.Itoa(i+900) {
+			t.Fatalf("expected value %q but got %q", strconv.Itoa(i+900), value)
+		}
+	}
+}
+
+func TestMapSize(t *testing.T) {
+	testMap := treemap.New[int, string](cmp.Compare[int])
+	for i := 1; i < 1001; i++ {
+		testMap.Put(i, strconv.Itoa(i))
+		if testMap.Size() != i {
+			t.Fatalf("expected map size %d but got %d", i, testMap.Size())
+		}
+	}
+}
+
+// newEvens returns a map of the even numbers from 0 to 198 to their string forms
+func newEvens() collections.SortedMap[int, string] {
+	testMap := treemap.New[int, string](cmp.Compare[int])
+	for _, i := range rand.Perm(100) {
+		testMap.Put(i*2, strconv.Itoa(i*2))
+	}
+
+	return testMap
+}
+
+func TestMapBackward(t *testing.T) {
+	testMap := newEvens()
+
+	expected := 198
+	for key, value := range testMap.Backward() {
+		if key != expected || value != strconv.Itoa(expected) {
+			t.Fatalf("expected entry %d but got %d: %q", expected, key, value)
+		}
+		expected -= 2
+	}
+	if expected != -2 {
+		t.Fatalf("expected %d entries from Backward but got %d", 100, (198-expected)/2)
+	}
+
+	for range testMap.Backward() {
+		break
+	}
+}
+
+func TestMapCeiling(t *testing.T) {
+	testMap := newEvens()
+	for i := -1; i < 199; i++ {
+		expected := i + i%2
+		if i < 0 {
+			expected = 0
+		}
+		if key, value, err := testMap.Ceiling(i); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if key != expected || value != strconv.Itoa(expected) {
+			t.Fatalf("expected ceiling of %d to be %d but got %d: %q", i, expected, key, value)
+		}
+	}
+	if _, _, err := testMap.Ceiling(199); err == nil || !errors.Is(err, collections.ErrNoSuchElement) {
+		t.Fatalf("expected ErrNoSuchElement but got: %v", err)
+	}
+}
+
+func TestMapDeleteOrdered(t *testing.T) {
+	testMap := treemap.New[int, string](cmp.Compare[int])
+	keys := rand.Perm(1000)
+	for _, key := range keys {
+		testMap.Put(key, strconv.Itoa(key))
+	}
+	deleted := make(map[int]bool)
+	for _, key := range keys[:500] {
+		testMap.Delete(key)
+		deleted[key] = true
+	}
+
+	previous := -1
+	for key, value := range testMap.Entries() {
+		if deleted[key] {
+			t.Fatalf("deleted key %d is still present", key)
+		} else if key <= previous || value != strconv.Itoa(key) {
+			t.Fatalf("unexpected entry %d: %q after key %d", key, value, previous)
+		}
+		previous = key
+	}
+	if testMap.Size() != 500 {
+		t.Fatalf("expected map size %d but got %d", 500, testMap.Size())
+	}
+}
+
+func TestMapFloor(t *testing.T) {
+	testMap := newEvens()
+	for i := 0; i < 200; i++ {
+		expected := i - i%2
+		if key, value, err := testMap.Floor(i); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if key != expected || value != strconv.Itoa(expected) {
+			t.Fatalf("expected floor of %d to be %d but got %d: %q", i, expected, key, value)
+		}
+	}
+	if _, _, err := testMap.Floor(-1); err == nil || !errors.Is(err, collections.ErrNoSuchElement) {
+		t.Fatalf("expected ErrNoSuchElement but got: %v", err)
+	}
+}
+
+func TestMapHigher(t *testing.T) {
+	testMap := newEvens()
+	for i := -1; i < 198; i++ {
+		expected := i + 2 - (i+2)%2
+		if i < 0 {
+			expected = 0
+		}
+		if key, _, err := testMap.Higher(i); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if key != expected {
+			t.Fatalf("expected higher of %d to be %d but got %d", i, expected, key)
+		}
+	}
+	if _, _, err := testMap.Higher(198); err == nil || !errors.Is(err, collections.ErrNoSuchElement) {
+		t.Fatalf("expected ErrNoSuchElement but got: %v", err)
+	}
+}
+
+func TestMapIteration(t *testing.T) {
+	testMap := newEvens()
+
+	expected := 0
+	for key, value := range testMap.Entries() {
+		if key != expected || value != strconv.Itoa(expected) {
+			t.Fatalf("expected entry %d but got %d: %q", expected, key, value)
+		}
+		expected += 2
+	}
+	expected = 0
+	for key := range testMap.Keys() {
+		if key != expected {
+			t.Fatalf("expected key %d but got %d", expected, key)
+		}
+		expected += 2
+	}
+	expected = 0
+	for value := range testMap.Values() {
+		if value != strconv.Itoa(expected) {
+			t.Fatalf("expected value %q but got %q", strconv.Itoa(expected), value)
+		}
+		expected += 2
+	}
+	if expected != 200 {
+		t.Fatalf("expected %d values but got %d", 100, expected/2)
+	}
+
+	for range testMap.Entries() {
+		break
+	}
+	for range testMap.Keys() {
+		break
+	}
+	for range testMap.Values() {
+		break
+	}
+}
+
+func TestMapLower(t *testing.T) {
+	testMap := newEvens()
+	for i := 1; i < 200; i++ {
+		expected := i - 1 - (i-1)%2
+		if key, _, err := testMap.Lower(i); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if key != expected {
+			t.Fatalf("expected lower of %d to be %d but got %d", i, expected, key)
+		}
+	}
+	if _, _, err := testMap.Lower(0); err == nil || !errors.Is(err, collections.ErrNoSuchElement) {
+		t.Fatalf("expected ErrNoSuchElement but got: %v", err)
+	}
+}
+
+func TestMapMinMax(t *testing.T) {
+	testMap := treemap.New[int, string](cmp.Compare[int])
+	if _, _, err := testMap.Min(); err == nil || !errors.Is(err, collections.ErrEmptyMap) {
+		t.Fatalf("expected ErrEmptyMap from Min on empty map but got: %v", err)
+	}
+	if _, _, err := testMap.Max(); err == nil || !errors.Is(err, collections.ErrEmptyMap) {
+		t.Fatalf("expected ErrEmptyMap from Max on empty map but got: %v", err)
+	}
+
+	testMap = newEvens()
+	if key, value, err := testMap.Min(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if key != 0 || value != "0" {
+		t.Fatalf("expected minimum %d but got %d: %q", 0, key, value)
+	}
+	if key, value, err := testMap.Max(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if key != 198 || value != "198" {
+		t.Fatalf("expected maximum %d but got %d: %q", 198, key, value)
+	}
+}
@@ -0,0 +1,208 @@
+// ©2022 Brandon Moller
+
+/*
+Package treeset is an implementation of [collections.SortedSet] backed by a balanced binary search tree.
+
+Elements are ordered by a compare function supplied when the set is created.
+The function must return a negative number when a sorts before b, a positive number when a sorts after b, and zero when they are equal, as [cmp.Compare] does.
+The tree is kept AVL-balanced, so Add, Contains, Remove and the nearest-element queries all run in O(log n) time.
+
+Each call to an Iterator looks up the successor of the last element returned, rather than holding a path through the tree.
+The same is true of a RemovingIterator, as Sets implement [collections.RemovingIterable], so removing the last element returned does not lose its place.
+*/
+package treeset
+
+import (
+	"iter"
+
+	"github.com/bmoller/collections"
+	"github.com/bmoller/collections/internal/avl"
+)
+
+type iterator[T comparable] struct {
+	last          T
+	modifications int
+	removable     bool
+	set           *set[T]
+	started       bool
+}
+
+func (i *iterator[T]) Next() (element T, err error) {
+	if i.set.modifications != i.modifications {
+		return element, collections.ErrConcurrentModification
+	} else if i.started {
+		element, err = i.set.Higher(i.last)
+	} else {
+		element, err = i.set.Min()
+	}
+	if err != nil {
+		return element, collections.ErrNoMoreItems
+	}
+	i.last, i.removable, i.started = element, true, true
+
+	return element, nil
+}
+
+func (i *iterator[T]) Remove() error {
+	if i.set.modifications != i.modifications {
+		return collections.ErrConcurrentModification
+	} else if !i.removable {
+		return collections.ErrNothingToRemove
+	}
+	i.set.Remove(i.last)
+	i.modifications, i.removable = i.set.modifications, false
+
+	return nil
+}
+
+type set[T comparable] struct {
+	compare       func(a, b T) int
+	modifications int
+	tree          *avl.Tree[T, struct{}]
+}
+
+/*
+New creates an empty SortedSet ordered by compare.
+*/
+func New[T comparable](compare func(a, b T) int) collections.SortedSet[T] {
+	return &set[T]{
+		compare: compare,
+		tree:    avl.New[T, struct{}](compare),
+	}
+}
+
+// nearest finds the closest element below (or above) item, returning item's own element if inclusive is true and it is present.
+func (s *set[T]) nearest(item T, inclusive, below bool) (T, error) {
+	element, _, ok := s.tree.Nearest(item, inclusive, below)
+	if !ok {
+		return element, collections.ErrNoSuchElement
+	}
+
+	return element, nil
+}
+
+func (s *set[T]) Add(item T) {
+	if s.tree.Put(item, struct{}{}) {
+		s.modifications++
+	}
+}
+
+func (s *set[T]) All() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		i := 0
+		s.tree.Ascend(func(element T, _ struct{}) bool {
+			if !yield(i, element) {
+				return false
+			}
+			i++
+			return true
+		})
+	}
+}
+
+func (s *set[T]) Backward() iter.Seq2[int, T] {
+	return func(yield func(int, T) bool) {
+		i := s.tree.Size() - 1
+		s.tree.Descend(func(element T, _ struct{}) bool {
+			if !yield(i, element) {
+				return false
+			}
+			i--
+			return true
+		})
+	}
+}
+
+func (s *set[T]) Ceiling(item T) (T, error) {
+	return s.nearest(item, true, false)
+}
+
+func (s *set[T]) Contains(item T) bool {
+	_, ok := s.tree.Get(item)
+
+	return ok
+}
+
+func (s *set[T]) Empty() bool {
+	return s.tree.Size() == 0
+}
+
+func (s *set[T]) Floor(item T) (T, error) {
+	return s.nearest(item, true, true)
+}
+
+func (s *set[T]) Higher(item T) (T, error) {
+	return s.nearest(item, false, false)
+}
+
+func (s *set[T]) Iterator() collections.Iterator[T] {
+	return s.RemovingIterator().Next
+}
+
+func (s *set[T]) Lower(item T) (T, error) {
+	return s.nearest(item, false, true)
+}
+
+func (s *set[T]) Max() (T, error) {
+	element, _, ok := s.tree.Max()
+	if !ok {
+		return element, collections.ErrEmptySet
+	}
+
+	return element, nil
+}
+
+func (s *set[T]) Min() (T, error) {
+	element, _, ok := s.tree.Min()
+	if !ok {
+		return element, collections.ErrEmptySet
+	}
+
+	return element, nil
+}
+
+func (s *set[T]) Pop() (element T, err error) {
+	if element, err = s.Min(); err == nil {
+		s.Remove(element)
+	}
+
+	return element, err
+}
+
+func (s *set[T]) Range(from, to T) collections.SortedSet[T] {
+	result := &set[T]{
+		compare: s.compare,
+		tree:    avl.New[T, struct{}](s.compare),
+	}
+	s.tree.AscendRange(from, to, func(element T, _ struct{}) bool {
+		result.tree.Put(element, struct{}{})
+		return true
+	})
+
+	return result
+}
+
+func (s *set[T]) Remove(item T) {
+	if s.tree.Delete(item) {
+		s.modifications++
+	}
+}
+
+func (s *set[T]) RemovingIterator() collections.RemovingIterator[T] {
+	return &iterator[T]{
+		modifications: s.modifications,
+		set:           s,
+	}
+}
+
+func (s *set[T]) Size() int {
+	return s.tree.Size()
+}
+
+func (s *set[T]) Values() iter.Seq[T] {
+	return func(yield func(T) bool) {
+		s.tree.Ascend(func(element T, _ struct{}) bool {
+			return yield(element)
+		})
+	}
+}
@@ -0,0 +1,327 @@
+// ©2022 Brandon Moller
+
+package treeset_test
+
+import (
+	"cmp"
+	"errors"
+	"math/rand"
+	"os"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/bmoller/collections"
+	"github.com/bmoller/collections/collectionstest"
+	"github.com/bmoller/collections/mapset"
+	"github.com/bmoller/collections/treeset"
+)
+
+func TestMain(m *testing.M) {
+	rand.Seed(time.Now().UTC().UnixNano())
+	os.Exit(m.Run())
+}
+
+// newEvens returns a set of the even numbers from 0 to 198
+func newEvens() collections.SortedSet[int] {
+	testSet := treeset.New(cmp.Compare[int])
+	for _, i := range rand.Perm(100) {
+		testSet.Add(i * 2)
+	}
+
+	return testSet
+}
+
+func FuzzSet(f *testing.F) {
+	collectionstest.FuzzSortedSet(f, func() collections.SortedSet[int] { return treeset.New(cmp.Compare[int]) })
+}
+
+func TestSet(t *testing.T) {
+	collectionstest.TestSet(t, func() collections.Set[int] { return treeset.New(cmp.Compare[int]) })
+}
+
+func TestSetAdd(t *testing.T) {
+	testSet := treeset.New(cmp.Compare[int])
+	for i := 0; i < 1000; i++ {
+		testSet.Add(rand.Intn(100))
+	}
+	if testSet.Size() != 100 {
+		t.Fatalf("expected size %d but got %d", 100, testSet.Size())
+	}
+}
+
+func TestSetAll(t *testing.T) {
+	testSet := newEvens()
+
+	expected := 0
+	for i, element := range testSet.All() {
+		if i != expected {
+			t.Fatalf("expected index %d but got %d", expected, i)
+		} else if element != i*2 {
+			t.Fatalf("expected element with value %d but got %d", i*2, element)
+		}
+		expected++
+	}
+	if expected != 100 {
+		t.Fatalf("expected %d elements from All but got %d", 100, expected)
+	}
+
+	for i := range testSet.All() {
+		if i == 10 {
+			break
+		}
+	}
+}
+
+func TestSetBackward(t *testing.T) {
+	testSet := newEvens()
+
+	expected := 99
+	for i, element := range testSet.Backward() {
+		if i != expected {
+			t.Fatalf("expected index %d but got %d", expected, i)
+		} else if element != i*2 {
+			t.Fatalf("expected element with value %d but got %d", i*2, element)
+		}
+		expected--
+	}
+	if expected != -1 {
+		t.Fatalf("expected %d elements from Backward but got %d", 100, 99-expected)
+	}
+
+	for i := range testSet.Backward() {
+		if i == 90 {
+			break
+		}
+	}
+}
+
+func TestSetCeiling(t *testing.T) {
+	testSet := newEvens()
+	for i := -1; i < 199; i++ {
+		expected := i + i%2
+		if i < 0 {
+			expected = 0
+		}
+		if element, err := testSet.Ceiling(i); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if element != expected {
+			t.Fatalf("expected ceiling of %d to be %d but got %d", i, expected, element)
+		}
+	}
+	if _, err := testSet.Ceiling(199); err == nil || !errors.Is(err, collections.ErrNoSuchElement) {
+		t.Fatalf("expected ErrNoSuchElement but got: %v", err)
+	}
+}
+
+func TestSetContains(t *testing.T) {
+	testSet := newEvens()
+	for i := -1; i < 200; i++ {
+		if testSet.Contains(i) != (i >= 0 && i%2 == 0) {
+			t.Fatalf("unexpected result from Contains for %d", i)
+		}
+	}
+}
+
+func TestSetFloor(t *testing.T) {
+	testSet := newEvens()
+	for i := 0; i < 200; i++ {
+		expected := i - i%2
+		if element, err := testSet.Floor(i); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if element != expected {
+			t.Fatalf("expected floor of %d to be %d but got %d", i, expected, element)
+		}
+	}
+	if _, err := testSet.Floor(-1); err == nil || !errors.Is(err, collections.ErrNoSuchElement) {
+		t.Fatalf("expected ErrNoSuchElement but got: %v", err)
+	}
+}
+
+func TestSetHigher(t *testing.T) {
+	testSet := newEvens()
+	for i := -1; i < 198; i++ {
+		expected := i + 2 - (i+2)%2
+		if i < 0 {
+			expected = 0
+		}
+		if element, err := testSet.Higher(i); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if element != expected {
+			t.Fatalf("expected higher of %d to be %d but got %d", i, expected, element)
+		}
+	}
+	if _, err := testSet.Higher(198); err == nil || !errors.Is(err, collections.ErrNoSuchElement) {
+		t.Fatalf("expected ErrNoSuchElement but got: %v", err)
+	}
+}
+
+func TestSetIterator(t *testing.T) {
+	testSet := treeset.New(cmp.Compare[int])
+	if _, err := testSet.Iterator()(); err == nil || !errors.Is(err, collections.ErrNoMoreItems) {
+		t.Fatalf("expected ErrNoMoreItems from Iterator on empty set but got: %v", err)
+	}
+
+	testSet = newEvens()
+	itr := testSet.Iterator()
+	for i := 0; i < 100; i++ {
+		if element, err := itr(); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if element != i*2 {
+			t.Fatalf("expected element with value %d but got %d", i*2, element)
+		}
+		// neither call changes the elements, so the iterator remains valid
+		testSet.Add(i * 2)
+		testSet.Remove(i*2 + 1)
+	}
+	if _, err := itr(); err == nil || !errors.Is(err, collections.ErrNoMoreItems) {
+		t.Fatalf("exhausted iterator should return ErrNoMoreItems but got %v", err)
+	}
+
+	itr = testSet.Iterator()
+	itr()
+	testSet.Remove(2)
+	if _, err := itr(); err == nil || !errors.Is(err, collections.ErrConcurrentModification) {
+		t.Fatalf("expected ErrConcurrentModification after Remove but got: %v", err)
+	}
+}
+
+func TestSetLower(t *testing.T) {
+	testSet := newEvens()
+	for i := 1; i < 200; i++ {
+		expected := i - 1 - (i-1)%2
+		if element, err := testSet.Lower(i); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if element != expected {
+			t.Fatalf("expected lower of %d to be %d but got %d", i, expected, element)
+		}
+	}
+	if _, err := testSet.Lower(0); err == nil || !errors.Is(err, collections.ErrNoSuchElement) {
+		t.Fatalf("expected ErrNoSuchElement but got: %v", err)
+	}
+}
+
+func TestSetMinMax(t *testing.T) {
+	testSet := treeset.New(cmp.Compare[int])
+	if _, err := testSet.Min(); err == nil || !errors.Is(err, collections.ErrEmptySet) {
+		t.Fatalf("expected ErrEmptySet from Min on empty set but got: %v", err)
+	}
+	if _, err := testSet.Max(); err == nil || !errors.Is(err, collections.ErrEmptySet) {
+		t.Fatalf("expected ErrEmptySet from Max on empty set but got: %v", err)
+	}
+
+	testSet = newEvens()
+	if element, err := testSet.Min(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if element != 0 {
+		t.Fatalf("expected minimum %d but got %d", 0, element)
+	}
+	if element, err := testSet.Max(); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	} else if element != 198 {
+		t.Fatalf("expected maximum %d but got %d", 198, element)
+	}
+}
+
+func TestSetPop(t *testing.T) {
+	testSet := newEvens()
+	for i := 0; i < 100; i++ {
+		if element, err := testSet.Pop(); err != nil {
+			t.Fatalf("unexpected error: %s", err)
+		} else if element != i*2 {
+			t.Fatalf("expected element with value %d but got %d", i*2, element)
+		}
+	}
+	if _, err := testSet.Pop(); err == nil || !errors.Is(err, collections.ErrEmptySet) {
+		t.Fatalf("expected ErrEmptySet from Pop on empty set but got: %v", err)
+	}
+}
+
+func TestSetRange(t *testing.T) {
+	testSet := newEvens()
+
+	subset := testSet.Range(11, 51)
+	if subset.Size() != 20 {
+		t.Fatalf("expected range size %d but got %d", 20, subset.Size())
+	}
+	expected := 12
+	for element := range subset.Values() {
+		if element != expected {
+			t.Fatalf("expected element with value %d but got %d", expected, element)
+		}
+		expected += 2
+	}
+
+	if subset := testSet.Range(10, 50); subset.Size() != 20 || !subset.Contains(10) || subset.Contains(50) {
+		t.Fatal("expected range to include its start and exclude its end")
+	}
+	if subset := testSet.Range(50, 10); !subset.Empty() {
+		t.Fatal("expected empty range when end is less than start")
+	}
+
+	subset.Add(1)
+	if testSet.Contains(1) {
+		t.Fatal("expected range to be a copy of the original set")
+	}
+}
+
+func TestSetRemove(t *testing.T) {
+	testSet := treeset.New(cmp.Compare[int])
+	values := rand.Perm(1000)
+	for _, value := range values {
+		testSet.Add(value)
+	}
+	testSet.Remove(-1)
+	if testSet.Size() != 1000 {
+		t.Fatalf("expected size %d after removing non-member but got %d", 1000, testSet.Size())
+	}
+
+	for i, value := range values[:500] {
+		testSet.Remove(value)
+		if testSet.Contains(value) {
+			t.Fatalf("set contains removed element %d", value)
+		} else if testSet.Size() != 999-i {
+			t.Fatalf("expected size %d but got %d", 999-i, testSet.Size())
+		}
+	}
+
+	remaining := slices.Clone(values[500:])
+	slices.Sort(remaining)
+	if result := slices.Collect(testSet.Values()); !slices.Equal(result, remaining) {
+		t.Fatal("remaining elements are not in ascending order")
+	}
+}
+
+func TestSetSetOperations(t *testing.T) {
+	a := newEvens()
+	b := mapset.New[int]()
+	for i := 0; i < 200; i++ {
+		b.Add(i)
+	}
+
+	if !mapset.IsSubset(a, b) {
+		t.Fatal("expected a to be subset of b")
+	}
+	if c := mapset.Intersection(b, a); c.Size() != 100 {
+		t.Fatalf("expected intersection size %d but got %d", 100, c.Size())
+	}
+}
+
+func TestSetValues(t *testing.T) {
+	testSet := treeset.New(cmp.Compare[int])
+	values := rand.Perm(1000)
+	for _, value := range values {
+		testSet.Add(value)
+	}
+
+	expected := 0
+	for element := range testSet.Values() {
+		if element != expected {
+			t.Fatalf("expected element with value %d but got %d", expected, element)
+		}
+		expected++
+	}
+	if expected != 1000 {
+		t.Fatalf("expected %d elements from Values but got %d", 1000, expected)
+	}
+}
@@ -0,0 +1,87 @@
+// ©2022 Brandon Moller
+
+/*
+Package unboundedchan provides a channel pair with no fixed capacity, so sending never blocks for long regardless of how far the receiver falls behind.
+
+Elements sent on In are received by a goroutine that stores any backlog in a linkedqueue and sends them on Out in the order in which they were sent.
+Once In is closed, the remaining elements are still delivered on Out, which is closed after the last of them has been received.
+The goroutine exits only after Out is closed, so the receiver must keep receiving until then or the goroutine and its backlog are never released.
+*/
+package unboundedchan
+
+import (
+	"sync/atomic"
+
+	"github.com/bmoller/collections/linkedqueue"
+)
+
+/*
+A Chan connects a sender on In to a receiver on Out with an unbounded buffer in between.
+Len reports the number of elements that have been sent but not yet received, which is useful for monitoring a backlog.
+*/
+type Chan[T comparable] interface {
+	In() chan<- T
+	Len() int
+	Out() <-chan T
+}
+
+type unbounded[T comparable] struct {
+	in  chan T
+	len atomic.Int64
+	out chan T
+}
+
+func New[T comparable]() Chan[T] {
+	c := &unbounded[T]{
+		in:  make(chan T),
+		out: make(chan T),
+	}
+	go c.run()
+
+	return c
+}
+
+func (c *unbounded[T]) run() {
+	defer close(c.out)
+
+	buffer := linkedqueue.New[T]()
+	in := c.in
+	for in != nil || !buffer.Empty() {
+		if buffer.Empty() {
+			element, ok := <-in
+			if !ok {
+				return
+			}
+			buffer.Push(element)
+			c.len.Add(1)
+			continue
+		}
+
+		// a nil channel is never ready, so once In is closed only Out is selected
+		front, _ := buffer.Peek()
+		select {
+		case element, ok := <-in:
+			if !ok {
+				in = nil
+				continue
+			}
+			buffer.Push(element)
+			c.len.Add(1)
+		case c.out <- front:
+			buffer.Pop()
+			c.len.Add(-1)
+		}
+	}
+}
+
+func (c *unbounded[T]) In() chan<- T {
+	return c.in
+}
+
+func (c *unbounded[T]) Len() int {
+	return int(c.len.Load())
+}
+
+func (c *unbounded[T]) Out() <-chan T {
+	return c.out
+}
@@ -0,0 +1,155 @@
+// ©2022 Brandon Moller
+
+package unboundedchan_test
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/bmoller/collections/unboundedchan"
+)
+
+// FuzzChan treats each byte of an input as a send of its value if it is even and a receive otherwise, and checks that elements arrive in the order in which they were sent.
+// A receive is skipped if nothing is waiting, since it would block forever; whatever remains is received after In is closed.
+func FuzzChan(f *testing.F) {
+	f.Add([]byte{})
+	f.Add([]byte{0, 2, 1, 4, 1, 1, 6, 8, 10, 1})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		c := unboundedchan.New[int]()
+		var model []int
+		for _, b := range data[:min(len(data), 1000)] {
+			if b%2 == 0 {
+				c.In() <- int(b)
+				model = append(model, int(b))
+			} else if len(model) > 0 {
+				if element := <-c.Out(); element != model[0] {
+					t.Fatalf("expected element with value %d but got %d", model[0], element)
+				}
+				model = model[1:]
+			}
+		}
+		waitForLen(t, c, len(model))
+
+		close(c.In())
+		for element := range c.Out() {
+			if len(model) == 0 {
+				t.Fatalf("expected Out to be closed but got element with value %d", element)
+			} else if element != model[0] {
+				t.Fatalf("expected element with value %d but got %d", model[0], element)
+			}
+			model = model[1:]
+		}
+		if len(model) != 0 {
+			t.Fatalf("expected %d more elements before Out was closed", len(model))
+		}
+	})
+}
+
+func TestChanClose(t *testing.T) {
+	c := unboundedchan.New[int]()
+	for i := 0; i < 1000; i++ {
+		c.In() <- i
+	}
+	close(c.In())
+
+	expected := 0
+	for element := range c.Out() {
+		if element != expected {
+			t.Fatalf("expected element with value %d but got %d", expected, element)
+		}
+		expected++
+	}
+	if expected != 1000 {
+		t.Fatalf("expected %d elements before Out was closed but got %d", 1000, expected)
+	}
+
+	empty := unboundedchan.New[int]()
+	close(empty.In())
+	if _, ok := <-empty.Out(); ok {
+		t.Fatal("expected Out to be closed after closing In with no backlog")
+	}
+}
+
+func TestChanLen(t *testing.T) {
+	c := unboundedchan.New[int]()
+	if c.Len() != 0 {
+		t.Fatalf("expected new Chan to have length %d but got %d", 0, c.Len())
+	}
+
+	for i := 0; i < 1000; i++ {
+		c.In() <- i
+	}
+	waitForLen(t, c, 1000)
+
+	for i := 0; i < 500; i++ {
+		<-c.Out()
+	}
+	waitForLen(t, c, 500)
+}
+
+// waitForLen allows for the last send or receive completing before the length is updated.
+func waitForLen(t *testing.T, c unboundedchan.Chan[int], expected int) {
+	for deadline := time.Now().Add(time.Second); c.Len() != expected && time.Now().Before(deadline); {
+		time.Sleep(time.Millisecond)
+	}
+	if c.Len() != expected {
+		t.Fatalf("expected length %d but got %d", expected, c.Len())
+	}
+}
+
+func TestChanOut(t *testing.T) {
+	c := unboundedchan.New[int]()
+	for i := 0; i < 1000; i++ {
+		c.In() <- i
+		if i%2 == 1 {
+			if element := <-c.Out(); element != i/2 {
+				t.Fatalf("expected element with value %d but got %d", i/2, element)
+			}
+		}
+	}
+	for i := 500; i < 1000; i++ {
+		if element := <-c.Out(); element != i {
+			t.Fatalf("expected element with value %d but got %d", i, element)
+		}
+	}
+}
+
+func TestChanConcurrent(t *testing.T) {
+	const (
+		producers   = 8
+		perProducer = 1000
+	)
+	c := unboundedchan.New[int]()
+
+	var wg sync.WaitGroup
+	for p := 0; p < producers; p++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < perProducer; i++ {
+				c.In() <- p*perProducer + i
+			}
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(c.In())
+	}()
+
+	// each producer's elements must arrive in the order they were sent
+	var next [producers]int
+	for element := range c.Out() {
+		producer := element / perProducer
+		if element%perProducer != next[producer] {
+			t.Fatalf("expected element %d from producer %d but got %d", next[producer], producer, element%perProducer)
+		}
+		next[producer]++
+		c.Len()
+	}
+	for producer, count := range next {
+		if count != perProducer {
+			t.Fatalf("expected %d elements from producer %d but got %d", perProducer, producer, count)
+		}
+	}
+}